@@ -4,6 +4,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"os"
 	"strings"
 
 	sudogo "github.com/ClickerMonkey/sudogo/pkg"
@@ -24,6 +25,8 @@ func main() {
 		}
 		chosenType   string             = "medium"
 		chosenLimits *sudogo.ClearLimit = types[chosenType]
+		rules        []*sudogo.Rule
+		even, odd    []sudogo.Position
 	)
 
 	flag.Func("type", "One of beginner, easy, medium, hard, or custom.", func(value string) error {
@@ -35,6 +38,25 @@ func main() {
 		return nil
 	})
 
+	flag.Func("rules", "Comma separated rules to add: anti-knight, anti-king, non-consecutive, or disjoint-groups.", func(value string) error {
+		for _, name := range strings.Split(value, ",") {
+			rule := sudogo.RuleNamed(name)
+			if rule == nil {
+				return fmt.Errorf("%q is not one of anti-knight, anti-king, non-consecutive, or disjoint-groups", name)
+			}
+			rules = append(rules, rule)
+		}
+		return nil
+	})
+	flag.Func("even", "Comma separated cells like r1c2 which are shaded even.", func(value string) (err error) {
+		even, err = parseCells(value)
+		return err
+	})
+	flag.Func("odd", "Comma separated cells like r1c2 which are shaded odd.", func(value string) (err error) {
+		odd, err = parseCells(value)
+		return err
+	})
+
 	boxWidth := flag.Int("boxWidth", 3, "The width of a box.")
 	boxHeight := flag.Int("boxHeight", 3, "The height of a box.")
 	symmetric := flag.Bool("symmetric", true, "If the easier puzzles should be symmetric.")
@@ -46,6 +68,10 @@ func main() {
 	maxPlacements := flag.Int("maxPlacements", -1, "Override the maxPlacements value for generation.")
 	maxLogs := flag.Int("maxLogs", -1, "Override the maxLogs value for generation.")
 	maxBatches := flag.Int("maxBatches", -1, "Override the maxBatches value for generation.")
+	maxChainLength := flag.Int("maxChainLength", -1, "Override the maximum number of links in a chain for generation.")
+	maxForcingDepth := flag.Int("maxForcingDepth", -1, "Override the maximum number of implications from a forcing chain assumption for generation.")
+	maxForcingNodes := flag.Int("maxForcingNodes", -1, "Override the maximum number of candidates a forcing chain assumption can decide for generation.")
+	maxTemplateNodes := flag.Int("maxTemplateNodes", -1, "Override the maximum number of template states a digit can have for generation.")
 	candidates := flag.Bool("candidates", false, "If the candidate puzzles should be printed.")
 	solutions := flag.Bool("solutions", false, "If the solutions should be printed as well.")
 	logSteps := flag.Bool("steps", false, "If the soluton steps should be logged.")
@@ -53,13 +79,19 @@ func main() {
 
 	flag.Parse()
 
-	kind := sudogo.Kind{
-		BoxSize: sudogo.Size{
-			Width:  *boxWidth,
-			Height: *boxHeight,
-		},
+	size := *boxWidth * *boxHeight
+	for _, cells := range [][]sudogo.Position{even, odd} {
+		if err := checkCells(cells, size); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+	if len(even) > 0 || len(odd) > 0 {
+		rules = append(rules, sudogo.RuleEvenOdd(even, odd))
 	}
 
+	kind := sudogo.NewKind(*boxWidth, *boxHeight).WithRules(rules...)
+
 	typeScale := float64(kind.Area()) / 81.0
 
 	if *clearDepth != 64 {
@@ -95,6 +127,18 @@ func main() {
 	} else {
 		chosenLimits.MaxBatches = int(float64(chosenLimits.MaxBatches) * typeScale)
 	}
+	if *maxChainLength != -1 {
+		chosenLimits.MaxChainLength = *maxChainLength
+	}
+	if *maxForcingDepth != -1 {
+		chosenLimits.MaxForcingDepth = *maxForcingDepth
+	}
+	if *maxForcingNodes != -1 {
+		chosenLimits.MaxForcingNodes = *maxForcingNodes
+	}
+	if *maxTemplateNodes != -1 {
+		chosenLimits.MaxTemplateNodes = *maxTemplateNodes
+	}
 
 	pdfMode := *outputPdf != ""
 	pdf := sudogo.NewPDF()
@@ -168,3 +212,26 @@ func handleConsoleOutput(puzzleIndex int, puzzle *sudogo.Puzzle, solution *sudog
 		}
 	}
 }
+
+// Parses cells like "r1c2,r3c4" where rows and columns start at 1.
+func parseCells(value string) ([]sudogo.Position, error) {
+	cells := []sudogo.Position{}
+	for _, cell := range strings.Split(value, ",") {
+		var row, col int
+		if _, err := fmt.Sscanf(strings.ToLower(strings.TrimSpace(cell)), "r%dc%d", &row, &col); err != nil || row < 1 || col < 1 {
+			return nil, fmt.Errorf("%q is not a cell like r1c2", cell)
+		}
+		cells = append(cells, sudogo.Position{Col: col - 1, Row: row - 1})
+	}
+	return cells, nil
+}
+
+// Returns an error if any of the cells are outside a puzzle with the given size.
+func checkCells(cells []sudogo.Position, size int) error {
+	for _, cell := range cells {
+		if cell.Row >= size || cell.Col >= size {
+			return fmt.Errorf("r%dc%d is outside of the %dx%d puzzle", cell.Row+1, cell.Col+1, size, size)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,865 @@
+package sudogo
+
+import (
+	mathBits "math/bits"
+)
+
+// The maximum number of almost locked sets in an ALS chain.
+const alsChainMaxLength = 5
+
+// The maximum number of partial chains an ALS chain step searches, since there can be hundreds of sets with
+// dozens of links each when few candidates have been removed.
+const alsChainMaxNodes = 100000
+
+// The maximum number of cells in an almost locked set.
+const alsMaxCells = 8
+
+// An almost locked set: cells in a house with one more candidate than there are cells. If any candidate
+// is removed from the set the remaining candidates are locked in the cells.
+type almostLockedSet struct {
+	cells      []*Cell
+	candidates Candidates
+	dist       candidateDistribution
+	// The cells in the set by id, 64 ids per value.
+	ids []uint64
+	// The cells by id with each candidate (by index) which see every cell in the set with it, found when first
+	// needed since most sets are only linked on a few of their candidates.
+	seen [][]uint64
+	grid *alsGrid
+}
+
+// The cells by id each unsolved cell sees and the unsolved cells with each candidate (by index), 64 ids per
+// value, shared by the almost locked sets found together.
+type alsGrid struct {
+	peers      [][]uint64
+	candidates [][]uint64
+}
+
+func newALSGrid(solver *Solver) *alsGrid {
+	words := (len(solver.Puzzle.Cells) + 63) / 64
+	size := solver.Puzzle.Kind.Size()
+	grid := &alsGrid{
+		peers:      make([][]uint64, len(solver.Puzzle.Cells)),
+		candidates: make([][]uint64, size),
+	}
+	for i := range grid.candidates {
+		grid.candidates[i] = make([]uint64, words)
+	}
+	for _, cell := range solver.Unsolved {
+		grid.peers[cell.Id] = make([]uint64, words)
+		for candidate := cell.candidates.First(); candidate <= cell.candidates.Last(); candidate++ {
+			if cell.HasCandidate(candidate) {
+				grid.candidates[candidate-1][cell.Id/64] |= 1 << (cell.Id % 64)
+			}
+		}
+	}
+
+	houses := make([][]*Cell, 0, size*3+len(solver.Houses))
+	houses = append(houses, solver.Cols...)
+	houses = append(houses, solver.Rows...)
+	houses = append(houses, solver.Boxs...)
+	houses = append(houses, solver.Houses...)
+	ids := make([]uint64, words)
+	for _, house := range houses {
+		for i := range ids {
+			ids[i] = 0
+		}
+		for _, cell := range house {
+			ids[cell.Id/64] |= 1 << (cell.Id % 64)
+		}
+		for _, cell := range house {
+			for i, id := range ids {
+				grid.peers[cell.Id][i] |= id
+			}
+		}
+	}
+	for _, cell := range solver.Unsolved {
+		for _, link := range cell.Links {
+			if solver.Puzzle.Cells[link].Empty() {
+				grid.peers[cell.Id][link/64] |= 1 << (link % 64)
+			}
+		}
+		grid.peers[cell.Id][cell.Id/64] &^= 1 << (cell.Id % 64)
+	}
+	return grid
+}
+
+func newAlmostLockedSet(solver *Solver, grid *alsGrid, cells []*Cell, candidates Candidates) *almostLockedSet {
+	als := &almostLockedSet{
+		cells:      sliceClone(cells),
+		candidates: candidates,
+		dist:       newDistribution(solver.Puzzle.Kind.Size()),
+		ids:        make([]uint64, (len(solver.Puzzle.Cells)+63)/64),
+		seen:       make([][]uint64, solver.Puzzle.Kind.Size()),
+		grid:       grid,
+	}
+	als.dist.reset(als.cells)
+	for _, cell := range cells {
+		als.ids[cell.Id/64] |= 1 << (cell.Id % 64)
+	}
+	return als
+}
+
+// The cells in the set which have the candidate.
+func (als *almostLockedSet) holders(candidate int) []*Cell {
+	holders := als.dist.candidates[candidate-1]
+	return holders.cells[:holders.size]
+}
+
+// The candidates in the cells of the sets, the pattern of a deduction made with them.
+func getAlmostLockedSetPattern(sets []*almostLockedSet) []candidateNode {
+	pattern := make([]candidateNode, 0)
+	for _, als := range sets {
+		pattern = append(pattern, getCandidateNodes(als.cells, als.candidates)...)
+	}
+	return pattern
+}
+
+func (als *almostLockedSet) contains(cell *Cell) bool {
+	return als.ids[cell.Id/64]&(1<<(cell.Id%64)) != 0
+}
+
+func (als *almostLockedSet) overlaps(other *almostLockedSet) bool {
+	for i, ids := range als.ids {
+		if ids&other.ids[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns whether every cell with the candidate in the set sees every given cell, which have the candidate.
+func (als *almostLockedSet) allSee(candidate int, cells []*Cell) bool {
+	seen := als.seenByAll(candidate)
+	for _, cell := range cells {
+		if seen[cell.Id/64]&(1<<(cell.Id%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns whether any cell sees every cell with the candidate in both sets.
+func (als *almostLockedSet) seesWith(other *almostLockedSet, candidate int) bool {
+	seen := other.seenByAll(candidate)
+	for i, ids := range als.seenByAll(candidate) {
+		if ids&seen[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the unsolved cells by id with the candidate which see every cell in the set with it.
+func (als *almostLockedSet) seenByAll(candidate int) []uint64 {
+	if als.seen[candidate-1] == nil {
+		seen := sliceClone(als.grid.candidates[candidate-1])
+		for _, holder := range als.holders(candidate) {
+			for i, peers := range als.grid.peers[holder.Id] {
+				seen[i] &= peers
+			}
+		}
+		als.seen[candidate-1] = seen
+	}
+	return als.seen[candidate-1]
+}
+
+// Returns every almost locked set in the columns, rows, and boxes. A cell with two candidates is an
+// almost locked set of one cell. Sets have at most alsMaxCells cells, and a set is skipped once its
+// candidates can no longer be almost locked with that many cells.
+func getAlmostLockedSets(solver *Solver) []*almostLockedSet {
+	size := solver.Puzzle.Kind.Size()
+	sets := make([]*almostLockedSet, 0)
+	grid := newALSGrid(solver)
+
+	for _, group := range []Group{GroupCol, GroupRow, GroupBox} {
+		for index := 0; index < size; index++ {
+			house := solver.GroupAt(group, index)
+			cells := make([]*Cell, 0, len(house))
+			maxCells := Min(len(house)-1, alsMaxCells)
+
+			var chooseCell func(start int, candidates Candidates)
+			chooseCell = func(start int, candidates Candidates) {
+				if len(cells) > 0 && candidates.Count == len(cells)+1 && !alsInEarlierGroup(group, cells) {
+					sets = append(sets, newAlmostLockedSet(solver, grid, cells, candidates))
+				}
+				if len(cells) == maxCells {
+					return
+				}
+				for i := start; i < len(house); i++ {
+					next := candidates
+					next.Or(house[i].candidates)
+					if next.Count > maxCells+1 {
+						continue
+					}
+					cells = append(cells, house[i])
+					chooseCell(i+1, next)
+					cells = sliceRemoveLast(cells)
+				}
+			}
+
+			chooseCell(0, Candidates{})
+		}
+	}
+
+	return sets
+}
+
+// Returns whether the cells were already found as a set in a group searched before the given one.
+// Columns are searched first, then rows, then boxes.
+func alsInEarlierGroup(group Group, cells []*Cell) bool {
+	sameRow, sameCol := true, true
+	for _, cell := range cells[1:] {
+		sameRow = sameRow && cell.Row == cells[0].Row
+		sameCol = sameCol && cell.Col == cells[0].Col
+	}
+	switch group {
+	case GroupRow:
+		return sameCol
+	case GroupBox:
+		return sameRow || sameCol
+	}
+	return false
+}
+
+// A restricted common candidate between two almost locked sets: every cell with the candidate in
+// one set sees every cell with the candidate in the other, so only one of the sets can have it.
+type alsLink struct {
+	other     int
+	candidate int
+}
+
+// Returns the restricted common candidates for each almost locked set (by index) with every other set
+// that it doesn't overlap.
+func getAlmostLockedSetLinks(sets []*almostLockedSet) [][]alsLink {
+	links := make([][]alsLink, len(sets))
+	for i, a := range sets {
+		for j := i + 1; j < len(sets); j++ {
+			b := sets[j]
+			if !a.candidates.Overlaps(b.candidates) || a.overlaps(b) {
+				continue
+			}
+			common := a.candidates
+			common.And(b.candidates)
+			for candidate := common.First(); candidate <= common.Last(); candidate++ {
+				if common.Has(candidate) && a.allSee(candidate, b.holders(candidate)) {
+					links[i] = append(links[i], alsLink{j, candidate})
+					links[j] = append(links[j], alsLink{i, candidate})
+				}
+			}
+		}
+	}
+	return links
+}
+
+// Appends the eliminations for a candidate which must be in one of the given sets: it can be removed from
+// every cell that sees all of the cells with the candidate in the sets and still has it.
+func getAlmostLockedSetEliminations(solver *Solver, candidate int, sets []*almostLockedSet, eliminations []candidateNode) []candidateNode {
+	for i := range sets[0].ids {
+		seen := sets[0].seenByAll(candidate)[i]
+		for _, als := range sets[1:] {
+			seen &= als.seenByAll(candidate)[i]
+		}
+		for seen != 0 {
+			cell := &solver.Puzzle.Cells[i*64+mathBits.TrailingZeros64(seen)]
+			if cell.HasCandidate(candidate) {
+				eliminations = append(eliminations, candidateNode{cell, candidate})
+			}
+			seen &= seen - 1
+		}
+	}
+	return eliminations
+}
+
+// The almost locked sets and links last found by a solver, reused by the next almost locked set step while
+// no cell has changed since.
+type alsCache struct {
+	cells      []Cell
+	values     []int
+	candidates []Candidates
+	sets       []*almostLockedSet
+	links      [][]alsLink
+}
+
+// Returns the almost locked sets and the links between them, reusing the ones last found when the
+// puzzle hasn't changed since.
+func getAlmostLockedSetsAndLinks(solver *Solver) ([]*almostLockedSet, [][]alsLink) {
+	cells := solver.Puzzle.Cells
+	cache := solver.alsCache
+	if cache == nil || len(cache.cells) != len(cells) || &cache.cells[0] != &cells[0] {
+		cache = &alsCache{
+			cells:      cells,
+			values:     make([]int, len(cells)),
+			candidates: make([]Candidates, len(cells)),
+		}
+		solver.alsCache = cache
+	} else if cache.matches() {
+		return cache.sets, cache.links
+	}
+	for i := range cells {
+		cache.values[i] = cells[i].Value
+		cache.candidates[i] = cells[i].candidates
+	}
+	cache.sets = getAlmostLockedSets(solver)
+	cache.links = getAlmostLockedSetLinks(cache.sets)
+	return cache.sets, cache.links
+}
+
+// Returns whether every cell has the same value and candidates as when the sets were found.
+func (cache *alsCache) matches() bool {
+	for i := range cache.cells {
+		cell := &cache.cells[i]
+		if cell.Value != cache.values[i] || !cell.candidates.Equals(cache.candidates[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Sets the cells seen in both given masks into the target, allocating it when needed, and returns whether
+// any cell was seen in both.
+func andALSSeen(target *[]uint64, a []uint64, b []uint64) bool {
+	if *target == nil {
+		*target = make([]uint64, len(a))
+	}
+	found := false
+	for i := range a {
+		(*target)[i] = a[i] & b[i]
+		found = found || (*target)[i] != 0
+	}
+	return found
+}
+
+// Appends a candidate node for the candidate in each cell of the mask which still has it.
+func appendALSSeenNodes(solver *Solver, candidate int, seen []uint64, nodes []candidateNode) []candidateNode {
+	for i, ids := range seen {
+		for ids != 0 {
+			cell := &solver.Puzzle.Cells[i*64+mathBits.TrailingZeros64(ids)]
+			if cell.HasCandidate(candidate) {
+				nodes = append(nodes, candidateNode{cell, candidate})
+			}
+			ids &= ids - 1
+		}
+	}
+	return nodes
+}
+
+// Creates an almost locked set step which is given the sets and the links between them.
+func createStepAlmostLockedSets(technique string, firstCost int, subsequentCost int, logic func(solver *Solver, limits SolveLimit, step *SolveStep, sets []*almostLockedSet, links [][]alsLink) int) *SolveStep {
+	return &SolveStep{
+		Technique:      technique,
+		FirstCost:      firstCost,
+		SubsequentCost: subsequentCost,
+		Logic: func(solver *Solver, limits SolveLimit, step *SolveStep) (int, bool) {
+			removed := false
+			if solver.CanContinueStep(limits, step) {
+				sets, links := getAlmostLockedSetsAndLinks(solver)
+				removed = logic(solver, limits, step, sets, links) > 0
+			}
+			return 0, removed
+		},
+	}
+}
+
+// ==================================================
+// Step: ALS-XZ
+//		http://hodoku.sourceforge.net/en/tech_als.php#axz
+// ==================================================
+var StepALSXZ = createStepAlmostLockedSets("ALS-XZ", 5500, 4000, doALSXZ)
+
+// Two almost locked sets A and B with a restricted common candidate x. Only one of them can have x, so
+// the other is locked and any other common candidate z must be in one of them. z can be removed from every
+// cell that sees all of the z cells in A and B. When there are two restricted common candidates (doubly
+// linked) both sets are locked: every candidate other than the links can be removed from cells that see all
+// of its cells in a set, and each link candidate from cells that see all of its cells in both sets.
+func doALSXZ(solver *Solver, limits SolveLimit, step *SolveStep, sets []*almostLockedSet, links [][]alsLink) int {
+	removed := 0
+	for i, a := range sets {
+		for k, link := range links[i] {
+			if link.other < i || (k > 0 && links[i][k-1].other == link.other) {
+				continue
+			}
+			b := sets[link.other]
+			pair := []*almostLockedSet{a, b}
+			restricted := Candidates{}
+			for _, other := range links[i][k:] {
+				if other.other != link.other {
+					break
+				}
+				restricted.Set(other.candidate, true)
+			}
+
+			common := a.candidates
+			common.And(b.candidates)
+			eliminations := make([]candidateNode, 0)
+			for _, candidate := range common.ToSlice() {
+				if !restricted.Has(candidate) || restricted.Count > 1 {
+					eliminations = getAlmostLockedSetEliminations(solver, candidate, pair, eliminations)
+				}
+			}
+			if restricted.Count > 1 {
+				for _, als := range pair {
+					for _, candidate := range als.candidates.ToSlice() {
+						if !restricted.Has(candidate) {
+							eliminations = getAlmostLockedSetEliminations(solver, candidate, []*almostLockedSet{als}, eliminations)
+						}
+					}
+				}
+			}
+			if len(eliminations) > 0 {
+				removed += removeCandidateNodes(solver, step, eliminations, getAlmostLockedSetPattern(pair))
+			}
+
+			if !solver.CanContinueStep(limits, step) {
+				return removed
+			}
+		}
+	}
+	return removed
+}
+
+// ==================================================
+// Step: ALS-XY-Wing
+//		http://hodoku.sourceforge.net/en/tech_als.php#axy
+// ==================================================
+var StepALSXYWing = createStepAlmostLockedSets("ALS-XY-Wing", 6500, 5000, doALSXYWing)
+
+// A pivot almost locked set C with a restricted common candidate x with A and y with B. If z (common to A
+// and B) is not in A or B then both are locked, so x is in A and y is in B, which leaves C with two fewer
+// candidates. So z can be removed from every cell that sees all of the z cells in A and B.
+func doALSXYWing(solver *Solver, limits SolveLimit, step *SolveStep, sets []*almostLockedSet, links [][]alsLink) int {
+	removed := 0
+	for pivot := range sets {
+		for k, first := range links[pivot] {
+			for _, second := range links[pivot][k+1:] {
+				if first.other == second.other || first.candidate == second.candidate {
+					continue
+				}
+				a := sets[first.other]
+				b := sets[second.other]
+				common := a.candidates
+				common.And(b.candidates)
+				common.Set(first.candidate, false)
+				common.Set(second.candidate, false)
+
+				eliminations := make([]candidateNode, 0)
+				for _, candidate := range common.ToSlice() {
+					eliminations = getAlmostLockedSetEliminations(solver, candidate, []*almostLockedSet{a, b}, eliminations)
+				}
+				if len(eliminations) > 0 {
+					removed += removeCandidateNodes(solver, step, eliminations, getAlmostLockedSetPattern([]*almostLockedSet{sets[pivot], a, b}))
+				}
+
+				if !solver.CanContinueStep(limits, step) {
+					return removed
+				}
+			}
+		}
+	}
+	return removed
+}
+
+// ==================================================
+// Step: ALS Chain
+//		http://hodoku.sourceforge.net/en/tech_als.php#ach
+// ==================================================
+var StepALSChain = createStepAlmostLockedSets("ALS Chain", 7500, 6000, doALSChain)
+
+// A chain of almost locked sets where each set has a restricted common candidate with the next, and two
+// links in a row have different candidates. If z (common to the first and last set) is not in either end
+// then the first set is locked, which removes the first link from the second set and locks it, and so on
+// until the last set is missing two candidates. So z can be removed from every cell that sees all of the z
+// cells in the first and last set. Chains of two and three sets are ALS-XZ and ALS-XY-Wing.
+// Only the chains whose first and last sets could remove a candidate are searched, up to alsChainMaxNodes.
+func doALSChain(solver *Solver, limits SolveLimit, step *SolveStep, sets []*almostLockedSet, links [][]alsLink) int {
+	removed := 0
+	chain := make([]int, 0, alsChainMaxLength)
+	stopped := false
+	nodes := 0
+	// The candidates which can be removed with the first set for each set that can end the chain, and the
+	// fewest links from each set to one that can end it.
+	ends := make([]Candidates, len(sets))
+	distances := make([]int, len(sets))
+	queue := make([]int, 0, len(sets))
+	// The sets (by index) with each candidate (by index).
+	holders := make([][]int, solver.Puzzle.Kind.Size())
+	for i, als := range sets {
+		for candidate := als.candidates.First(); candidate <= als.candidates.Last(); candidate++ {
+			if als.candidates.Has(candidate) {
+				holders[candidate-1] = append(holders[candidate-1], i)
+			}
+		}
+	}
+
+	var extend func(current int, previous int, first int)
+	extend = func(current int, previous int, first int) {
+		nodes++
+		if nodes > alsChainMaxNodes {
+			stopped = true
+			return
+		}
+		if len(chain) >= 4 && chain[0] < current {
+			common := ends[current]
+			common.Set(first, false)
+			common.Set(previous, false)
+
+			if common.Count > 0 {
+				start := sets[chain[0]]
+				end := sets[current]
+				eliminations := make([]candidateNode, 0)
+				for _, candidate := range common.ToSlice() {
+					eliminations = getAlmostLockedSetEliminations(solver, candidate, []*almostLockedSet{start, end}, eliminations)
+				}
+				if len(eliminations) > 0 {
+					pattern := make([]*almostLockedSet, 0, len(chain))
+					for _, index := range chain {
+						pattern = append(pattern, sets[index])
+					}
+					removed += removeCandidateNodes(solver, step, eliminations, getAlmostLockedSetPattern(pattern))
+					stopped = !solver.CanContinueStep(limits, step)
+				}
+			}
+		}
+		if len(chain) == alsChainMaxLength {
+			return
+		}
+		for _, link := range links[current] {
+			if stopped {
+				return
+			}
+			if link.candidate == previous || len(chain)+1+distances[link.other] > alsChainMaxLength || sliceIndex(chain, func(i int) bool { return i == link.other }) != -1 {
+				continue
+			}
+			nextFirst := first
+			if len(chain) == 1 {
+				nextFirst = link.candidate
+			}
+			chain = append(chain, link.other)
+			extend(link.other, link.candidate, nextFirst)
+			chain = sliceRemoveLast(chain)
+		}
+	}
+
+	for start := range sets {
+		if !getAlmostLockedSetChainEnds(sets, holders, links, start, ends, distances, queue) {
+			continue
+		}
+		chain = append(chain[:0], start)
+		extend(start, 0, 0)
+		if stopped {
+			break
+		}
+	}
+
+	return removed
+}
+
+// Finds the sets after the start which share a candidate with it that can be removed from cells seeing all
+// of its cells in both, and the fewest links from each set to one of them. Returns whether any were found.
+func getAlmostLockedSetChainEnds(sets []*almostLockedSet, holders [][]int, links [][]alsLink, start int, ends []Candidates, distances []int, queue []int) bool {
+	a := sets[start]
+	queue = queue[:0]
+	for i := range sets {
+		ends[i] = Candidates{}
+		distances[i] = alsChainMaxLength
+	}
+	for candidate := a.candidates.First(); candidate <= a.candidates.Last(); candidate++ {
+		if !a.candidates.Has(candidate) || !sliceHasBits(a.seenByAll(candidate)) {
+			continue
+		}
+		for _, i := range holders[candidate-1] {
+			if i > start && a.seesWith(sets[i], candidate) {
+				if ends[i].Count == 0 {
+					distances[i] = 0
+					queue = append(queue, i)
+				}
+				ends[i].Set(candidate, true)
+			}
+		}
+	}
+	for k := 0; k < len(queue); k++ {
+		current := queue[k]
+		for _, link := range links[current] {
+			if distances[link.other] > distances[current]+1 {
+				distances[link.other] = distances[current] + 1
+				queue = append(queue, link.other)
+			}
+		}
+	}
+	return len(queue) > 0
+}
+
+// Returns whether any bit is set in the words.
+func sliceHasBits(words []uint64) bool {
+	for _, word := range words {
+		if word != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ==================================================
+// Step: Death Blossom
+//		http://hodoku.sourceforge.net/en/tech_misc.php#db
+// ==================================================
+var StepDeathBlossom = createStepAlmostLockedSets("Death Blossom", 8000, 6500, doDeathBlossom)
+
+// A stem cell where each candidate is in an almost locked set (a petal) where all of the cells with that
+// candidate see the stem. Whichever candidate the stem is removes it from its petal and locks it, so a
+// candidate z which is in every petal (but not the stem) can be removed from every cell that sees all of the
+// z cells in the petals.
+func doDeathBlossom(solver *Solver, limits SolveLimit, step *SolveStep, sets []*almostLockedSet, links [][]alsLink) int {
+	removed := 0
+	size := solver.Puzzle.Kind.Size()
+	if len(sets) == 0 {
+		return removed
+	}
+	grid := sets[0].grid
+	for _, stem := range solver.Unsolved {
+		if stem.candidates.Count < 2 || stem.candidates.Count > 4 {
+			continue
+		}
+		stemCandidates := stem.Candidates()
+		petals := make([][]*almostLockedSet, len(stemCandidates))
+		for i, candidate := range stemCandidates {
+			for _, als := range sets {
+				if als.candidates.Has(candidate) && !als.contains(stem) && als.allSee(candidate, []*Cell{stem}) {
+					petals[i] = append(petals[i], als)
+				}
+			}
+		}
+
+		chosen := make([]*almostLockedSet, 0, len(stemCandidates))
+		stopped := false
+		// The cells by id which see every cell with each candidate (by index) in the chosen petals, for each
+		// number of chosen petals.
+		seen := make([][][]uint64, len(stemCandidates)+1)
+		for i := range seen {
+			seen[i] = make([][]uint64, size)
+		}
+		for i, candidates := range grid.candidates {
+			seen[0][i] = candidates
+		}
+
+		var choosePetal func(i int, common Candidates)
+		choosePetal = func(i int, common Candidates) {
+			if i == len(stemCandidates) {
+				eliminations := make([]candidateNode, 0)
+				for candidate := common.First(); candidate <= common.Last(); candidate++ {
+					if common.Has(candidate) {
+						eliminations = appendALSSeenNodes(solver, candidate, seen[i][candidate-1], eliminations)
+					}
+				}
+				if len(eliminations) > 0 {
+					pattern := append(getCandidateNodes([]*Cell{stem}, stem.candidates), getAlmostLockedSetPattern(chosen)...)
+					removed += removeCandidateNodes(solver, step, eliminations, pattern)
+					stopped = !solver.CanContinueStep(limits, step)
+				}
+				return
+			}
+			for _, petal := range petals[i] {
+				next := common
+				next.And(petal.candidates)
+				for candidate := next.First(); candidate <= next.Last(); candidate++ {
+					if next.Has(candidate) && !andALSSeen(&seen[i+1][candidate-1], seen[i][candidate-1], petal.seenByAll(candidate)) {
+						next.Set(candidate, false)
+					}
+				}
+				if next.Count == 0 {
+					continue
+				}
+				chosen = append(chosen, petal)
+				choosePetal(i+1, next)
+				chosen = sliceRemoveLast(chosen)
+				if stopped {
+					return
+				}
+			}
+		}
+
+		common := Candidates{}
+		common.Fill(size)
+		common.Remove(stem.candidates)
+		choosePetal(0, common)
+
+		if stopped {
+			break
+		}
+	}
+	return removed
+}
+
+// ==================================================
+// Step: Sue de Coq
+//		http://hodoku.sourceforge.net/en/tech_misc.php#sdc
+// ==================================================
+var StepSueDeCoq = &SolveStep{
+	Technique:      "Sue de Coq",
+	FirstCost:      5000,
+	SubsequentCost: 3600,
+	Logic: func(solver *Solver, limits SolveLimit, step *SolveStep) (int, bool) {
+		removed := false
+		if solver.CanContinueStep(limits, step) {
+			removed = doSueDeCoq(solver, limits, step) > 0
+		}
+		return 0, removed
+	},
+}
+
+// The most cells in the rest of a line or box Sue de Coq will search subsets of.
+const sueDeCoqMaxRest = 16
+
+// Two or three cells where a box and a line (row or column) intersect with at least two more candidates
+// than cells. Cells from the rest of the line and the rest of the box are added so there are as many cells
+// as candidates, where no candidate is in both the added line cells and the added box cells. Every
+// candidate is then in exactly one cell, those not in the box cells are in the line, and those not in the
+// line cells are in the box, so they can be removed from the rest of the line and box respectively.
+func doSueDeCoq(solver *Solver, limits SolveLimit, step *SolveStep) int {
+	removed := 0
+	size := solver.Puzzle.Kind.Size()
+	for _, line := range []Group{GroupRow, GroupCol} {
+		for lineIndex := 0; lineIndex < size; lineIndex++ {
+			lineCells := solver.GroupAt(line, lineIndex)
+			for _, boxCell := range lineCells {
+				if boxCell != getFirstInBox(lineCells, boxCell.Box) {
+					continue
+				}
+				boxCells := solver.Box(boxCell.Box)
+
+				intersection := make([]*Cell, 0)
+				lineRest := make([]*Cell, 0)
+				boxRest := make([]*Cell, 0)
+				for _, cell := range lineCells {
+					if cell.Box == boxCell.Box {
+						intersection = append(intersection, cell)
+					} else {
+						lineRest = append(lineRest, cell)
+					}
+				}
+				for _, cell := range boxCells {
+					if cell.GetGroup(line) != lineIndex {
+						boxRest = append(boxRest, cell)
+					}
+				}
+				// Every subset of the cells is considered, which is too many in large puzzles.
+				if len(lineRest) > sueDeCoqMaxRest || len(boxRest) > sueDeCoqMaxRest {
+					continue
+				}
+
+				intersectionSubsets := getCellSubsets(intersection)
+				lineSubsets := getCellSubsets(lineRest)
+				boxSubsets := getCellSubsets(boxRest)
+				for _, subset := range intersectionSubsets {
+					if subset.size < 2 || subset.candidates.Count < subset.size+2 {
+						continue
+					}
+					removed += doSueDeCoqIntersection(solver, step, subset.cells(intersection), subset.candidates, lineCells, lineRest, lineSubsets, boxCells, boxRest, boxSubsets)
+
+					if !solver.CanContinueStep(limits, step) {
+						return removed
+					}
+				}
+			}
+		}
+	}
+	return removed
+}
+
+func doSueDeCoqIntersection(solver *Solver, step *SolveStep, cells []*Cell, candidates Candidates, lineCells []*Cell, lineRest []*Cell, lineSubsets []cellSubset, boxCells []*Cell, boxRest []*Cell, boxSubsets []cellSubset) int {
+	for _, lineSubset := range lineSubsets[1:] {
+		lineCandidates := lineSubset.candidates
+		if !lineCandidates.Overlaps(candidates) {
+			continue
+		}
+		// Only box cells without any of the line candidates can be added.
+		allowed := 0
+		for i, cell := range boxRest {
+			if !cell.candidates.Overlaps(lineCandidates) {
+				allowed |= 1 << i
+			}
+		}
+		for mask := allowed; mask > 0; mask = (mask - 1) & allowed {
+			boxSubset := boxSubsets[mask]
+			boxCandidates := boxSubset.candidates
+			if !boxCandidates.Overlaps(candidates) {
+				continue
+			}
+			all := candidates
+			all.Or(lineCandidates)
+			all.Or(boxCandidates)
+			if all.Count != len(cells)+lineSubset.size+boxSubset.size {
+				continue
+			}
+
+			lineRemove := all
+			lineRemove.Remove(boxCandidates)
+			boxRemove := all
+			boxRemove.Remove(lineCandidates)
+
+			eliminations := make([]candidateNode, 0)
+			used := append(append(sliceClone(cells), lineSubset.cells(lineRest)...), boxSubset.cells(boxRest)...)
+			for _, house := range []struct {
+				cells  []*Cell
+				remove Candidates
+			}{{lineCells, lineRemove}, {boxCells, boxRemove}} {
+				for _, cell := range house.cells {
+					if sliceIndex(used, func(u *Cell) bool { return u == cell }) != -1 {
+						continue
+					}
+					for _, candidate := range house.remove.ToSlice() {
+						if cell.HasCandidate(candidate) {
+							eliminations = append(eliminations, candidateNode{cell, candidate})
+						}
+					}
+				}
+			}
+			if len(eliminations) > 0 {
+				return removeCandidateNodes(solver, step, eliminations, getCandidateNodes(used, all))
+			}
+		}
+	}
+	return 0
+}
+
+// Returns the first cell in the given box.
+func getFirstInBox(cells []*Cell, box int) *Cell {
+	for _, cell := range cells {
+		if cell.Box == box {
+			return cell
+		}
+	}
+	return nil
+}
+
+// A subset of cells (by bit in mask) and the candidates in them.
+type cellSubset struct {
+	mask       int
+	size       int
+	candidates Candidates
+}
+
+// Returns every subset of the cells indexed by mask.
+func getCellSubsets(cells []*Cell) []cellSubset {
+	subsets := make([]cellSubset, 1<<len(cells))
+	for mask := 1; mask < len(subsets); mask++ {
+		last := mathBits.TrailingZeros(uint(mask))
+		subset := subsets[mask&(mask-1)]
+		subset.mask = mask
+		subset.size++
+		subset.candidates.Or(cells[last].candidates)
+		subsets[mask] = subset
+	}
+	return subsets
+}
+
+// The cells in the subset.
+func (subset cellSubset) cells(cells []*Cell) []*Cell {
+	chosen := make([]*Cell, 0, subset.size)
+	for i, cell := range cells {
+		if subset.mask&(1<<i) != 0 {
+			chosen = append(chosen, cell)
+		}
+	}
+	return chosen
+}
@@ -4,16 +4,36 @@ import (
 	mathBits "math/bits"
 )
 
-// A set of integers from 0 to 63 with O(1) add, remove, search, and union/substraction/intersection set operations.
+// A set of non-negative integers with O(1) add, remove, search, and union/substraction/intersection set
+// operations for integers from 0 to 63. Larger integers are supported with a word per 64 integers.
 type Bitset struct {
+	// The integers from 0 to 63.
 	Value uint64
 	Count int
+	// The integers from 64 and up, 64 per word. Copies of a set share the words, so they are replaced
+	// instead of modified when the set changes.
+	Extra []uint64
 }
 
 // Sets the set to the first N integers starting with 0.
 func (bits *Bitset) Fill(n int) {
 	bits.Count = n
-	bits.Value = (1 << n) - 1
+	bits.Extra = nil
+	if n < 64 {
+		bits.Value = (1 << n) - 1
+		return
+	}
+	bits.Value = ^uint64(0)
+	if n > 64 {
+		bits.Extra = make([]uint64, (n-1)/64)
+		for i := range bits.Extra {
+			if remaining := n - (i+1)*64; remaining >= 64 {
+				bits.Extra[i] = ^uint64(0)
+			} else {
+				bits.Extra[i] = (1 << remaining) - 1
+			}
+		}
+	}
 }
 
 // Removes all integers from the set
@@ -23,7 +43,10 @@ func (bits *Bitset) Clear() {
 
 // Returns whether the integer exists in the set
 func (bits *Bitset) Has(i int) bool {
-	return (bits.Value & (1 << i)) != 0
+	if i < 64 {
+		return (bits.Value & (1 << i)) != 0
+	}
+	return (bits.word(i/64-1) & (1 << (i % 64))) != 0
 }
 
 // Adds or removes the integer from the set and returns true if the set has changed as a result of this call.
@@ -32,10 +55,16 @@ func (bits *Bitset) Set(i int, on bool) bool {
 	if change {
 		if on {
 			bits.Count++
-			bits.Value = bits.Value | (1 << i)
 		} else {
 			bits.Count--
-			bits.Value = bits.Value & ^(1 << i)
+		}
+		if i < 64 {
+			bits.Value = bits.Value ^ (1 << i)
+		} else {
+			extra := make([]uint64, Max(len(bits.Extra), i/64))
+			copy(extra, bits.Extra)
+			extra[i/64-1] = extra[i/64-1] ^ (1 << (i % 64))
+			bits.Extra = extra
 		}
 	}
 	return change
@@ -44,37 +73,55 @@ func (bits *Bitset) Set(i int, on bool) bool {
 // Creates a slice of all integers in this set.
 func (bits *Bitset) ToSlice() []int {
 	slice := make([]int, 0, bits.Count)
-	remaining := bits.Value
-	current := 0
-	for remaining > 0 {
-		if (remaining & 1) == 1 {
-			slice = append(slice, current)
-		}
-		remaining = remaining >> 1
-		current++
+	slice = appendBitsetWord(slice, bits.Value, 0)
+	for i, word := range bits.Extra {
+		slice = appendBitsetWord(slice, word, (i+1)*64)
 	}
 	return slice
 }
 
-// The smallest integer in the set or 64 if the set is empty.
+// The smallest integer in the set or 64 (past the last word) if the set is empty.
 func (bits *Bitset) First() int {
-	return mathBits.TrailingZeros64(bits.Value)
+	if bits.Value != 0 {
+		return mathBits.TrailingZeros64(bits.Value)
+	}
+	for i, word := range bits.Extra {
+		if word != 0 {
+			return (i+1)*64 + mathBits.TrailingZeros64(word)
+		}
+	}
+	return (len(bits.Extra) + 1) * 64
 }
 
 // The largest integer in the set or -1 if the set is empty.
 func (bits *Bitset) Last() int {
+	for i := len(bits.Extra) - 1; i >= 0; i-- {
+		if bits.Extra[i] != 0 {
+			return (i+1)*64 + 63 - mathBits.LeadingZeros64(bits.Extra[i])
+		}
+	}
 	return 63 - mathBits.LeadingZeros64(bits.Value)
 }
 
 // Updates the number of integers in the set based on the Value.
 func (bits *Bitset) UpdateCount() {
 	bits.Count = mathBits.OnesCount64(bits.Value)
+	for _, word := range bits.Extra {
+		bits.Count += mathBits.OnesCount64(word)
+	}
 }
 
 // Removes all integers in the given set from this set.
 func (bits *Bitset) Remove(remove Bitset) int {
 	original := bits.Count
 	bits.Value = bits.Value & ^remove.Value
+	if len(bits.Extra) > 0 && len(remove.Extra) > 0 {
+		extra := make([]uint64, len(bits.Extra))
+		for i, word := range bits.Extra {
+			extra[i] = word & ^remove.word(i)
+		}
+		bits.Extra = extra
+	}
 	bits.UpdateCount()
 	return original - bits.Count
 }
@@ -83,6 +130,13 @@ func (bits *Bitset) Remove(remove Bitset) int {
 func (bits *Bitset) Or(or Bitset) int {
 	original := bits.Count
 	bits.Value = bits.Value | or.Value
+	if len(or.Extra) > 0 {
+		extra := make([]uint64, Max(len(bits.Extra), len(or.Extra)))
+		for i := range extra {
+			extra[i] = bits.word(i) | or.word(i)
+		}
+		bits.Extra = extra
+	}
 	bits.UpdateCount()
 	return bits.Count - original
 }
@@ -91,16 +145,69 @@ func (bits *Bitset) Or(or Bitset) int {
 func (bits *Bitset) And(and Bitset) int {
 	original := bits.Count
 	bits.Value = bits.Value & and.Value
+	if len(bits.Extra) > 0 {
+		extra := make([]uint64, len(bits.Extra))
+		for i, word := range bits.Extra {
+			extra[i] = word & and.word(i)
+		}
+		bits.Extra = extra
+	}
 	bits.UpdateCount()
 	return original - bits.Count
 }
 
 // Returns whether this set and the other share any integers.
 func (bits *Bitset) Overlaps(other Bitset) bool {
-	return (bits.Value & other.Value) != 0
+	if (bits.Value & other.Value) != 0 {
+		return true
+	}
+	for i, word := range bits.Extra {
+		if (word & other.word(i)) != 0 {
+			return true
+		}
+	}
+	return false
 }
 
 // Returns whether this set and the other don't share one or more integers.
 func (bits *Bitset) Differences(other Bitset) bool {
-	return (bits.Value & ^other.Value) != 0
+	if (bits.Value & ^other.Value) != 0 {
+		return true
+	}
+	for i, word := range bits.Extra {
+		if (word & ^other.word(i)) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns whether this set and the other have the same integers.
+func (bits *Bitset) Equals(other Bitset) bool {
+	if bits.Value != other.Value || bits.Count != other.Count {
+		return false
+	}
+	for i := 0; i < len(bits.Extra) || i < len(other.Extra); i++ {
+		if bits.word(i) != other.word(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// The word of integers from (i+1)*64 to (i+2)*64-1, which is 0 past the end of Extra.
+func (bits *Bitset) word(i int) uint64 {
+	if i < len(bits.Extra) {
+		return bits.Extra[i]
+	}
+	return 0
+}
+
+// Appends the integers in the word starting at offset to the slice.
+func appendBitsetWord(slice []int, word uint64, offset int) []int {
+	for word != 0 {
+		slice = append(slice, offset+mathBits.TrailingZeros64(word))
+		word = word & (word - 1)
+	}
+	return slice
 }
@@ -80,3 +80,65 @@ func TestBits(t *testing.T) {
 		t.Error("setting bit 3 resulted in the wrong slice")
 	}
 }
+
+func TestBitsWide(t *testing.T) {
+	b := Bitset{}
+	b.Fill(100)
+
+	if b.Count != 100 {
+		t.Error("fill has wrong count")
+	}
+	if !b.Has(0) || !b.Has(63) || !b.Has(64) || !b.Has(99) || b.Has(100) {
+		t.Error("fill has wrong integers")
+	}
+	if b.First() != 0 || b.Last() != 99 {
+		t.Errorf("fill has wrong first %d and last %d", b.First(), b.Last())
+	}
+
+	copied := b
+	if !copied.Set(70, false) || copied.Count != 99 {
+		t.Error("bit 70 could not be removed")
+	}
+	if !b.Has(70) || b.Count != 100 {
+		t.Error("removing from a copy changed the original")
+	}
+	if copied.Equals(b) {
+		t.Error("different sets are equal")
+	}
+
+	high := Bitset{}
+	high.Set(64, true)
+	high.Set(99, true)
+	if fmt.Sprint(high.ToSlice()) != "[64 99]" {
+		t.Errorf("high has the wrong slice %v", high.ToSlice())
+	}
+	if high.First() != 64 || high.Last() != 99 {
+		t.Errorf("high has wrong first %d and last %d", high.First(), high.Last())
+	}
+
+	if copied.Overlaps(Bitset{Extra: []uint64{1 << 6}}) {
+		t.Error("removed bit 70 overlaps")
+	}
+	if !copied.Overlaps(high) || copied.Differences(b) || !b.Differences(copied) {
+		t.Error("overlaps or differences are wrong")
+	}
+
+	copied.Remove(high)
+	if copied.Count != 97 || copied.Has(64) || copied.Has(99) {
+		t.Errorf("remove is wrong %v", copied.ToSlice())
+	}
+	copied.Or(high)
+	if copied.Count != 99 || !copied.Has(64) || !copied.Has(99) {
+		t.Errorf("or is wrong %v", copied.ToSlice())
+	}
+	copied.And(high)
+	if copied.Count != 2 || !copied.Equals(high) {
+		t.Errorf("and is wrong %v", copied.ToSlice())
+	}
+
+	copied.Set(64, false)
+	copied.Set(99, false)
+	if !copied.Equals(Bitset{}) || copied.First() != 128 || copied.Last() != -1 {
+		t.Error("emptied set is wrong")
+	}
+}
@@ -59,3 +59,8 @@ func (cand *Candidates) Overlaps(other Candidates) bool {
 func (cand *Candidates) Differences(other Candidates) bool {
 	return cand.Bitset.Differences(other.Bitset)
 }
+
+// Returns whether this set and the other have the same candidates.
+func (cand *Candidates) Equals(other Candidates) bool {
+	return cand.Bitset.Equals(other.Bitset)
+}
@@ -16,6 +16,10 @@ type Cell struct {
 	candidates Candidates
 	// The constrains applicable for this cell.
 	Constraints []Constraint
+	// The extra houses of the puzzle's kind this cell is in, like the diagonals of Sudoku-X.
+	Houses []int
+	// The ids of the cells this cell sees through the links of the puzzle's kind, like Anti-Knight.
+	Links []int
 }
 
 type Group int
@@ -24,6 +28,8 @@ const (
 	GroupCol Group = iota
 	GroupRow
 	GroupBox
+	// An extra house of the puzzle's kind. A cell can be in more than one, so they are found by index.
+	GroupHouse
 )
 
 // Returns whether this cell has a value in it.
@@ -38,10 +44,11 @@ func (cell *Cell) Empty() bool {
 
 // Returns if this cell is valid, meaning the value and candidates it has match. If this returns false then there is a logical error in the software.
 func (cell *Cell) Valid() bool {
-	return (cell.Value != 0) == (cell.candidates.Value == 0)
+	return (cell.Value != 0) == (cell.candidates.Count == 0)
 }
 
-// Returns the group given its index. The group order starting with zero is Col, Row, Box.
+// Returns the group given its index. The group order starting with zero is Col, Row, Box. Extra houses
+// are found with Houses since a cell can be in more than one.
 func (cell *Cell) GetGroup(groupIndex Group) int {
 	if groupIndex == GroupCol {
 		return cell.Col
@@ -52,9 +59,44 @@ func (cell *Cell) GetGroup(groupIndex Group) int {
 	}
 }
 
-// Returns whether this cell and the given cell are in the same group (box, column, or row).
+// Returns whether this cell and the given cell are in the same group (box, column, row, or extra house) or
+// see each other through a link.
 func (cell *Cell) InGroup(other *Cell) bool {
-	return cell.Id != other.Id && (cell.Row == other.Row || cell.Col == other.Col || cell.Box == other.Box)
+	return cell.Id != other.Id && (cell.Row == other.Row || cell.Col == other.Col || cell.Box == other.Box || cell.sharesHouse(other) || cell.HasLink(other))
+}
+
+// Returns whether this cell sees the given cell through a link of the puzzle's kind.
+func (cell *Cell) HasLink(other *Cell) bool {
+	for _, link := range cell.Links {
+		if link == other.Id {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns whether this cell and the given cell are in the same extra house.
+func (cell *Cell) InHouse(other *Cell) bool {
+	return cell.Id != other.Id && cell.sharesHouse(other)
+}
+
+// Returns whether this cell is in the extra house with the given index.
+func (cell *Cell) HasHouse(house int) bool {
+	for _, h := range cell.Houses {
+		if h == house {
+			return true
+		}
+	}
+	return false
+}
+
+func (cell *Cell) sharesHouse(other *Cell) bool {
+	for _, house := range cell.Houses {
+		if other.HasHouse(house) {
+			return true
+		}
+	}
+	return false
 }
 
 // Returns whether this cell and the given cell are in the same box.
@@ -0,0 +1,395 @@
+package sudogo
+
+// The maximum number of links in a chain when SolveLimit.MaxChainLength is not given.
+const DefaultMaxChainLength = 12
+
+// The links which are added to a chain graph.
+type chainLinks struct {
+	// A candidate which is in only two cells of a house.
+	houseStrong bool
+	// A candidate in two cells of the same house, or in two cells linked by the kind.
+	houseWeak bool
+	// A cell with only two candidates.
+	cellStrong bool
+	// Two candidates in the same cell.
+	cellWeak bool
+	// Only cells with two candidates are in the graph.
+	bivalueOnly bool
+}
+
+// How the chains found in the graph are used.
+type chainLoop int
+
+const (
+	// A chain which starts and ends with a strong link, either end must be true.
+	chainOpen chainLoop = iota
+	// A chain which is closed by a weak link, every weak link in the loop acts as a strong link.
+	chainContinuous
+	// A chain which starts and ends on the same candidate, which is then true or false.
+	chainDiscontinuous
+)
+
+// Candidates (a cell & value) connected by strong links (if one is false the other is true) and weak
+// links (if one is true the other is false).
+type chainGraph struct {
+	size int
+	// The candidates in the graph by index, a candidate not in the graph has a nil cell.
+	nodes  []candidateNode
+	strong [][]int
+	weak   [][]int
+}
+
+// Builds the links between the unsolved candidates using the rows, columns, boxes, and extra houses of the
+// solver. Cells linked by the kind (like a knight's move apart) are weakly linked too.
+func newChainGraph(solver *Solver, links chainLinks) *chainGraph {
+	size := solver.Puzzle.Kind.Size()
+	nodeCount := len(solver.Puzzle.Cells) * size
+	g := &chainGraph{
+		size:   size,
+		nodes:  make([]candidateNode, nodeCount),
+		strong: make([][]int, nodeCount),
+		weak:   make([][]int, nodeCount),
+	}
+
+	included := func(cell *Cell) bool {
+		return !links.bivalueOnly || cell.candidates.Count == 2
+	}
+
+	for _, cell := range solver.Unsolved {
+		if !included(cell) {
+			continue
+		}
+		candidates := cell.Candidates()
+		for _, candidate := range candidates {
+			i := g.index(cell, candidate)
+			g.nodes[i] = candidateNode{cell, candidate}
+
+			for _, other := range candidates {
+				if other == candidate {
+					continue
+				}
+				if links.cellStrong && len(candidates) == 2 {
+					g.strong[i] = append(g.strong[i], g.index(cell, other))
+				}
+				if links.cellWeak {
+					g.weak[i] = append(g.weak[i], g.index(cell, other))
+				}
+			}
+
+			if !links.houseStrong && !links.houseWeak {
+				continue
+			}
+			for _, group := range solver.cellHouses(cell) {
+				holders := 0
+				for _, other := range group {
+					if other.HasCandidate(candidate) {
+						holders++
+					}
+				}
+				for _, other := range group {
+					if other == cell || !other.HasCandidate(candidate) || !included(other) {
+						continue
+					}
+					if links.houseStrong && holders == 2 {
+						g.strong[i] = append(g.strong[i], g.index(other, candidate))
+					}
+					if links.houseWeak {
+						g.weak[i] = append(g.weak[i], g.index(other, candidate))
+					}
+				}
+			}
+			if links.houseWeak {
+				for _, link := range cell.Links {
+					other := &solver.Puzzle.Cells[link]
+					if other.HasCandidate(candidate) && included(other) {
+						g.weak[i] = append(g.weak[i], g.index(other, candidate))
+					}
+				}
+			}
+		}
+	}
+
+	return g
+}
+
+func (g *chainGraph) index(cell *Cell, candidate int) int {
+	return cell.Id*g.size + candidate - 1
+}
+
+// A breadth first search of the states of the candidates in a chain graph. A state is a candidate
+// (by index) and whether it's true, a false candidate leads to the true candidates on its strong links
+// and a true candidate leads to the false candidates on its weak links.
+type chainSearch struct {
+	graph      *chainGraph
+	parents    []int
+	lengths    []int
+	visited    []int
+	generation int
+	queue      []int
+}
+
+func newChainSearch(graph *chainGraph) *chainSearch {
+	stateCount := len(graph.nodes) * 2
+	return &chainSearch{
+		graph:   graph,
+		parents: make([]int, stateCount),
+		lengths: make([]int, stateCount),
+		visited: make([]int, stateCount),
+		queue:   make([]int, 0, stateCount),
+	}
+}
+
+func chainState(index int, on bool) int {
+	if on {
+		return index*2 + 1
+	}
+	return index * 2
+}
+
+func chainStateIndex(state int) int {
+	return state / 2
+}
+
+func chainStateOn(state int) bool {
+	return state%2 == 1
+}
+
+// Visits every state reachable from the start with at most maxLength links, shortest chains first.
+// The search stops when found returns true.
+func (s *chainSearch) search(start int, on bool, maxLength int, found func(state int, length int) bool) {
+	s.generation++
+	s.queue = s.queue[:0]
+
+	first := chainState(start, on)
+	s.visited[first] = s.generation
+	s.parents[first] = -1
+	s.lengths[first] = 0
+	s.queue = append(s.queue, first)
+
+	for i := 0; i < len(s.queue); i++ {
+		state := s.queue[i]
+		length := s.lengths[state] + 1
+		if length > maxLength {
+			continue
+		}
+		index := chainStateIndex(state)
+		linked := s.graph.strong[index]
+		if chainStateOn(state) {
+			linked = s.graph.weak[index]
+		}
+		for _, next := range linked {
+			nextState := chainState(next, !chainStateOn(state))
+			if s.visited[nextState] == s.generation {
+				continue
+			}
+			s.visited[nextState] = s.generation
+			s.parents[nextState] = state
+			s.lengths[nextState] = length
+			s.queue = append(s.queue, nextState)
+
+			if found(nextState, length) {
+				return
+			}
+		}
+	}
+}
+
+// The candidates in the chain from the start of the last search to the given state.
+func (s *chainSearch) path(state int) []candidateNode {
+	path := make([]candidateNode, s.lengths[state]+1)
+	for i := len(path) - 1; i >= 0; i-- {
+		path[i] = s.graph.nodes[chainStateIndex(state)]
+		state = s.parents[state]
+	}
+	return path
+}
+
+// The implications in the chain from the start of the last search to the given state.
+func (s *chainSearch) implications(state int) []SolverImplication {
+	implications := make([]SolverImplication, s.lengths[state]+1)
+	for i := len(implications) - 1; i >= 0; i-- {
+		node := s.graph.nodes[chainStateIndex(state)]
+		implications[i] = SolverImplication{SolverCandidate{Position{Col: node.cell.Col, Row: node.cell.Row}, node.candidate}, chainStateOn(state)}
+		state = s.parents[state]
+	}
+	return implications
+}
+
+// The maximum number of links in a chain for the given limits.
+func getMaxChainLength(limits SolveLimit) int {
+	if limits.MaxChainLength > 0 {
+		return limits.MaxChainLength
+	}
+	return DefaultMaxChainLength
+}
+
+// The cost of a chain step which increases with each link after the third.
+func getChainCost(solver *Solver, step *SolveStep, lengthCost int, length int) int {
+	return solver.GetCost(step) + lengthCost*Max(0, length-3)
+}
+
+// Returns whether the two candidates are weakly linked, in the same cell or the same value in cells
+// that see each other.
+func chainSees(a candidateNode, b candidateNode) bool {
+	if a == b {
+		return false
+	}
+	return a.cell == b.cell || (a.candidate == b.candidate && a.cell.InGroup(b.cell))
+}
+
+// Returns the candidates that are weakly linked to both a and b, which are false when either a or b
+// is true.
+func getChainEliminations(solver *Solver, a candidateNode, b candidateNode, eliminations []candidateNode) []candidateNode {
+	add := func(node candidateNode) {
+		if node != a && node != b && node.cell.HasCandidate(node.candidate) && sliceIndex(eliminations, func(e candidateNode) bool { return e == node }) == -1 {
+			eliminations = append(eliminations, node)
+		}
+	}
+	if a.cell == b.cell {
+		for _, candidate := range a.cell.Candidates() {
+			add(candidateNode{a.cell, candidate})
+		}
+	} else if a.candidate == b.candidate {
+		for _, cell := range solver.Unsolved {
+			if cell != a.cell && cell != b.cell && cell.InGroup(a.cell) && cell.InGroup(b.cell) {
+				add(candidateNode{cell, a.candidate})
+			}
+		}
+	} else if a.cell.InGroup(b.cell) {
+		add(candidateNode{a.cell, b.candidate})
+		add(candidateNode{b.cell, a.candidate})
+	}
+	return eliminations
+}
+
+// ==================================================
+// Step: X-Chain, XY-Chain, Continuous & Discontinuous Nice Loop
+//		http://hodoku.sourceforge.net/en/tech_chains.php
+// ==================================================
+func CreateStepChain(technique string, firstCost int, subsequentCost int, lengthCost int, links chainLinks, loop chainLoop) *SolveStep {
+	return &SolveStep{
+		Technique:      technique,
+		FirstCost:      firstCost,
+		SubsequentCost: subsequentCost,
+		Logic: func(solver *Solver, limits SolveLimit, step *SolveStep) (int, bool) {
+			placements, removed := 0, 0
+			if solver.CanContinueStep(limits, step) {
+				placements, removed = doChain(solver, limits, step, lengthCost, links, loop)
+			}
+			return placements, removed > 0
+		},
+	}
+}
+
+// Chains of strong links in houses on a single value.
+var StepXChain = CreateStepChain("X-Chain", 5000, 3400, 200, chainLinks{houseStrong: true, houseWeak: true}, chainOpen)
+
+// Chains of cells with two candidates where each cell shares a value with the next.
+var StepXYChain = CreateStepChain("XY-Chain", 5200, 3600, 200, chainLinks{cellStrong: true, houseWeak: true, bivalueOnly: true}, chainOpen)
+
+// Loops of alternating strong and weak links of any kind.
+var StepContinuousNiceLoop = CreateStepChain("Continuous Nice Loop", 6600, 4800, 300, chainLinks{houseStrong: true, houseWeak: true, cellStrong: true, cellWeak: true}, chainContinuous)
+
+// Loops of alternating strong and weak links of any kind that meet at a candidate with two links of the
+// same kind.
+var StepDiscontinuousNiceLoop = CreateStepChain("Discontinuous Nice Loop", 6800, 5000, 300, chainLinks{houseStrong: true, houseWeak: true, cellStrong: true, cellWeak: true}, chainDiscontinuous)
+
+// Searches for chains from every candidate in the graph, alternating between strong and weak links.
+// An open chain that starts and ends with a strong link means one of its ends is true, so any candidate
+// weakly linked to both ends can be removed. A continuous loop means one end of every weak link is
+// true, so candidates weakly linked to both ends of a weak link can be removed. A discontinuous loop
+// at a candidate means it's true (two strong links) or false (two weak links).
+func doChain(solver *Solver, limits SolveLimit, step *SolveStep, lengthCost int, links chainLinks, loop chainLoop) (int, int) {
+	removed := 0
+	placements := 0
+	graph := newChainGraph(solver, links)
+	search := newChainSearch(graph)
+	maxLength := getMaxChainLength(limits)
+	stopped := false
+
+	apply := func(length int, eliminations []candidateNode, state int) bool {
+		if len(eliminations) == 0 {
+			return false
+		}
+		if !solver.CanContinue(limits, getChainCost(solver, step, lengthCost, length)) {
+			stopped = true
+			return true
+		}
+		solver.LogStepCost(step, getChainCost(solver, step, lengthCost, length))
+		logPatternNodes(solver, search.path(state))
+		solver.LogImplications(search.implications(state))
+		for _, node := range eliminations {
+			solver.LogBefore(node.cell)
+			node.cell.RemoveCandidate(node.candidate)
+			solver.LogAfter(node.cell)
+		}
+		removed += len(eliminations)
+		stopped = !solver.CanContinueStep(limits, step)
+		return stopped
+	}
+
+	for start, node := range graph.nodes {
+		if node.cell == nil || !node.cell.HasCandidate(node.candidate) {
+			continue
+		}
+
+		switch loop {
+		case chainOpen:
+			search.search(start, false, maxLength, func(state int, length int) bool {
+				end := graph.nodes[chainStateIndex(state)]
+				if !chainStateOn(state) || end == node || length < 3 {
+					return false
+				}
+				return apply(length, getChainEliminations(solver, node, end, nil), state)
+			})
+
+		case chainContinuous:
+			search.search(start, false, maxLength-1, func(state int, length int) bool {
+				end := graph.nodes[chainStateIndex(state)]
+				if !chainStateOn(state) || length < 3 || !chainSees(end, node) {
+					return false
+				}
+				path := search.path(state)
+				eliminations := getChainEliminations(solver, end, node, nil)
+				for i := 1; i < len(path)-1; i += 2 {
+					eliminations = getChainEliminations(solver, path[i], path[i+1], eliminations)
+				}
+				return apply(length+1, eliminations, state)
+			})
+
+		case chainDiscontinuous:
+			search.search(start, false, maxLength, func(state int, length int) bool {
+				if chainStateIndex(state) != start || !chainStateOn(state) {
+					return false
+				}
+				cost := getChainCost(solver, step, lengthCost, length)
+				if solver.CanContinue(limits, cost) {
+					solver.LogStepCost(step, cost)
+					logPatternNodes(solver, search.path(state))
+					solver.LogImplications(search.implications(state))
+					solver.LogBefore(node.cell)
+					solver.SetCell(node.cell, node.candidate)
+					solver.LogPlacement(node.cell)
+					placements++
+				}
+				stopped = true
+				return true
+			})
+			if stopped {
+				return placements, removed
+			}
+			search.search(start, true, maxLength, func(state int, length int) bool {
+				if chainStateIndex(state) != start || chainStateOn(state) {
+					return false
+				}
+				return apply(length, []candidateNode{node}, state)
+			})
+		}
+
+		if stopped {
+			break
+		}
+	}
+
+	return placements, removed
+}
@@ -1,5 +1,7 @@
 package sudogo
 
+import "math/rand"
+
 type ClearLimit struct {
 	SolveLimit
 	Symmetric bool
@@ -27,6 +29,15 @@ func (limits ClearLimit) Extend(extend ClearLimit) ClearLimit {
 	if extend.MaxPlacements > 0 {
 		out.MaxPlacements = extend.MaxPlacements
 	}
+	if extend.MaxChainLength > 0 {
+		out.MaxChainLength = extend.MaxChainLength
+	}
+	if extend.MaxForcingDepth > 0 {
+		out.MaxForcingDepth = extend.MaxForcingDepth
+	}
+	if extend.MaxForcingNodes > 0 {
+		out.MaxForcingNodes = extend.MaxForcingNodes
+	}
 	if extend.MaxStates > 0 {
 		out.MaxStates = extend.MaxStates
 	}
@@ -63,25 +74,51 @@ var DifficultyDiabolical = ClearLimit{
 }
 
 func (gen *Generator) ClearCells(puzzle *Puzzle, limits ClearLimit) (*Puzzle, int) {
-	if puzzle == nil || (limits.MaxBatches == 0 && limits.MaxCost == 0 && limits.MaxLogs == 0 && limits.MaxPlacements == 0 && limits.MaxStates == 0) {
+	if puzzle == nil {
 		return nil, 0
 	}
+	return clearCells[*Puzzle](gen.Random, puzzle, limits)
+}
+
+// A puzzle which cells can be cleared from, a Puzzle or a MultiPuzzle.
+type clearablePuzzle[P any] interface {
+	clone() P
+	Remove(col int, row int) bool
+	// The positions of the cells with values.
+	valuePositions() []Position
+	// The position opposite the given one through the center and whether it has a value.
+	symmetric(position Position) (Position, bool)
+	// Solves the puzzle to rate it and returns the solver if it has a unique solution, or if it can be
+	// solved when the limits are fast.
+	rate(limits ClearLimit) clearSolver
+}
+
+type clearSolver interface {
+	CanContinue(limits SolveLimit, cost int) bool
+}
+
+// Clears cells from the solved puzzle, keeping a unique solution, until solving it reaches the limits.
+// Returns the cleared puzzle, or the zero value when the limits are never reached, and the number of
+// puzzles with a unique solution that were rated.
+func clearCells[P clearablePuzzle[P]](random *rand.Rand, puzzle P, limits ClearLimit) (P, int) {
+	var none P
+	if limits.MaxBatches == 0 && limits.MaxCost == 0 && limits.MaxLogs == 0 && limits.MaxPlacements == 0 && limits.MaxStates == 0 {
+		return none, 0
+	}
 
 	states := 0
 
 	type AttemptState struct {
-		puzzle    Puzzle
-		available []*Cell
+		puzzle    P
+		available []Position
 	}
 
 	attempts := NewStack[AttemptState](limits.MaxPlacements)
 
-	initial := puzzle.Clone()
+	initial := puzzle.clone()
 	attempts.Push(AttemptState{
-		puzzle: initial,
-		available: pointersWhere(initial.Cells, func(cell *Cell) bool {
-			return cell.HasValue()
-		}),
+		puzzle:    initial,
+		available: initial.valuePositions(),
 	})
 
 	for !attempts.Empty() {
@@ -92,51 +129,32 @@ func (gen *Generator) ClearCells(puzzle *Puzzle, limits ClearLimit) (*Puzzle, in
 			continue
 		}
 
-		next := last.puzzle.Clone()
+		next := last.puzzle.clone()
 
-		cell := randomPointer(gen.Random, last.available)
-		cellSymmetric := last.puzzle.GetSymmetric(cell)
+		position := randomElement(random, last.available, Position{})
+		positionSymmetric, symmetricValue := last.puzzle.symmetric(position)
 
-		doSymmetric := limits.Symmetric && cellSymmetric.HasValue()
+		doSymmetric := limits.Symmetric && symmetricValue
 
-		next.Remove(cell.Col, cell.Row)
+		next.Remove(position.Col, position.Row)
 		if doSymmetric {
-			next.Remove(cellSymmetric.Col, cellSymmetric.Row)
+			next.Remove(positionSymmetric.Col, positionSymmetric.Row)
 		}
 
-		last.available = removeValue(last.available, cell)
+		last.available = removeValue(last.available, position)
 		if doSymmetric {
-			last.available = removeValue(last.available, cellSymmetric)
+			last.available = removeValue(last.available, positionSymmetric)
 		}
 
 		if len(last.available) == 0 {
 			attempts.Pop()
 		}
 
-		var solver *Solver = nil
-
-		if limits.Fast {
-			nextSolver := next.Solver()
-			nextSolution, _ := nextSolver.Solve(limits.SolveLimit)
-			if nextSolution != nil && nextSolution.IsSolved() {
-				solver = &nextSolver
-			}
-		} else {
-			nextSolutions := next.GetSolutions(SolutionsLimit{
-				SolveLimit:   limits.SolveLimit,
-				MaxSolutions: 2,
-			})
-
-			if len(nextSolutions) == 1 {
-				solver = nextSolutions[0]
-			}
-		}
-
-		if solver != nil {
+		if solver := next.rate(limits); solver != nil {
 			states++
 
 			if !solver.CanContinue(limits.SolveLimit, 0) {
-				return &next, states
+				return next, states
 			}
 
 			if limits.MaxStates > 0 && states >= limits.MaxStates {
@@ -150,5 +168,41 @@ func (gen *Generator) ClearCells(puzzle *Puzzle, limits ClearLimit) (*Puzzle, in
 		}
 	}
 
-	return nil, states
+	return none, states
+}
+
+func (puzzle *Puzzle) clone() *Puzzle {
+	clone := puzzle.Clone()
+	return &clone
+}
+
+func (puzzle *Puzzle) valuePositions() []Position {
+	positions := make([]Position, 0, len(puzzle.Cells))
+	for i := range puzzle.Cells {
+		if cell := &puzzle.Cells[i]; cell.HasValue() {
+			positions = append(positions, Position{Col: cell.Col, Row: cell.Row})
+		}
+	}
+	return positions
+}
+
+func (puzzle *Puzzle) symmetric(position Position) (Position, bool) {
+	cell := puzzle.GetSymmetric(puzzle.Get(position.Col, position.Row))
+	return Position{Col: cell.Col, Row: cell.Row}, cell.HasValue()
+}
+
+func (puzzle *Puzzle) rate(limits ClearLimit) clearSolver {
+	if limits.Fast {
+		solver := puzzle.Solver()
+		solution, _ := solver.Solve(limits.SolveLimit)
+		if solution != nil && solution.IsSolved() {
+			return &solver
+		}
+	} else if puzzle.CountSolutions(2) == 1 {
+		// The solver only rates the puzzle, it doesn't need to solve it.
+		solver := puzzle.Solver()
+		solver.Solve(limits.SolveLimit)
+		return &solver
+	}
+	return nil
 }
@@ -0,0 +1,412 @@
+package sudogo
+
+// Candidates (a cell & value) connected by strong links where exactly one end of each link is true. Each
+// connected cluster is split into two colors, one color of a cluster is all true and the other all false.
+type coloring struct {
+	size int
+	// The color of each candidate (by cell id & value) where 0 is not colored. The colors of a cluster are
+	// cluster*2+1 and cluster*2+2.
+	colors []int
+	// The colored candidates for each color.
+	nodes [][]candidateNode
+}
+
+// A candidate in a cell.
+type candidateNode struct {
+	cell      *Cell
+	candidate int
+}
+
+// The candidates in the cells which are one of the given candidates.
+func getCandidateNodes(cells []*Cell, candidates Candidates) []candidateNode {
+	nodes := make([]candidateNode, 0, len(cells))
+	for _, cell := range cells {
+		for _, candidate := range candidates.ToSlice() {
+			if cell.HasCandidate(candidate) {
+				nodes = append(nodes, candidateNode{cell, candidate})
+			}
+		}
+	}
+	return nodes
+}
+
+// Adds the cells and candidates of the nodes to the pattern of the current batch's deduction.
+func logPatternNodes(solver *Solver, nodes []candidateNode) {
+	for _, node := range nodes {
+		if node.cell != nil {
+			solver.LogPattern([]*Cell{node.cell}, node.candidate)
+		}
+	}
+}
+
+// Colors the strong links of the given candidates, and when bivalue is true a cell with only two
+// candidates links them as well.
+func newColoring(solver *Solver, links [][]strongLink, candidates []int, bivalue bool) *coloring {
+	size := solver.Puzzle.Kind.Size()
+	nodeCount := len(solver.Puzzle.Cells) * size
+	c := &coloring{
+		size:   size,
+		colors: make([]int, nodeCount),
+		nodes:  [][]candidateNode{{}},
+	}
+
+	edges := make([][]candidateNode, nodeCount)
+	addEdge := func(a candidateNode, b candidateNode) {
+		edges[c.index(a.cell, a.candidate)] = append(edges[c.index(a.cell, a.candidate)], b)
+		edges[c.index(b.cell, b.candidate)] = append(edges[c.index(b.cell, b.candidate)], a)
+	}
+
+	for _, candidate := range candidates {
+		for _, link := range links[candidate] {
+			addEdge(candidateNode{link.first, candidate}, candidateNode{link.second, candidate})
+		}
+	}
+	if bivalue {
+		for _, cell := range solver.Unsolved {
+			if cell.candidates.Count == 2 {
+				addEdge(candidateNode{cell, cell.candidates.First()}, candidateNode{cell, cell.candidates.Last()})
+			}
+		}
+	}
+
+	queue := NewQueue[candidateNode]()
+	for _, cell := range solver.Unsolved {
+		for _, candidate := range candidates {
+			start := candidateNode{cell, candidate}
+			if len(edges[c.index(cell, candidate)]) == 0 || c.color(cell, candidate) != 0 {
+				continue
+			}
+
+			color := len(c.nodes)
+			c.nodes = append(c.nodes, []candidateNode{}, []candidateNode{})
+			c.setColor(start, color)
+			queue.Offer(start)
+
+			for !queue.Empty() {
+				node := queue.Poll()
+				opposite := coloringOpposite(c.color(node.cell, node.candidate))
+				for _, linked := range edges[c.index(node.cell, node.candidate)] {
+					if c.color(linked.cell, linked.candidate) == 0 {
+						c.setColor(linked, opposite)
+						queue.Offer(linked)
+					}
+				}
+			}
+		}
+	}
+
+	return c
+}
+
+func (c *coloring) index(cell *Cell, candidate int) int {
+	return cell.Id*c.size + candidate - 1
+}
+
+func (c *coloring) setColor(node candidateNode, color int) {
+	c.colors[c.index(node.cell, node.candidate)] = color
+	c.nodes[color] = append(c.nodes[color], node)
+}
+
+// The color of the candidate in the cell or 0 if it has none.
+func (c *coloring) color(cell *Cell, candidate int) int {
+	return c.colors[c.index(cell, candidate)]
+}
+
+// The value of the first candidate with the color, which is the value of all candidates with the color
+// when only strong links in houses are colored.
+func (c *coloring) candidate(color int) int {
+	return c.nodes[color][0].candidate
+}
+
+// The number of colors, the colors are 1 to colorCount (inclusive).
+func (c *coloring) colorCount() int {
+	return len(c.nodes) - 1
+}
+
+// Returns whether the candidate in the cell sees a candidate of the given color with the same value.
+func (c *coloring) sees(cell *Cell, candidate int, color int) bool {
+	for _, node := range c.nodes[color] {
+		if node.candidate == candidate && node.cell.InGroup(cell) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns whether a candidate of color a sees a candidate of color b with the same value, in which case
+// both colors can't be true.
+func (c *coloring) colorsSee(a int, b int) bool {
+	for _, node := range c.nodes[a] {
+		if c.sees(node.cell, node.candidate, b) {
+			return true
+		}
+	}
+	return false
+}
+
+// The other color in the cluster.
+func coloringOpposite(color int) int {
+	if color%2 == 1 {
+		return color + 1
+	}
+	return color - 1
+}
+
+// Adds the candidates of each color to the colors of the current batch's deduction.
+func (c *coloring) log(solver *Solver, colors ...int) {
+	for _, color := range colors {
+		candidates := make([]SolverCandidate, 0, len(c.nodes[color]))
+		for _, node := range c.nodes[color] {
+			candidates = append(candidates, SolverCandidate{Position{Col: node.cell.Col, Row: node.cell.Row}, node.candidate})
+		}
+		solver.LogColors(candidates)
+	}
+}
+
+// Removes every candidate of a color that is false as one step, the pattern is the color's cluster.
+func removeColor(solver *Solver, step *SolveStep, c *coloring, color int) int {
+	removed := 0
+	solver.LogStep(step)
+	logPatternNodes(solver, c.nodes[color])
+	logPatternNodes(solver, c.nodes[coloringOpposite(color)])
+	c.log(solver, color, coloringOpposite(color))
+	for _, node := range c.nodes[color] {
+		if node.cell.HasCandidate(node.candidate) {
+			solver.LogBefore(node.cell)
+			node.cell.RemoveCandidate(node.candidate)
+			solver.LogAfter(node.cell)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Removes the given candidates which are still in their cells as one step, the pattern is the given colors.
+func removeColoredNodes(solver *Solver, step *SolveStep, c *coloring, nodes []candidateNode, colors ...int) int {
+	pattern := make([]candidateNode, 0)
+	for _, color := range colors {
+		pattern = append(pattern, c.nodes[color]...)
+	}
+	removed := removeCandidateNodes(solver, step, nodes, pattern)
+	if removed > 0 {
+		c.log(solver, colors...)
+	}
+	return removed
+}
+
+// Removes the given candidates which are still in their cells as one step of the pattern.
+func removeCandidateNodes(solver *Solver, step *SolveStep, nodes []candidateNode, pattern []candidateNode) int {
+	removed := 0
+	for _, node := range nodes {
+		if !node.cell.HasCandidate(node.candidate) {
+			continue
+		}
+		if removed == 0 {
+			solver.LogStep(step)
+			logPatternNodes(solver, pattern)
+		}
+		solver.LogBefore(node.cell)
+		node.cell.RemoveCandidate(node.candidate)
+		solver.LogAfter(node.cell)
+		removed++
+	}
+	return removed
+}
+
+// ==================================================
+// Step: Simple Coloring
+//		http://hodoku.sourceforge.net/en/tech_col.php
+// ==================================================
+var StepSimpleColoring = &SolveStep{
+	Technique:      "Simple Coloring",
+	FirstCost:      3800,
+	SubsequentCost: 2400,
+	Logic: func(solver *Solver, limits SolveLimit, step *SolveStep) (int, bool) {
+		removed := false
+		if solver.CanContinueStep(limits, step) {
+			removed = doSimpleColoring(solver, limits, step) > 0
+		}
+		return 0, removed
+	},
+}
+
+// For each digit, color the cells of each chain of strong links alternating between two colors.
+// Color wrap: if two cells of the same color see each other that color is false and is removed.
+// Color trap: a cell that sees both colors of a chain can't be the digit.
+func doSimpleColoring(solver *Solver, limits SolveLimit, step *SolveStep) int {
+	removed := 0
+	c := newColoring(solver, getStrongLinks(solver), solver.Puzzle.Kind.Candidates(), false)
+
+	for color := 1; color <= c.colorCount(); color++ {
+		if c.colorsSee(color, color) {
+			removed += removeColor(solver, step, c, color)
+
+			if !solver.CanContinueStep(limits, step) {
+				return removed
+			}
+		}
+	}
+
+	for color := 1; color <= c.colorCount(); color += 2 {
+		candidate := c.candidate(color)
+		trapped := make([]candidateNode, 0)
+		for _, cell := range solver.Unsolved {
+			if cell.HasCandidate(candidate) && c.color(cell, candidate) == 0 && c.sees(cell, candidate, color) && c.sees(cell, candidate, color+1) {
+				trapped = append(trapped, candidateNode{cell, candidate})
+			}
+		}
+		removed += removeColoredNodes(solver, step, c, trapped, color, color+1)
+
+		if !solver.CanContinueStep(limits, step) {
+			return removed
+		}
+	}
+
+	return removed
+}
+
+// ==================================================
+// Step: Multi-Coloring
+//		http://hodoku.sourceforge.net/en/tech_col.php
+// ==================================================
+var StepMultiColoring = &SolveStep{
+	Technique:      "Multi-Coloring",
+	FirstCost:      4400,
+	SubsequentCost: 3000,
+	Logic: func(solver *Solver, limits SolveLimit, step *SolveStep) (int, bool) {
+		removed := false
+		if solver.CanContinueStep(limits, step) {
+			removed = doMultiColoring(solver, limits, step) > 0
+		}
+		return 0, removed
+	},
+}
+
+// For each digit, color the chains of strong links like simple coloring and compare two chains.
+// If a color (a) of one chain sees both colors of another chain then a is false and is removed.
+// If a color (a) of one chain sees a color (b) of another chain then one of their opposite colors
+// is true, and any cell that sees both opposite colors can't be the digit.
+func doMultiColoring(solver *Solver, limits SolveLimit, step *SolveStep) int {
+	removed := 0
+	c := newColoring(solver, getStrongLinks(solver), solver.Puzzle.Kind.Candidates(), false)
+
+	for a := 1; a <= c.colorCount(); a++ {
+		candidate := c.candidate(a)
+		for b := 1; b <= c.colorCount(); b++ {
+			if (a-1)/2 == (b-1)/2 || c.candidate(b) != candidate || !c.colorsSee(a, b) {
+				continue
+			}
+			if b%2 == 1 && c.colorsSee(a, b+1) {
+				removed += removeColor(solver, step, c, a)
+
+				if !solver.CanContinueStep(limits, step) {
+					return removed
+				}
+			}
+			if a > b {
+				continue
+			}
+			oppositeA := coloringOpposite(a)
+			oppositeB := coloringOpposite(b)
+			trapped := make([]candidateNode, 0)
+			for _, cell := range solver.Unsolved {
+				if cell.HasCandidate(candidate) && c.sees(cell, candidate, oppositeA) && c.sees(cell, candidate, oppositeB) {
+					trapped = append(trapped, candidateNode{cell, candidate})
+				}
+			}
+			removed += removeColoredNodes(solver, step, c, trapped, a, oppositeA, b, oppositeB)
+
+			if !solver.CanContinueStep(limits, step) {
+				return removed
+			}
+		}
+	}
+
+	return removed
+}
+
+// ==================================================
+// Step: 3D Medusa
+//		https://www.sudokuwiki.org/3D_Medusa
+// ==================================================
+var Step3DMedusa = &SolveStep{
+	Technique:      "3D Medusa",
+	FirstCost:      6000,
+	SubsequentCost: 4400,
+	Logic: func(solver *Solver, limits SolveLimit, step *SolveStep) (int, bool) {
+		removed := false
+		if solver.CanContinueStep(limits, step) {
+			removed = do3DMedusa(solver, limits, step) > 0
+		}
+		return 0, removed
+	},
+}
+
+// Color every candidate linked by strong links in houses and by cells with two candidates.
+// A color is false when it's twice in a cell, twice in a house, or when an uncolored cell has all of its
+// candidates see that color. An uncolored candidate can be removed when it's in a cell with both colors,
+// sees both colors, or is in a cell with one color and sees the opposite color.
+func do3DMedusa(solver *Solver, limits SolveLimit, step *SolveStep) int {
+	removed := 0
+	c := newColoring(solver, getStrongLinks(solver), solver.Puzzle.Kind.Candidates(), true)
+
+	for color := 1; color <= c.colorCount(); color++ {
+		if isMedusaColorFalse(solver, c, color) {
+			return removeColor(solver, step, c, color)
+		}
+	}
+
+	for color := 1; color <= c.colorCount(); color += 2 {
+		opposite := color + 1
+		eliminations := make([]candidateNode, 0)
+		for _, cell := range solver.Unsolved {
+			hasColor := false
+			hasOpposite := false
+			for _, candidate := range cell.Candidates() {
+				hasColor = hasColor || c.color(cell, candidate) == color
+				hasOpposite = hasOpposite || c.color(cell, candidate) == opposite
+			}
+			for _, candidate := range cell.Candidates() {
+				if c.color(cell, candidate) != 0 {
+					continue
+				}
+				seesColor := c.sees(cell, candidate, color)
+				seesOpposite := c.sees(cell, candidate, opposite)
+				if (hasColor || seesColor) && (hasOpposite || seesOpposite) {
+					eliminations = append(eliminations, candidateNode{cell, candidate})
+				}
+			}
+		}
+		removed += removeColoredNodes(solver, step, c, eliminations, color, opposite)
+
+		if !solver.CanContinueStep(limits, step) {
+			return removed
+		}
+	}
+
+	return removed
+}
+
+func isMedusaColorFalse(solver *Solver, c *coloring, color int) bool {
+	nodes := c.nodes[color]
+	for i, a := range nodes {
+		for _, b := range nodes[i+1:] {
+			if a.cell == b.cell || (a.candidate == b.candidate && a.cell.InGroup(b.cell)) {
+				return true
+			}
+		}
+	}
+	for _, cell := range solver.Unsolved {
+		emptied := cell.candidates.Count > 0
+		for _, candidate := range cell.Candidates() {
+			if c.color(cell, candidate) != 0 || !c.sees(cell, candidate, color) {
+				emptied = false
+				break
+			}
+		}
+		if emptied {
+			return true
+		}
+	}
+	return false
+}
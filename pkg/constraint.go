@@ -19,6 +19,12 @@ package sudogo
 //		- Kings move
 //		- Knights move: https://www.youtube.com/watch?v=hAyZ9K2EBF0
 // 		- No repeats in group/age: https://www.youtube.com/watch?v=u6Le6f9d0KU&t=602s, https://www.youtube.com/watch?v=hAyZ9K2EBF0
+// - A cell is the sum of a collection of cells which can repeat digits
+//		- Arrow
+// - A collection of cells along a diagonal sum up to a value and can repeat digits
+//		- Little Killer
+// - The cells between the 1 and the largest digit of a row or column sum up to a value
+//		- Sandwich
 // - Or constraint (multiple constraints can dictate which candidates are available)
 // 		- Sum is square: https://www.youtube.com/watch?v=u6Le6f9d0KU&t=602s
 // - And constraint
@@ -26,6 +32,22 @@ package sudogo
 type Constraint interface {
 	Affects(cell *Cell) bool
 	RemoveCandidates(cell *Cell, puzzle *Puzzle, remove *Candidates)
+	// The cells whose values break the constraint. Empty cells are ignored, so the values of a partial grid
+	// only break the constraint when the other cells can't complete it.
+	Validate(puzzle *Puzzle) []Position
+}
+
+// A constraint whose deductions are made by a named step with its own costs, like a killer cage's by
+// StepKillerCombination. When the solver doesn't have the step the constraint is applied by StepConstraints.
+type ConstraintTechnique interface {
+	Constraint
+	Technique() *SolveStep
+}
+
+// A constraint which removes candidates from cells beyond filtering the candidates of its own cells, like a
+// digit a killer cage must have being removed from the cells which see every cell of the cage it could be in.
+type ConstraintEliminator interface {
+	Eliminations(puzzle *Puzzle) []SolverCandidate
 }
 
 // A cell position on a puzzle.
@@ -127,8 +149,9 @@ func (c *ConstraintSum) RemoveCandidates(cell *Cell, puzzle *Puzzle, remove *Can
 
 	if combos.empty() {
 		chosen := sumEmpty
-		if remove.Has(chosen) {
-			remove.Clear()
+		fits := chosen > 0 && remove.Has(chosen)
+		remove.Clear()
+		if fits {
 			remove.Set(chosen, true)
 		}
 		return
@@ -155,6 +178,10 @@ func (c *ConstraintSum) RemoveCandidates(cell *Cell, puzzle *Puzzle, remove *Can
 	}
 }
 
+func (c *ConstraintSum) Validate(puzzle *Puzzle) []Position {
+	return validateCandidates(c, puzzle)
+}
+
 // ==================================================
 // Constraint: Uniqueness
 // ==================================================
@@ -184,6 +211,10 @@ func (c *ConstraintUnique) RemoveCandidates(cell *Cell, puzzle *Puzzle, remove *
 	})
 }
 
+func (c *ConstraintUnique) Validate(puzzle *Puzzle) []Position {
+	return validateCandidates(c, puzzle)
+}
+
 // ==================================================
 // Constraint: Uniqueness
 // ==================================================
@@ -261,6 +292,10 @@ func (c *ConstraintOrder) RemoveCandidates(cell *Cell, puzzle *Puzzle, remove *C
 	}
 }
 
+func (c *ConstraintOrder) Validate(puzzle *Puzzle) []Position {
+	return validateCandidates(c, puzzle)
+}
+
 // ==================================================
 // Constraint: Magic Square
 // ==================================================
@@ -326,6 +361,10 @@ func (c *ConstraintMagic) RemoveCandidates(cell *Cell, puzzle *Puzzle, remove *C
 	}
 }
 
+func (c *ConstraintMagic) Validate(puzzle *Puzzle) []Position {
+	return validateCandidates(c, puzzle)
+}
+
 // ==================================================
 // Constraint: Scale
 // ==================================================
@@ -372,6 +411,10 @@ func (c *ConstraintScalePair) RemoveCandidates(cell *Cell, puzzle *Puzzle, remov
 	}
 }
 
+func (c *ConstraintScalePair) Validate(puzzle *Puzzle) []Position {
+	return validateCandidates(c, puzzle)
+}
+
 func ConstraintScalePairs(scale int, pairs [][2]Position) []ConstraintScalePair {
 	constraints := make([]ConstraintScalePair, len(pairs))
 	for pairIndex, pair := range pairs {
@@ -451,6 +494,10 @@ func (c *ConstraintDifference) RemoveCandidates(cell *Cell, puzzle *Puzzle, remo
 	}
 }
 
+func (c *ConstraintDifference) Validate(puzzle *Puzzle) []Position {
+	return validateCandidates(c, puzzle)
+}
+
 func doMinMaxDifference(candidate int, min int, max int, candidateMin int, candidateMax int, out *Candidates, set bool) {
 	if min > 1 {
 		minMin := Max(candidate-min+1, candidateMin)
@@ -498,6 +545,10 @@ func (c *ConstraintDivisible) RemoveCandidates(cell *Cell, puzzle *Puzzle, remov
 	}
 }
 
+func (c *ConstraintDivisible) Validate(puzzle *Puzzle) []Position {
+	return validateCandidates(c, puzzle)
+}
+
 func ConstraintEven(cells []Position) ConstraintDivisible {
 	return ConstraintDivisible{
 		By:        2,
@@ -514,6 +565,269 @@ func ConstraintOdd(cells []Position) ConstraintDivisible {
 	}
 }
 
+// ==================================================
+// Constraint: Arrow
+// ==================================================
+
+// The digit in the circle is the sum of the digits along the arrow. Digits can repeat along the arrow
+// unless the cells see each other.
+type ConstraintArrow struct {
+	Circle Position
+	Arrow  []Position
+}
+
+func (c *ConstraintArrow) Technique() *SolveStep {
+	return StepArrowSum
+}
+
+func (c *ConstraintArrow) Affects(cell *Cell) bool {
+	return isSame(cell, c.Circle) || cellExists(cell, c.Arrow)
+}
+
+func (c *ConstraintArrow) RemoveCandidates(cell *Cell, puzzle *Puzzle, remove *Candidates) {
+	circle := getAbsoluteCell(puzzle, c.Circle)
+	arrow := make([]*Cell, len(c.Arrow))
+	for i, pos := range c.Arrow {
+		arrow[i] = getAbsoluteCell(puzzle, pos)
+	}
+	sets := make([]Candidates, len(arrow))
+
+	// Whether the arrow can sum to the circle with the cell being the candidate.
+	possible := func(candidate int) bool {
+		circleValues := cellValues(circle)
+		if circle.Id == cell.Id {
+			circleValues.Clear()
+			circleValues.Set(candidate, true)
+		}
+		for _, total := range circleValues.ToSlice() {
+			for i, other := range arrow {
+				sets[i] = cellValues(other)
+				if other.Id == cell.Id {
+					sets[i].Clear()
+					sets[i].Set(candidate, true)
+				}
+				if other.InGroup(circle) {
+					sets[i].Set(total, false)
+				}
+			}
+			if sumPossible(arrow, sets, total) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, candidate := range remove.ToSlice() {
+		if !possible(candidate) {
+			remove.Set(candidate, false)
+		}
+	}
+}
+
+func (c *ConstraintArrow) Validate(puzzle *Puzzle) []Position {
+	return validateCandidates(c, puzzle)
+}
+
+// ==================================================
+// Step: Arrow Sum
+// ==================================================
+var StepArrowSum = CreateStepConstraint("Arrow Sum", 350, 200)
+
+// ==================================================
+// Constraint: Little Killer
+// ==================================================
+
+// The digits along a diagonal starting at the edge of the puzzle sum to a value. Digits can repeat along
+// the diagonal unless the cells see each other.
+type ConstraintLittleKiller struct {
+	Sum int
+	// The first cell of the diagonal.
+	Start Position
+	// The direction of the diagonal where the column and row are each -1 or 1.
+	Direction Position
+}
+
+// The cells along the diagonal which are in the puzzle.
+func (c *ConstraintLittleKiller) GetCells(puzzle *Puzzle) []*Cell {
+	cells := make([]*Cell, 0, puzzle.Kind.Size())
+	col, row := c.Start.Col, c.Start.Row
+	for puzzle.Contains(col, row) && (c.Direction.Col != 0 || c.Direction.Row != 0) {
+		cells = append(cells, puzzle.Get(col, row))
+		col += c.Direction.Col
+		row += c.Direction.Row
+	}
+	return cells
+}
+
+func (c *ConstraintLittleKiller) Technique() *SolveStep {
+	return StepLittleKillerSum
+}
+
+func (c *ConstraintLittleKiller) Affects(cell *Cell) bool {
+	dc := cell.Col - c.Start.Col
+	dr := cell.Row - c.Start.Row
+	if c.Direction.Col == 0 || c.Direction.Row == 0 {
+		return false
+	}
+	steps := dc * c.Direction.Col
+	return steps >= 0 && dr*c.Direction.Row == steps && AbsInt(dc) == AbsInt(dr)
+}
+
+func (c *ConstraintLittleKiller) RemoveCandidates(cell *Cell, puzzle *Puzzle, remove *Candidates) {
+	cells := c.GetCells(puzzle)
+	sets := make([]Candidates, len(cells))
+
+	for _, candidate := range remove.ToSlice() {
+		for i, other := range cells {
+			sets[i] = cellValues(other)
+			if other.Id == cell.Id {
+				sets[i].Clear()
+				sets[i].Set(candidate, true)
+			}
+		}
+		if !sumPossible(cells, sets, c.Sum) {
+			remove.Set(candidate, false)
+		}
+	}
+}
+
+func (c *ConstraintLittleKiller) Validate(puzzle *Puzzle) []Position {
+	return validateCandidates(c, puzzle)
+}
+
+// ==================================================
+// Step: Little Killer Sum
+// ==================================================
+var StepLittleKillerSum = CreateStepConstraint("Little Killer Sum", 400, 250)
+
+// ==================================================
+// Constraint: Sandwich
+// ==================================================
+
+// The digits in a row or column between the 1 and the largest digit sum to a value.
+type ConstraintSandwich struct {
+	Sum int
+	// The row or column of the sandwich.
+	Group Group
+	Index int
+}
+
+// The cells of the row or column.
+func (c *ConstraintSandwich) GetCells(puzzle *Puzzle) []*Cell {
+	size := puzzle.Kind.Size()
+	cells := make([]*Cell, size)
+	for i := range cells {
+		if c.Group == GroupRow {
+			cells[i] = puzzle.Get(i, c.Index)
+		} else {
+			cells[i] = puzzle.Get(c.Index, i)
+		}
+	}
+	return cells
+}
+
+func (c *ConstraintSandwich) Technique() *SolveStep {
+	return StepSandwichSum
+}
+
+func (c *ConstraintSandwich) Affects(cell *Cell) bool {
+	return (c.Group == GroupRow || c.Group == GroupCol) && cell.GetGroup(c.Group) == c.Index
+}
+
+func (c *ConstraintSandwich) RemoveCandidates(cell *Cell, puzzle *Puzzle, remove *Candidates) {
+	cells := c.GetCells(puzzle)
+	digits := puzzle.Kind.Digits()
+	crusts := Candidates{}
+	crusts.Set(1, true)
+	crusts.Set(digits, true)
+	all := Candidates{}
+	all.Fill(digits)
+
+	possible := Candidates{}
+	between := make([]*Cell, 0, len(cells))
+	outside := make([]*Cell, 0, len(cells))
+
+	for first, firstCell := range cells {
+		for last, lastCell := range cells {
+			if first == last || AbsInt(first-last)-1 > digits-2 {
+				continue
+			}
+			// The 1 and the largest digit are either way around.
+			firstValues, lastValues := cellValues(firstCell), cellValues(lastCell)
+			if !firstValues.Has(1) || !lastValues.Has(digits) {
+				continue
+			}
+			between = between[:0]
+			outside = outside[:0]
+			role := 0
+			for i, other := range cells {
+				inside := (i > first && i < last) || (i < first && i > last)
+				if other.Id == cell.Id {
+					if i == first {
+						role = 1
+					} else if i == last {
+						role = digits
+					} else if inside {
+						role = -1
+					} else {
+						role = -2
+					}
+				} else if inside {
+					between = append(between, other)
+				} else if i != first && i != last {
+					outside = append(outside, other)
+				}
+			}
+
+			size := AbsInt(first-last) - 1
+			for _, combo := range cageCombinations(digits, size, c.Sum) {
+				if combo.Overlaps(crusts) {
+					continue
+				}
+				rest := all
+				rest.Remove(combo)
+				rest.Remove(crusts)
+
+				switch role {
+				case 1, digits:
+					if remove.Has(role) && !possible.Has(role) && cellsFit(between, combo) && cellsFit(outside, rest) {
+						possible.Set(role, true)
+					}
+				case -1, -2:
+					fill, other, otherDigits := combo, outside, rest
+					if role == -2 {
+						fill, other, otherDigits = rest, between, combo
+					}
+					if !cellsFit(other, otherDigits) {
+						continue
+					}
+					for _, candidate := range fill.ToSlice() {
+						if !remove.Has(candidate) || possible.Has(candidate) {
+							continue
+						}
+						remaining := fill
+						remaining.Set(candidate, false)
+						if role == -1 && cellsFit(between, remaining) || role == -2 && cellsFit(outside, remaining) {
+							possible.Set(candidate, true)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	remove.And(possible)
+}
+
+func (c *ConstraintSandwich) Validate(puzzle *Puzzle) []Position {
+	return validateCandidates(c, puzzle)
+}
+
+// ==================================================
+// Step: Sandwich Sum
+// ==================================================
+var StepSandwichSum = CreateStepConstraint("Sandwich Sum", 500, 300)
+
 // Functions
 
 func traverseCells(puzzle *Puzzle, cell *Cell, absolute *[]Position, relative *[]Position, traverse func(other *Cell, index int)) {
@@ -535,6 +849,32 @@ func traverseCells(puzzle *Puzzle, cell *Cell, absolute *[]Position, relative *[
 	}
 }
 
+// The affected cells with a value the constraint would remove if it were their only candidate, which is how
+// constraints are validated. Each cell is emptied while it's checked so the constraint treats it like the
+// others, and then it's restored.
+func validateCandidates(constraint Constraint, puzzle *Puzzle) []Position {
+	var invalid []Position
+	for i := range puzzle.Cells {
+		cell := &puzzle.Cells[i]
+		if cell.Empty() || !constraint.Affects(cell) {
+			continue
+		}
+		saved := *cell
+		cell.Value = 0
+		cell.candidates = Candidates{}
+		cell.candidates.Set(saved.Value, true)
+
+		remove := cell.candidates
+		constraint.RemoveCandidates(cell, puzzle, &remove)
+		*cell = saved
+
+		if !remove.Has(saved.Value) {
+			invalid = append(invalid, Position{cell.Col, cell.Row})
+		}
+	}
+	return invalid
+}
+
 func isSame(cell *Cell, pos Position) bool {
 	return cell.Col == pos.Col && cell.Row == pos.Row
 }
@@ -587,6 +927,114 @@ func cellExists(cell *Cell, cells []Position) bool {
 	return false
 }
 
+func positionExists(pos Position, cells []Position) bool {
+	for _, p := range cells {
+		if p == pos {
+			return true
+		}
+	}
+	return false
+}
+
+// The value of the cell or its candidates.
+func cellValues(cell *Cell) Candidates {
+	if cell.HasValue() {
+		values := Candidates{}
+		values.Set(cell.Value, true)
+		return values
+	}
+	return cell.candidates
+}
+
+// Returns whether each cell can take a different one of the digits, which must be as many as the cells.
+func cellsFit(cells []*Cell, digits Candidates) bool {
+	if len(cells) != digits.Count {
+		return false
+	}
+	if len(cells) == 0 {
+		return true
+	}
+	values := cellValues(cells[0])
+	for digit := digits.First(); digit <= digits.Last(); digit++ {
+		if digits.Has(digit) && values.Has(digit) {
+			rest := digits
+			rest.Set(digit, false)
+			if cellsFit(cells[1:], rest) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Returns whether a digit can be chosen from each set for the cells which add up to the sum, where the
+// digits of cells which see each other are different.
+func sumPossible(cells []*Cell, sets []Candidates, sum int) bool {
+	mins := make([]int, len(sets)+1)
+	maxs := make([]int, len(sets)+1)
+	for i := len(sets) - 1; i >= 0; i-- {
+		if sets[i].Count == 0 {
+			return false
+		}
+		mins[i] = mins[i+1] + sets[i].First()
+		maxs[i] = maxs[i+1] + sets[i].Last()
+	}
+	chosen := make([]int, len(sets))
+
+	var search func(i int, remaining int) bool
+	search = func(i int, remaining int) bool {
+		if remaining < mins[i] || remaining > maxs[i] {
+			return false
+		}
+		if i == len(sets) {
+			return remaining == 0
+		}
+		for _, candidate := range sets[i].ToSlice() {
+			seen := false
+			for k := 0; k < i && !seen; k++ {
+				seen = chosen[k] == candidate && cells[k].InGroup(cells[i])
+			}
+			if !seen {
+				chosen[i] = candidate
+				if search(i+1, remaining-candidate) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	return search(0, sum)
+}
+
+// The eliminations of each required digit, which must be in one of the cells, from the other cells which see
+// every one of the cells that could be the digit.
+func requiredEliminations(puzzle *Puzzle, cells []*Cell, required Candidates) []SolverCandidate {
+	eliminations := make([]SolverCandidate, 0)
+
+	for _, digit := range required.ToSlice() {
+		holders := sliceWhere(cells, func(cell *Cell) bool {
+			return cell.candidates.Has(digit)
+		})
+		if len(holders) == 0 {
+			continue
+		}
+
+		for i := range puzzle.Cells {
+			other := &puzzle.Cells[i]
+			if !other.candidates.Has(digit) || sliceIndex(cells, func(cell *Cell) bool { return cell.Id == other.Id }) != -1 {
+				continue
+			}
+			sees := sliceIndex(holders, func(holder *Cell) bool { return !other.InGroup(holder) }) == -1
+			if sees {
+				eliminations = append(eliminations, SolverCandidate{Position{Col: other.Col, Row: other.Row}, digit})
+			}
+		}
+	}
+
+	return eliminations
+}
+
 func intsSum(values []int) int {
 	sum := 0
 	for _, v := range values {
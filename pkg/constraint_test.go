@@ -122,6 +122,46 @@ func TestConstraintSum(t *testing.T) {
 			affects:         []Position{},
 			affectsExpected: []bool{},
 		},
+		{
+			puzzle: Classic.Create([][]int{
+				{7, 5, 0, 0, 0, 0, 0, 0, 0},
+			}),
+			constraint: ConstraintSum{
+				Sum: SumConstant(30),
+				Cells: &[]Position{
+					{0, 0},
+					{1, 0},
+					{2, 0},
+				},
+			},
+			cellsExpected: []string{
+				"[]",
+				"[]",
+				"[]", // 18
+			},
+			affects:         []Position{},
+			affectsExpected: []bool{},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{7, 5, 0, 0, 0, 0, 0, 0, 0},
+			}),
+			constraint: ConstraintSum{
+				Sum: SumConstant(10),
+				Cells: &[]Position{
+					{0, 0},
+					{1, 0},
+					{2, 0},
+				},
+			},
+			cellsExpected: []string{
+				"[]",
+				"[]",
+				"[]", // -2
+			},
+			affects:         []Position{},
+			affectsExpected: []bool{},
+		},
 	}
 
 	for testIndex, test := range tests {
@@ -476,3 +516,156 @@ func Test4GivenDigits(t *testing.T) {
 		solutions[0].Puzzle.PrintConsole()
 	}
 }
+
+func testConstraintCandidates(t *testing.T, name string, puzzle Puzzle, constraint Constraint, cells []Position, cellsExpected []string) {
+	for cellIndex, pos := range cells {
+		cell := puzzle.Get(pos.Col, pos.Row)
+		cand := cell.candidates
+
+		if !constraint.Affects(cell) {
+			t.Errorf("%s expected to affect %v", name, pos)
+		}
+		if cell.Empty() {
+			constraint.RemoveCandidates(cell, &puzzle, &cand)
+		}
+
+		expected := cellsExpected[cellIndex]
+		actual := fmt.Sprintf("%v", cand.ToSlice())
+
+		if expected != actual {
+			t.Errorf("%s failed at %v, actual: %s, expected: %s", name, pos, actual, expected)
+		}
+	}
+}
+
+func TestConstraintArrow(t *testing.T) {
+	tests := []struct {
+		name          string
+		puzzle        Puzzle
+		constraint    ConstraintArrow
+		cellsExpected []string
+	}{
+		{
+			name:          "row",
+			puzzle:        Classic.Empty(),
+			constraint:    ConstraintArrow{Circle: Position{0, 0}, Arrow: []Position{{1, 0}, {2, 0}}},
+			cellsExpected: []string{"[3 4 5 6 7 8 9]", "[1 2 3 4 5 6 7 8]", "[1 2 3 4 5 6 7 8]"},
+		},
+		{
+			name:          "repeats",
+			puzzle:        Classic.Empty(),
+			constraint:    ConstraintArrow{Circle: Position{0, 0}, Arrow: []Position{{3, 1}, {6, 2}}},
+			cellsExpected: []string{"[2 3 4 5 6 7 8 9]", "[1 2 3 4 5 6 7 8]", "[1 2 3 4 5 6 7 8]"},
+		},
+		{
+			name: "given circle",
+			puzzle: Classic.Create([][]int{
+				{3, 0, 0, 0, 0, 0, 0, 0, 0},
+			}),
+			constraint:    ConstraintArrow{Circle: Position{0, 0}, Arrow: []Position{{1, 0}, {2, 0}}},
+			cellsExpected: []string{"[]", "[1 2]", "[1 2]"},
+		},
+	}
+
+	for _, test := range tests {
+		cells := append([]Position{test.constraint.Circle}, test.constraint.Arrow...)
+		testConstraintCandidates(t, "TestConstraintArrow "+test.name, test.puzzle, &test.constraint, cells, test.cellsExpected)
+	}
+}
+
+func TestConstraintLittleKiller(t *testing.T) {
+	tests := []struct {
+		name          string
+		puzzle        Puzzle
+		constraint    ConstraintLittleKiller
+		cellsExpected []string
+	}{
+		{
+			name:          "box",
+			puzzle:        Classic.Empty(),
+			constraint:    ConstraintLittleKiller{Sum: 6, Start: Position{6, 0}, Direction: Position{1, 1}},
+			cellsExpected: []string{"[1 2 3]", "[1 2 3]", "[1 2 3]"},
+		},
+		{
+			name:          "repeats",
+			puzzle:        Classic.Empty(),
+			constraint:    ConstraintLittleKiller{Sum: 9, Start: Position{2, 0}, Direction: Position{1, 1}},
+			cellsExpected: []string{"[1]", "[1 2]", "[1 2]", "[1]", "[1 2]", "[1 2]", "[1]"},
+		},
+		{
+			name:          "up",
+			puzzle:        Classic.Empty(),
+			constraint:    ConstraintLittleKiller{Sum: 3, Start: Position{1, 7}, Direction: Position{-1, 1}},
+			cellsExpected: []string{"[1 2]", "[1 2]"},
+		},
+	}
+
+	for _, test := range tests {
+		cells := make([]Position, 0)
+		for _, cell := range test.constraint.GetCells(&test.puzzle) {
+			cells = append(cells, Position{cell.Col, cell.Row})
+		}
+		if len(cells) != len(test.cellsExpected) {
+			t.Fatalf("TestConstraintLittleKiller %s expected %d cells, got %d", test.name, len(test.cellsExpected), len(cells))
+		}
+		testConstraintCandidates(t, "TestConstraintLittleKiller "+test.name, test.puzzle, &test.constraint, cells, test.cellsExpected)
+	}
+
+	outside := ConstraintLittleKiller{Sum: 6, Start: Position{6, 0}, Direction: Position{1, 1}}
+	puzzle := Classic.Empty()
+	if outside.Affects(puzzle.Get(5, 0)) || outside.Affects(puzzle.Get(7, 0)) || outside.Affects(puzzle.Get(5, 1)) {
+		t.Errorf("TestConstraintLittleKiller expected to only affect the diagonal")
+	}
+}
+
+func TestConstraintSandwich(t *testing.T) {
+	row := []Position{{0, 0}, {1, 0}, {2, 0}, {3, 0}, {4, 0}, {5, 0}, {6, 0}, {7, 0}, {8, 0}}
+
+	tests := []struct {
+		name          string
+		puzzle        Puzzle
+		constraint    ConstraintSandwich
+		cellsExpected []string
+	}{
+		{
+			name:       "ends",
+			puzzle:     Classic.Empty(),
+			constraint: ConstraintSandwich{Sum: 35, Group: GroupRow, Index: 0},
+			cellsExpected: []string{
+				"[1 9]", "[2 3 4 5 6 7 8]", "[2 3 4 5 6 7 8]", "[2 3 4 5 6 7 8]", "[2 3 4 5 6 7 8]",
+				"[2 3 4 5 6 7 8]", "[2 3 4 5 6 7 8]", "[2 3 4 5 6 7 8]", "[1 9]",
+			},
+		},
+		{
+			name: "given",
+			puzzle: Classic.Create([][]int{
+				{1, 0, 0, 0, 0, 0, 0, 0, 0},
+			}),
+			constraint: ConstraintSandwich{Sum: 2, Group: GroupRow, Index: 0},
+			cellsExpected: []string{
+				"[]", "[2]", "[9]", "[3 4 5 6 7 8]", "[3 4 5 6 7 8]",
+				"[3 4 5 6 7 8]", "[3 4 5 6 7 8]", "[3 4 5 6 7 8]", "[3 4 5 6 7 8]",
+			},
+		},
+		{
+			name:       "empty",
+			puzzle:     Classic.Empty(),
+			constraint: ConstraintSandwich{Sum: 0, Group: GroupCol, Index: 0},
+			cellsExpected: []string{
+				"[1 2 3 4 5 6 7 8 9]", "[1 2 3 4 5 6 7 8 9]", "[1 2 3 4 5 6 7 8 9]", "[1 2 3 4 5 6 7 8 9]", "[1 2 3 4 5 6 7 8 9]",
+				"[1 2 3 4 5 6 7 8 9]", "[1 2 3 4 5 6 7 8 9]", "[1 2 3 4 5 6 7 8 9]", "[1 2 3 4 5 6 7 8 9]",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		cells := row
+		if test.constraint.Group == GroupCol {
+			cells = make([]Position, len(row))
+			for i, pos := range row {
+				cells[i] = Position{pos.Row, pos.Col}
+			}
+		}
+		testConstraintCandidates(t, "TestConstraintSandwich "+test.name, test.puzzle, &test.constraint, cells, test.cellsExpected)
+	}
+}
@@ -0,0 +1,246 @@
+package sudogo
+
+// An exact cover matrix for the row, column, box, and extra house rules of a puzzle solved with dancing
+// links (Algorithm X). Each column is a rule which must be covered exactly once: every cell has one value
+// and every row, column, box, and extra house has each digit once. Each row is a digit in a cell and covers
+// the columns of its rules. Rules already covered by values in the puzzle are left out. Secondary columns are
+// rules which can be covered at most once, like two linked cells not having the same digit.
+type dancingLinks struct {
+	// The links of each node where 0 is the root, the next nodes are the column headers, and then the
+	// nodes of the rows.
+	left   []int
+	right  []int
+	up     []int
+	down   []int
+	column []int
+	// The candidate of the row (cell id * digits + digit - 1) for each node.
+	row []int
+	// The number of rows in each column (by header).
+	size []int
+	// The candidates of the rows chosen so far.
+	solution []int
+}
+
+// Builds the matrix for the puzzle's values. Returns false if the values break a rule.
+func newDancingLinks(puzzle *Puzzle) (*dancingLinks, bool) {
+	size := puzzle.Kind.Size()
+	area := size * size
+	columns := area*4 + len(puzzle.Kind.Houses)*size
+
+	values := make([]int, len(puzzle.Cells))
+	for i := range puzzle.Cells {
+		values[i] = puzzle.Cells[i].Value
+	}
+
+	// The secondary rules of each pair of linked cells.
+	pairs := make([][]int, len(puzzle.Cells))
+	secondary := 0
+	for i := range puzzle.Cells {
+		cell := &puzzle.Cells[i]
+		for _, link := range cell.Links {
+			if link > cell.Id {
+				pairs[cell.Id] = append(pairs[cell.Id], secondary)
+				pairs[link] = append(pairs[link], secondary)
+				secondary++
+			}
+		}
+	}
+
+	return newDancingLinksRules(values, size, columns, secondary*size, func(id int, digit int) []int {
+		cell := &puzzle.Cells[id]
+		d := digit - 1
+		cellRules := []int{cell.Id, area + cell.Row*size + d, area*2 + cell.Col*size + d, area*3 + cell.Box*size + d}
+		for _, house := range cell.Houses {
+			cellRules = append(cellRules, area*4+house*size+d)
+		}
+		for _, pair := range pairs[cell.Id] {
+			cellRules = append(cellRules, columns+pair*size+d)
+		}
+		return cellRules
+	})
+}
+
+// Builds the matrix for cells with the given values (0 when empty) which can have the digits 1 to digits.
+// Each digit in a cell covers the columns returned by rules, the first of which should be the cell's own
+// rule. The secondary columns come after the columns. Returns false if the values break a rule.
+func newDancingLinksRules(values []int, digits int, primary int, secondary int, rules func(cell int, digit int) []int) (*dancingLinks, bool) {
+	cells := len(values)
+	columns := primary + secondary
+
+	dl := &dancingLinks{
+		left:     make([]int, columns+1, columns+1+cells*4),
+		right:    make([]int, columns+1, columns+1+cells*4),
+		up:       make([]int, columns+1, columns+1+cells*4),
+		down:     make([]int, columns+1, columns+1+cells*4),
+		column:   make([]int, columns+1, columns+1+cells*4),
+		row:      make([]int, columns+1, columns+1+cells*4),
+		size:     make([]int, columns+1),
+		solution: make([]int, 0, cells),
+	}
+
+	// Whether each rule is already covered by a value.
+	covered := make([]bool, columns)
+	for cell, value := range values {
+		if value == 0 {
+			continue
+		}
+		for _, rule := range rules(cell, value) {
+			if covered[rule] {
+				return nil, false
+			}
+			covered[rule] = true
+		}
+	}
+
+	last := 0
+	for rule := 0; rule < columns; rule++ {
+		header := rule + 1
+		dl.up[header] = header
+		dl.down[header] = header
+		dl.column[header] = header
+		if rule < primary && !covered[rule] {
+			dl.left[header] = last
+			dl.right[last] = header
+			last = header
+		} else {
+			dl.left[header] = header
+			dl.right[header] = header
+		}
+	}
+	dl.left[0] = last
+	dl.right[last] = 0
+
+	for cell, value := range values {
+		if value != 0 {
+			continue
+		}
+		for digit := 1; digit <= digits; digit++ {
+			cellRules := rules(cell, digit)
+			if sliceIndex(cellRules, func(rule int) bool { return covered[rule] }) != -1 {
+				continue
+			}
+			first := len(dl.left)
+			for k, rule := range cellRules {
+				dl.addNode(rule+1, cell*digits+digit-1, first, k)
+			}
+		}
+	}
+
+	return dl, true
+}
+
+// Adds a node to the bottom of the column, in a row which starts at the first node and has k nodes before it.
+func (dl *dancingLinks) addNode(header int, candidate int, first int, k int) {
+	node := len(dl.left)
+	left, right := node, node
+	if k > 0 {
+		left, right = node-1, first
+	}
+	dl.left = append(dl.left, left)
+	dl.right = append(dl.right, right)
+	dl.up = append(dl.up, dl.up[header])
+	dl.down = append(dl.down, header)
+	dl.column = append(dl.column, header)
+	dl.row = append(dl.row, candidate)
+	if k > 0 {
+		dl.right[left] = node
+		dl.left[first] = node
+	}
+	dl.down[dl.up[header]] = node
+	dl.up[header] = node
+	dl.size[header]++
+}
+
+func (dl *dancingLinks) cover(header int) {
+	dl.right[dl.left[header]] = dl.right[header]
+	dl.left[dl.right[header]] = dl.left[header]
+	for i := dl.down[header]; i != header; i = dl.down[i] {
+		for j := dl.right[i]; j != i; j = dl.right[j] {
+			dl.down[dl.up[j]] = dl.down[j]
+			dl.up[dl.down[j]] = dl.up[j]
+			dl.size[dl.column[j]]--
+		}
+	}
+}
+
+func (dl *dancingLinks) uncover(header int) {
+	for i := dl.up[header]; i != header; i = dl.up[i] {
+		for j := dl.left[i]; j != i; j = dl.left[j] {
+			dl.size[dl.column[j]]++
+			dl.down[dl.up[j]] = j
+			dl.up[dl.down[j]] = j
+		}
+	}
+	dl.right[dl.left[header]] = header
+	dl.left[dl.right[header]] = header
+}
+
+// Searches for every exact cover and calls found with the candidates of each until it returns false.
+// Returns false if the search was stopped.
+func (dl *dancingLinks) search(found func(solution []int) bool) bool {
+	if dl.right[0] == 0 {
+		return found(dl.solution)
+	}
+
+	// The rule with the fewest rows left.
+	best := dl.right[0]
+	for header := dl.right[best]; header != 0 && dl.size[best] > 0; header = dl.right[header] {
+		if dl.size[header] < dl.size[best] {
+			best = header
+		}
+	}
+	if dl.size[best] == 0 {
+		return true
+	}
+
+	searching := true
+	dl.cover(best)
+	for i := dl.down[best]; i != best && searching; i = dl.down[i] {
+		dl.solution = append(dl.solution, dl.row[i])
+		for j := dl.right[i]; j != i; j = dl.right[j] {
+			dl.cover(dl.column[j])
+		}
+		searching = dl.search(found)
+		for j := dl.left[i]; j != i; j = dl.left[j] {
+			dl.uncover(dl.column[j])
+		}
+		dl.solution = sliceRemoveLast(dl.solution)
+	}
+	dl.uncover(best)
+	return searching
+}
+
+// Calls found with each solution to the puzzle's row, column, box, extra house, and link rules until it returns false.
+// The constraints of the puzzle's kind are ignored.
+func (puzzle *Puzzle) EachSolution(found func(solution *Puzzle) bool) {
+	dl, valid := newDancingLinks(puzzle)
+	if !valid {
+		return
+	}
+	size := puzzle.Kind.Size()
+	dl.search(func(candidates []int) bool {
+		solution := puzzle.Clone()
+		for _, candidate := range candidates {
+			solution.SetCell(&solution.Cells[candidate/size], candidate%size+1)
+		}
+		return found(&solution)
+	})
+}
+
+// Returns the number of solutions to the puzzle, counting no more than max (when max > 0). Puzzles
+// without constraints are counted with dancing links, otherwise they are solved with GetSolutions.
+func (puzzle *Puzzle) CountSolutions(max int) int {
+	if len(puzzle.Kind.Constraints) > 0 {
+		return len(puzzle.GetSolutions(SolutionsLimit{MaxSolutions: max}))
+	}
+	dl, valid := newDancingLinks(puzzle)
+	if !valid {
+		return 0
+	}
+	count := 0
+	dl.search(func(candidates []int) bool {
+		count++
+		return max <= 0 || count < max
+	})
+	return count
+}
@@ -0,0 +1,143 @@
+package sudogo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCountSolutions(t *testing.T) {
+	unique := Classic.Create([][]int{
+		{0, 0, 3, 0, 2, 0, 6, 0, 0},
+		{9, 0, 0, 3, 0, 5, 0, 0, 1},
+		{0, 0, 1, 8, 0, 6, 4, 0, 0},
+		{0, 0, 8, 1, 0, 2, 9, 0, 0},
+		{7, 0, 0, 0, 0, 0, 0, 0, 8},
+		{0, 0, 6, 7, 0, 8, 2, 0, 0},
+		{0, 0, 2, 6, 0, 9, 5, 0, 0},
+		{8, 0, 0, 2, 0, 3, 0, 0, 9},
+		{0, 0, 5, 0, 1, 0, 3, 0, 0},
+	})
+	multiple := unique.Clone()
+	multiple.Cells[2].Value = 0
+	multiple.Cells[4].Value = 0
+	multiple.Cells[9].Value = 0
+
+	// Create ignores values which break a rule.
+	conflicting := Kind2x2.Empty()
+	conflicting.Cells[0].Value = 1
+	conflicting.Cells[3].Value = 1
+
+	tests := []struct {
+		name     string
+		puzzle   Puzzle
+		max      int
+		expected int
+	}{
+		{
+			name:     "unique",
+			puzzle:   unique,
+			max:      2,
+			expected: 1,
+		},
+		{
+			name:     "multiple",
+			puzzle:   multiple,
+			max:      2,
+			expected: 2,
+		},
+		{
+			name:     "empty 2x2",
+			puzzle:   Kind2x2.Empty(),
+			max:      0,
+			expected: 288,
+		},
+		{
+			name:     "conflicting",
+			puzzle:   conflicting,
+			max:      0,
+			expected: 0,
+		},
+		{
+			name: "unsolvable",
+			puzzle: Kind2x2.Create([][]int{
+				{1, 2, 0, 0},
+				{0, 0, 0, 0},
+				{0, 0, 3, 0},
+				{0, 0, 4, 0},
+			}),
+			max:      2,
+			expected: 0,
+		},
+	}
+
+	for _, test := range tests {
+		count := test.puzzle.CountSolutions(test.max)
+		if count != test.expected {
+			t.Errorf("%s: expected %d solutions, got %d", test.name, test.expected, count)
+		}
+
+		if test.max > 0 && test.expected < test.max {
+			solutions := test.puzzle.GetSolutions(SolutionsLimit{MaxSolutions: test.max})
+			if len(solutions) != count {
+				t.Errorf("%s: logic solver found %d solutions, dancing links found %d", test.name, len(solutions), count)
+			}
+		}
+	}
+}
+
+func TestEachSolution(t *testing.T) {
+	puzzle := Kind2x2.Create([][]int{
+		{1, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	})
+
+	seen := map[string]bool{}
+	puzzle.EachSolution(func(solution *Puzzle) bool {
+		if !solution.IsSolved() {
+			t.Errorf("Solution is not solved: %s", solution.String())
+		}
+		if solution.Cells[0].Value != 1 {
+			t.Errorf("Solution changed a given: %s", solution.String())
+		}
+		seen[solution.String()] = true
+		return true
+	})
+	if len(seen) != 72 {
+		t.Errorf("Expected 72 distinct solutions, got %d", len(seen))
+	}
+
+	stopped := 0
+	puzzle.EachSolution(func(solution *Puzzle) bool {
+		stopped++
+		return stopped < 5
+	})
+	if stopped != 5 {
+		t.Errorf("Expected the search to stop after 5 solutions, got %d", stopped)
+	}
+}
+
+func TestCountSolutionsLarge(t *testing.T) {
+	gen := Kind4x4.Generator()
+	solution, _ := gen.Generate()
+	if solution == nil {
+		t.Skip("Failed to generate a 16x16 puzzle")
+	}
+
+	puzzle := solution.Clone()
+	for i := range puzzle.Cells {
+		if i%3 != 0 {
+			puzzle.Cells[i].Value = 0
+		}
+	}
+
+	start := time.Now()
+	count := puzzle.CountSolutions(2)
+	duration := time.Since(start)
+
+	if count == 0 {
+		t.Errorf("Expected the generated solution to be found")
+	}
+	t.Logf("Counted %d solutions of a 16x16 puzzle in %s", count, duration)
+}
@@ -0,0 +1,221 @@
+package sudogo
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Returns a sentence describing the deduction of the log's batch in standard notation, where rows, columns,
+// and boxes are numbered from 1. For example "X-Wing on 4 in rows 2,7 (c3,c8) => r5c3<>4". Chains and
+// forcing steps follow the pattern with their implications in brackets, and coloring steps with their colors.
+func (log SolverLog) Explanation() string {
+	deduction := log.Deduction
+	if deduction == nil {
+		deduction = &SolverDeduction{}
+		if log.Placement && log.After.Value != 0 {
+			deduction.Placements = append(deduction.Placements, SolverCandidate{Position{Col: log.After.Col, Row: log.After.Row}, log.After.Value})
+		} else {
+			deduction.addChange(&log.Before, &log.After)
+		}
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(log.Step.Technique)
+	if len(deduction.Candidates) > 0 {
+		sb.WriteString(" on ")
+		candidates := sliceClone(deduction.Candidates)
+		sort.Ints(candidates)
+		sb.WriteString(explainInts(candidates))
+	}
+	if pattern := explainPattern(deduction); pattern != "" {
+		sb.WriteString(" in ")
+		sb.WriteString(pattern)
+	}
+	if len(deduction.Colors) > 0 {
+		sb.WriteString(" with colors ")
+		sb.WriteString(explainColors(deduction.Colors))
+	}
+	for _, path := range deduction.Implications {
+		sb.WriteString(" [")
+		sb.WriteString(explainImplications(path))
+		sb.WriteString("]")
+	}
+	sb.WriteString(" => ")
+	sb.WriteString(explainResults(deduction))
+	return sb.String()
+}
+
+var explainHouseShort = map[Group]string{GroupRow: "r", GroupCol: "c", GroupBox: "b", GroupHouse: "h"}
+var explainHousePlural = map[Group]string{GroupRow: "rows ", GroupCol: "columns ", GroupBox: "boxes ", GroupHouse: "houses "}
+
+// The houses of the pattern followed by the cells in them, or just the cells when there are no houses.
+func explainPattern(deduction *SolverDeduction) string {
+	cells := explainCells(deduction.Cells)
+	if len(deduction.Houses) == 0 {
+		return cells
+	}
+
+	houses := explainHouses(deduction.Houses)
+	group := deduction.Houses[0].Group
+	sameGroup := sliceIndex(deduction.Houses, func(h SolverHouse) bool { return h.Group != group }) == -1
+
+	// Cells in rows are described by their columns and cells in columns by their rows.
+	if sameGroup && (group == GroupRow || group == GroupCol) {
+		crossGroup := GroupCol
+		if group == GroupCol {
+			crossGroup = GroupRow
+		}
+		crosses := make([]int, 0, len(deduction.Cells))
+		for _, cell := range deduction.Cells {
+			cross := cell.Col
+			if crossGroup == GroupRow {
+				cross = cell.Row
+			}
+			if sliceIndex(crosses, func(c int) bool { return c == cross }) == -1 {
+				crosses = append(crosses, cross)
+			}
+		}
+		sort.Ints(crosses)
+		names := make([]string, 0, len(crosses))
+		for _, cross := range crosses {
+			names = append(names, explainHouseShort[crossGroup]+strconv.Itoa(cross+1))
+		}
+		cells = strings.Join(names, ",")
+	}
+
+	if cells == "" {
+		return houses
+	}
+	return houses + " (" + cells + ")"
+}
+
+// The houses like "r5" or "rows 2,7" when they're all in the same group, otherwise like "r2,b4".
+func explainHouses(houses []SolverHouse) string {
+	group := houses[0].Group
+	sameGroup := true
+	indices := make([]int, 0, len(houses))
+	for _, house := range houses {
+		sameGroup = sameGroup && house.Group == group
+		indices = append(indices, house.Index+1)
+	}
+
+	if sameGroup && len(indices) > 1 {
+		sort.Ints(indices)
+		return explainHousePlural[group] + explainInts(indices)
+	}
+	names := make([]string, 0, len(houses))
+	for _, house := range houses {
+		names = append(names, explainHouseShort[house.Group]+strconv.Itoa(house.Index+1))
+	}
+	return strings.Join(names, ",")
+}
+
+// The placements and eliminations, where candidates removed from the same cells are combined.
+func explainResults(deduction *SolverDeduction) string {
+	results := make([]string, 0)
+	for _, placement := range deduction.Placements {
+		results = append(results, fmt.Sprintf("%s=%d", explainCells([]Position{placement.Position}), placement.Value))
+	}
+
+	values := make([]int, 0)
+	cells := make(map[int][]Position)
+	for _, elimination := range deduction.Eliminations {
+		if _, exists := cells[elimination.Value]; !exists {
+			values = append(values, elimination.Value)
+		}
+		cells[elimination.Value] = append(cells[elimination.Value], elimination.Position)
+	}
+
+	grouped := make([]string, 0)
+	groupedValues := make(map[string][]int)
+	for _, value := range values {
+		key := explainCells(cells[value])
+		if _, exists := groupedValues[key]; !exists {
+			grouped = append(grouped, key)
+		}
+		groupedValues[key] = append(groupedValues[key], value)
+	}
+	for _, key := range grouped {
+		results = append(results, key+"<>"+explainInts(groupedValues[key]))
+	}
+
+	return strings.Join(results, ", ")
+}
+
+// The implications from the first like "r1c2<>4 -> r5c2=4 -> r5c7<>4".
+func explainImplications(path []SolverImplication) string {
+	names := make([]string, 0, len(path))
+	for _, implication := range path {
+		operator := "<>"
+		if implication.On {
+			operator = "="
+		}
+		names = append(names, explainCells([]Position{implication.Position})+operator+strconv.Itoa(implication.Value))
+	}
+	return strings.Join(names, " -> ")
+}
+
+// The candidates of each color like "(r1c2,r5c7),(r5c2,r9c7)", where candidates are only described by their
+// cells when every color has the same value, otherwise like "(r1c2=4,r1c5=7)".
+func explainColors(colors [][]SolverCandidate) string {
+	value := colors[0][0].Value
+	sameValue := true
+	for _, color := range colors {
+		sameValue = sameValue && sliceIndex(color, func(c SolverCandidate) bool { return c.Value != value }) == -1
+	}
+
+	names := make([]string, 0, len(colors))
+	for _, color := range colors {
+		if sameValue {
+			positions := make([]Position, 0, len(color))
+			for _, candidate := range color {
+				positions = append(positions, candidate.Position)
+			}
+			names = append(names, "("+explainCells(positions)+")")
+			continue
+		}
+		sorted := sliceClone(color)
+		sort.Slice(sorted, func(i, j int) bool {
+			if sorted[i].Row != sorted[j].Row {
+				return sorted[i].Row < sorted[j].Row
+			}
+			if sorted[i].Col != sorted[j].Col {
+				return sorted[i].Col < sorted[j].Col
+			}
+			return sorted[i].Value < sorted[j].Value
+		})
+		candidates := make([]string, 0, len(sorted))
+		for _, candidate := range sorted {
+			candidates = append(candidates, explainCells([]Position{candidate.Position})+"="+strconv.Itoa(candidate.Value))
+		}
+		names = append(names, "("+strings.Join(candidates, ",")+")")
+	}
+	return strings.Join(names, ",")
+}
+
+// The positions as cells sorted by row and then column, like "r1c2,r5c3".
+func explainCells(positions []Position) string {
+	sorted := sliceClone(positions)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Row != sorted[j].Row {
+			return sorted[i].Row < sorted[j].Row
+		}
+		return sorted[i].Col < sorted[j].Col
+	})
+	names := make([]string, 0, len(sorted))
+	for _, position := range sorted {
+		names = append(names, fmt.Sprintf("r%dc%d", position.Row+1, position.Col+1))
+	}
+	return strings.Join(names, ",")
+}
+
+// The values separated by commas, like "2,7".
+func explainInts(values []int) string {
+	names := make([]string, 0, len(values))
+	for _, value := range values {
+		names = append(names, strconv.Itoa(value))
+	}
+	return strings.Join(names, ",")
+}
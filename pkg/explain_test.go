@@ -0,0 +1,130 @@
+package sudogo
+
+import "testing"
+
+func TestExplanation(t *testing.T) {
+	xwing := Classic.Create([][]int{
+		{0, 4, 1, 7, 2, 9, 0, 3, 0},
+		{7, 6, 9, 0, 0, 3, 4, 0, 2},
+		{0, 3, 2, 6, 4, 0, 7, 1, 9},
+		{4, 0, 3, 9, 0, 0, 1, 7, 0},
+		{6, 0, 7, 0, 0, 4, 9, 0, 3},
+		{1, 9, 5, 3, 7, 0, 0, 2, 4},
+		{2, 1, 4, 5, 6, 7, 3, 9, 8},
+		{3, 7, 6, 0, 9, 0, 5, 4, 1},
+		{9, 5, 8, 4, 3, 1, 2, 6, 7},
+	})
+
+	simple := Classic.Create([][]int{
+		{5, 3, 0, 0, 7, 0, 0, 0, 0},
+		{6, 0, 0, 1, 9, 5, 0, 0, 0},
+		{0, 9, 8, 0, 0, 0, 0, 6, 0},
+		{8, 0, 0, 0, 6, 0, 0, 0, 3},
+		{4, 0, 0, 8, 0, 3, 0, 0, 1},
+		{7, 0, 0, 0, 2, 0, 0, 0, 6},
+		{0, 6, 0, 0, 0, 0, 2, 8, 0},
+		{0, 0, 0, 4, 1, 9, 0, 0, 5},
+		{0, 0, 0, 0, 8, 0, 0, 7, 9},
+	})
+
+	xchain := Classic.Create([][]int{
+		{4, 9, 2, 7, 8, 1, 0, 6, 0},
+		{3, 8, 6, 2, 4, 5, 7, 9, 1},
+		{7, 5, 1, 0, 0, 3, 2, 4, 8},
+		{9, 0, 3, 0, 0, 8, 0, 5, 7},
+		{0, 0, 0, 0, 7, 0, 0, 0, 0},
+		{0, 6, 7, 4, 0, 0, 0, 0, 0},
+		{2, 3, 9, 0, 0, 0, 0, 0, 6},
+		{0, 0, 5, 0, 0, 0, 0, 0, 0},
+		{0, 7, 0, 0, 0, 0, 9, 3, 0},
+	})
+
+	coloring := Classic.Create([][]int{
+		{0, 1, 0, 0, 0, 9, 2, 0, 4},
+		{9, 0, 2, 0, 1, 0, 0, 6, 8},
+		{0, 0, 7, 2, 0, 0, 9, 1, 5},
+		{0, 2, 0, 0, 0, 5, 6, 0, 9},
+		{0, 8, 6, 1, 9, 2, 0, 4, 7},
+		{4, 0, 0, 0, 0, 0, 1, 0, 2},
+		{0, 0, 4, 0, 5, 1, 0, 0, 6},
+		{0, 0, 0, 0, 2, 0, 4, 0, 3},
+		{0, 0, 0, 0, 6, 4, 0, 0, 1},
+	})
+
+	medusa := Classic.Create([][]int{
+		{0, 0, 0, 0, 0, 0, 0, 8, 9},
+		{0, 0, 0, 1, 0, 0, 7, 0, 4},
+		{0, 5, 0, 8, 0, 0, 3, 0, 2},
+		{6, 3, 2, 4, 5, 8, 9, 7, 1},
+		{0, 0, 0, 0, 0, 0, 0, 0, 5},
+		{0, 7, 0, 0, 0, 9, 0, 0, 8},
+		{0, 4, 0, 9, 6, 1, 8, 2, 3},
+		{2, 9, 8, 7, 3, 4, 0, 0, 6},
+		{1, 6, 3, 5, 8, 2, 4, 9, 7},
+	})
+
+	sum := Classic.Clone()
+	sum.Constraints = []Constraint{
+		&ConstraintSum{
+			Sum:   SumConstant(8),
+			Cells: &[]Position{{0, 0}, {1, 0}, {2, 0}},
+		},
+	}
+
+	tests := []struct {
+		puzzle      Puzzle
+		step        *SolveStep
+		explanation string
+	}{
+		{
+			puzzle:      xwing,
+			step:        StepXWing,
+			explanation: "X-Wing on 5 in rows 2,5 (c5,c8) => r4c5<>5",
+		},
+		{
+			puzzle:      simple,
+			step:        StepNakedSingle,
+			explanation: "Naked Single on 5 in r5c5 => r5c5=5",
+		},
+		{
+			puzzle:      simple,
+			step:        StepHiddenSingle,
+			explanation: "Hidden Single on 8 in b2 (r1c6) => r1c6=8",
+		},
+		{
+			puzzle:      xchain,
+			step:        StepXChain,
+			explanation: "X-Chain on 4 in r4c2,r4c7,r7c6,r7c7,r8c2,r9c3 [r7c6<>4 -> r7c7=4 -> r4c7<>4 -> r4c2=4 -> r8c2<>4 -> r9c3=4] => r9c6<>4",
+		},
+		{
+			puzzle:      coloring,
+			step:        StepSimpleColoring,
+			explanation: "Simple Coloring on 3 in r1c8,r2c7,r5c1,r5c7 with colors (r1c8,r5c7),(r2c7,r5c1) => r1c1<>3",
+		},
+		{
+			puzzle:      medusa,
+			step:        Step3DMedusa,
+			explanation: "3D Medusa on 1,5,6 in r1c6,r1c7,r2c6,r2c8,r3c3,r3c8,r8c7,r8c8 with colors (r1c6=5,r1c7=1,r2c8=5,r3c3=1,r3c8=6,r8c7=5,r8c8=1),(r1c7=5,r2c6=5,r2c8=6,r3c8=1,r8c7=1,r8c8=5) => r1c7,r5c8,r6c8<>6",
+		},
+		{
+			puzzle:      sum.Empty(),
+			step:        StepConstraints,
+			explanation: "Constraints in r1c1 => r1c1<>6,7,8,9",
+		},
+	}
+
+	for _, test := range tests {
+		solver := test.puzzle.Solver()
+		solver.LogEnabled = true
+		test.step.Logic(&solver, SolveLimit{MaxBatches: 1}, test.step)
+
+		if len(solver.Logs) == 0 {
+			t.Errorf("%s: no deduction was made", test.step.Technique)
+			continue
+		}
+		explanation := solver.Logs[0].Explanation()
+		if explanation != test.explanation {
+			t.Errorf("%s: expected %q, got %q", test.step.Technique, test.explanation, explanation)
+		}
+	}
+}
@@ -0,0 +1,535 @@
+package sudogo
+
+// The maximum number of implications from an assumption when SolveLimit.MaxForcingDepth is not given.
+const DefaultMaxForcingDepth = 12
+
+// The maximum number of candidates an assumption can decide when SolveLimit.MaxForcingNodes is not given.
+const DefaultMaxForcingNodes = 400
+
+// The assumptions a forcing step makes.
+type forcingKind int
+
+const (
+	// Each candidate is assumed true and false, one leading to a contradiction decides the candidate.
+	forcingContradiction forcingKind = iota
+	// Each candidate of a cell is assumed true.
+	forcingCell
+	// Each cell with a candidate in a house is assumed to be that candidate.
+	forcingRegion
+	// A candidate is assumed true and then false.
+	forcingDigit
+)
+
+// The candidates decided by assuming a candidate is true or false. A forcing chain only follows links
+// between candidates in the starting grid: a true candidate makes every candidate it sees false, and a
+// false candidate makes the candidates it's strongly linked to true. A forcing net also uses what has
+// been decided: a cell or house left with one place for a candidate makes it true, and a cell or house
+// left with no place is a contradiction.
+type forcingNet struct {
+	solver   *Solver
+	graph    *chainGraph
+	net      bool
+	maxDepth int
+	maxNodes int
+	// The remaining candidates for each cell (by id) after the decisions.
+	candidates []Candidates
+	// The generation a candidate (by index) was decided true or false in.
+	on         []int
+	off        []int
+	depths     []int
+	generation int
+	// The candidate (by chain state) each candidate (by index) was decided from, or -1 for the assumption.
+	parents []int
+	// The decided candidates as chain states in the order they were decided.
+	queue []int
+	// The last candidate (by chain state) decided before the last assumption led to a contradiction, and the
+	// candidate (by chain state) decided both ways or -1.
+	conflict      int
+	conflictState int
+	// The cells of the last contradiction.
+	contradiction []Position
+	// A copy of the puzzle a net gives the remaining candidates to so the kind's constraints can remove more,
+	// the unsolved cells of the copy in each constraint (by index), and the constraints of each cell (by id).
+	trial           *Puzzle
+	constraintCells [][]*Cell
+	cellConstraints [][]int
+	// The constraints (by index) with a cell decided since they were last applied, and the last candidate
+	// (by chain state) decided in each. They are applied once nothing else follows from the decisions.
+	pending      []int
+	pendingState []int
+}
+
+func newForcingNet(solver *Solver, limits SolveLimit, net bool) *forcingNet {
+	graph := newChainGraph(solver, chainLinks{houseStrong: true, houseWeak: true, cellStrong: true, cellWeak: true})
+	f := &forcingNet{
+		solver:     solver,
+		graph:      graph,
+		net:        net,
+		maxDepth:   getMaxForcingDepth(limits),
+		maxNodes:   getMaxForcingNodes(limits),
+		candidates: make([]Candidates, len(solver.Puzzle.Cells)),
+		on:         make([]int, len(graph.nodes)),
+		off:        make([]int, len(graph.nodes)),
+		depths:     make([]int, len(graph.nodes)),
+		parents:    make([]int, len(graph.nodes)),
+		queue:      make([]int, 0, len(graph.nodes)),
+	}
+	if constraints := solver.Puzzle.Kind.Constraints; net && len(constraints) > 0 {
+		trial := solver.Puzzle.Clone()
+		f.trial = &trial
+		f.constraintCells = make([][]*Cell, len(constraints))
+		f.cellConstraints = make([][]int, len(solver.Puzzle.Cells))
+		f.pending = make([]int, 0, len(constraints))
+		f.pendingState = make([]int, len(constraints))
+		for i := range f.pendingState {
+			f.pendingState[i] = -1
+		}
+		size := solver.Puzzle.Kind.Size()
+		for i, constraint := range constraints {
+			cells := make([]*Cell, 0, size)
+			for _, cell := range solver.Unsolved {
+				if constraint.Affects(cell) {
+					cells = append(cells, &trial.Cells[cell.Id])
+				}
+			}
+			// Constraints over more cells than a house, like a rule on every cell, are too costly to apply
+			// after each decision and are left to the other steps.
+			if len(cells) > size {
+				continue
+			}
+			f.constraintCells[i] = cells
+			for _, cell := range cells {
+				f.cellConstraints[cell.Id] = append(f.cellConstraints[cell.Id], i)
+			}
+		}
+	}
+	return f
+}
+
+// The maximum number of implications from an assumption for the given limits.
+func getMaxForcingDepth(limits SolveLimit) int {
+	if limits.MaxForcingDepth > 0 {
+		return limits.MaxForcingDepth
+	}
+	return DefaultMaxForcingDepth
+}
+
+// The maximum number of candidates an assumption can decide for the given limits.
+func getMaxForcingNodes(limits SolveLimit) int {
+	if limits.MaxForcingNodes > 0 {
+		return limits.MaxForcingNodes
+	}
+	return DefaultMaxForcingNodes
+}
+
+// Assumes the candidate (by index) is true or false and decides what follows from it. Returns false if
+// the assumption leads to a contradiction.
+func (f *forcingNet) assume(index int, on bool) bool {
+	f.generation++
+	f.queue = f.queue[:0]
+	for _, pending := range f.pending {
+		f.pendingState[pending] = -1
+	}
+	f.pending = f.pending[:0]
+	for _, cell := range f.solver.Unsolved {
+		f.candidates[cell.Id] = cell.candidates
+	}
+
+	if !f.decide(index, on, 0, -1) {
+		return false
+	}
+
+	for i := 0; i < len(f.queue) || len(f.pending) > 0; i++ {
+		if i == len(f.queue) {
+			if !f.decideConstraints() {
+				return false
+			}
+			if i == len(f.queue) {
+				break
+			}
+		}
+		state := f.queue[i]
+		index := chainStateIndex(state)
+		depth := f.depths[index] + 1
+		if depth > f.maxDepth {
+			continue
+		}
+
+		if chainStateOn(state) {
+			for _, weak := range f.graph.weak[index] {
+				if !f.decide(weak, false, depth, state) {
+					return false
+				}
+			}
+		} else if !f.net {
+			for _, strong := range f.graph.strong[index] {
+				if !f.decide(strong, true, depth, state) {
+					return false
+				}
+			}
+		} else if !f.decideNet(state, depth) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Decides the candidate (by index) is true or false from the parent (by chain state), returns false if it
+// was already decided otherwise.
+func (f *forcingNet) decide(index int, on bool, depth int, parent int) bool {
+	decided, opposite := f.off, f.on
+	if on {
+		decided, opposite = f.on, f.off
+	}
+	if opposite[index] == f.generation {
+		node := f.graph.nodes[index]
+		f.contradict(parent, chainState(index, on), []*Cell{node.cell})
+		return false
+	}
+	if decided[index] == f.generation || len(f.queue) >= f.maxNodes {
+		return true
+	}
+	decided[index] = f.generation
+	f.depths[index] = depth
+	f.parents[index] = parent
+	f.queue = append(f.queue, chainState(index, on))
+	if !on {
+		node := f.graph.nodes[index]
+		f.candidates[node.cell.Id].Set(node.candidate, false)
+	}
+	return true
+}
+
+// Decides what follows from the candidate (by chain state) being false in the cell and each of its houses.
+func (f *forcingNet) decideNet(state int, depth int) bool {
+	node := f.graph.nodes[chainStateIndex(state)]
+	remaining := f.candidates[node.cell.Id]
+	if remaining.Count == 0 {
+		f.contradict(state, -1, []*Cell{node.cell})
+		return false
+	}
+	if remaining.Count == 1 && !f.decide(f.graph.index(node.cell, remaining.First()), true, depth, state) {
+		return false
+	}
+
+	for _, house := range f.solver.cellHouses(node.cell) {
+		var last *Cell
+		holders := 0
+		for _, other := range house {
+			if f.candidates[other.Id].Has(node.candidate) {
+				last = other
+				holders++
+			}
+		}
+		if holders == 0 {
+			f.contradict(state, -1, house)
+			return false
+		}
+		if holders == 1 && !f.decide(f.graph.index(last, node.candidate), true, depth, state) {
+			return false
+		}
+	}
+	if f.trial != nil {
+		for _, index := range f.cellConstraints[node.cell.Id] {
+			if f.pendingState[index] == -1 {
+				f.pending = append(f.pending, index)
+			}
+			f.pendingState[index] = state
+		}
+	}
+	return true
+}
+
+// Decides the candidates the pending constraints remove given the remaining candidates.
+func (f *forcingNet) decideConstraints() bool {
+	for len(f.pending) > 0 {
+		index := f.pending[len(f.pending)-1]
+		f.pending = f.pending[:len(f.pending)-1]
+		state := f.pendingState[index]
+		f.pendingState[index] = -1
+		depth := f.depths[chainStateIndex(state)] + 1
+		if depth > f.maxDepth {
+			continue
+		}
+		constraint := f.solver.Puzzle.Kind.Constraints[index]
+		cells := f.constraintCells[index]
+		for _, trial := range cells {
+			trial.candidates = f.candidates[trial.Id]
+		}
+		for _, trial := range cells {
+			remaining := trial.candidates
+			constraint.RemoveCandidates(trial, f.trial, &remaining)
+			other := &f.solver.Puzzle.Cells[trial.Id]
+			for candidate := trial.candidates.First(); candidate <= trial.candidates.Last(); candidate++ {
+				if trial.candidates.Has(candidate) && !remaining.Has(candidate) && !f.decide(f.graph.index(other, candidate), false, depth, state) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// Remembers the contradiction the last assumption led to after deciding the candidate (by chain state).
+func (f *forcingNet) contradict(last int, conflict int, cells []*Cell) {
+	f.conflict = last
+	f.conflictState = conflict
+	f.contradiction = f.contradiction[:0]
+	for _, cell := range cells {
+		f.contradiction = append(f.contradiction, Position{Col: cell.Col, Row: cell.Row})
+	}
+}
+
+// The candidate (by chain state) as an implication.
+func (f *forcingNet) implication(state int) SolverImplication {
+	node := f.graph.nodes[chainStateIndex(state)]
+	return SolverImplication{SolverCandidate{Position{Col: node.cell.Col, Row: node.cell.Row}, node.candidate}, chainStateOn(state)}
+}
+
+// The implications from the last assumption to the candidate (by chain state) it decided, in order.
+func (f *forcingNet) path(state int) []SolverImplication {
+	path := make([]SolverImplication, f.depths[chainStateIndex(state)]+1)
+	for i := len(path) - 1; i >= 0; i-- {
+		path[i] = f.implication(state)
+		state = f.parents[chainStateIndex(state)]
+	}
+	return path
+}
+
+// The implications from each assumption (by chain state) to each conclusion (by chain state) and the cells
+// of the contradictions they lead to, found by making each assumption again. No assumptions means the
+// conclusions follow from the contradiction the last assumption led to.
+func (f *forcingNet) implications(assumptions []int, conclusions []int) ([][]SolverImplication, []Position) {
+	if len(assumptions) == 0 {
+		return [][]SolverImplication{f.contradictionPath()}, sliceClone(f.contradiction)
+	}
+	paths := make([][]SolverImplication, 0, len(assumptions)*len(conclusions))
+	contradiction := make([]Position, 0)
+	for _, assumption := range assumptions {
+		if !f.assume(chainStateIndex(assumption), chainStateOn(assumption)) {
+			paths = append(paths, f.contradictionPath())
+			contradiction = append(contradiction, f.contradiction...)
+			continue
+		}
+		for _, conclusion := range conclusions {
+			paths = append(paths, f.path(conclusion))
+		}
+	}
+	return paths, contradiction
+}
+
+// The implications from the last assumption to its contradiction.
+func (f *forcingNet) contradictionPath() []SolverImplication {
+	path := f.path(f.conflict)
+	if f.conflictState != -1 {
+		path = append(path, f.implication(f.conflictState))
+	}
+	return path
+}
+
+// Assumes each of the candidates (by chain state) and returns the candidates decided the same way (by chain
+// state) in every assumption which doesn't lead to a contradiction.
+func (f *forcingNet) verity(assumptions []int, agree []int) []int {
+	possible := 0
+	touched := make([]int, 0)
+	for _, assumption := range assumptions {
+		if !f.assume(chainStateIndex(assumption), chainStateOn(assumption)) {
+			continue
+		}
+		possible++
+		for _, state := range f.queue {
+			if agree[state] == 0 {
+				touched = append(touched, state)
+			}
+			agree[state]++
+		}
+	}
+
+	conclusions := make([]int, 0)
+	for _, state := range touched {
+		if possible > 0 && agree[state] == possible {
+			conclusions = append(conclusions, state)
+		}
+		agree[state] = 0
+	}
+	return conclusions
+}
+
+// ==================================================
+// Step: Forcing Chains & Forcing Nets
+//		http://hodoku.sourceforge.net/en/tech_chains.php#fc
+// ==================================================
+// Chains only follow the links between candidates in houses, cells, and linked cells. Nets also remove
+// the candidates the kind's constraints rule out given what has been decided, but not the ones a
+// constraint eliminates from cells outside of it and not for constraints over more cells than a house.
+func CreateStepForcing(technique string, firstCost int, subsequentCost int, net bool, kind forcingKind) *SolveStep {
+	return &SolveStep{
+		Technique:      technique,
+		FirstCost:      firstCost,
+		SubsequentCost: subsequentCost,
+		Logic: func(solver *Solver, limits SolveLimit, step *SolveStep) (int, bool) {
+			placements, removed := 0, 0
+			if solver.CanContinueStep(limits, step) {
+				placements, removed = doForcing(solver, step, newForcingNet(solver, limits, net), kind)
+			}
+			return placements, removed > 0
+		},
+	}
+}
+
+// A candidate which leads to a contradiction when assumed true is removed, and when assumed false is placed.
+var StepForcingChainContradiction = CreateStepForcing("Forcing Chain Contradiction", 8500, 6500, false, forcingContradiction)
+
+// Whichever candidate is true in a cell, the same candidates are decided by chains from it.
+var StepCellForcingChain = CreateStepForcing("Cell Forcing Chain", 9000, 7000, false, forcingCell)
+
+// Whichever cell in a house has a candidate, the same candidates are decided by chains from it.
+var StepRegionForcingChain = CreateStepForcing("Region Forcing Chain", 9000, 7000, false, forcingRegion)
+
+// Whether a candidate is true or false, the same candidates are decided by chains from it.
+var StepDigitForcingChain = CreateStepForcing("Digit Forcing Chain", 9500, 7500, false, forcingDigit)
+
+// A candidate which leads to a contradiction when assumed true is removed, and when assumed false is placed.
+var StepForcingNetContradiction = CreateStepForcing("Forcing Net Contradiction", 10500, 8500, true, forcingContradiction)
+
+// Whichever candidate is true in a cell, the same candidates are decided by nets from it.
+var StepCellForcingNet = CreateStepForcing("Cell Forcing Net", 11000, 9000, true, forcingCell)
+
+// Whichever cell in a house has a candidate, the same candidates are decided by nets from it.
+var StepRegionForcingNet = CreateStepForcing("Region Forcing Net", 11000, 9000, true, forcingRegion)
+
+// Whether a candidate is true or false, the same candidates are decided by nets from it.
+var StepDigitForcingNet = CreateStepForcing("Digit Forcing Net", 11500, 9500, true, forcingDigit)
+
+// Makes assumptions until one leads to a contradiction or a set of assumptions (one of which must be true)
+// all decide a candidate the same way. The candidates decided are placed or removed and the search stops,
+// since the decisions were made on the grid before they were applied.
+func doForcing(solver *Solver, step *SolveStep, f *forcingNet, kind forcingKind) (int, int) {
+	if kind == forcingContradiction {
+		for index, node := range f.graph.nodes {
+			if node.cell == nil {
+				continue
+			}
+			if !f.assume(index, true) {
+				return applyForcing(solver, step, f, []int{chainState(index, false)}, []candidateNode{node}, nil)
+			}
+			if !f.assume(index, false) {
+				return applyForcing(solver, step, f, []int{chainState(index, true)}, []candidateNode{node}, nil)
+			}
+		}
+		return 0, 0
+	}
+
+	agree := make([]int, len(f.graph.nodes)*2)
+	conclude := func(assumptions []int) (int, int) {
+		if len(assumptions) < 2 {
+			return 0, 0
+		}
+		pattern := make([]candidateNode, 0, len(assumptions))
+		for _, assumption := range assumptions {
+			pattern = append(pattern, f.graph.nodes[chainStateIndex(assumption)])
+		}
+		return applyForcing(solver, step, f, f.verity(assumptions, agree), pattern, assumptions)
+	}
+
+	switch kind {
+	case forcingCell:
+		for _, cell := range solver.Unsolved {
+			assumptions := make([]int, 0, cell.candidates.Count)
+			for _, candidate := range cell.Candidates() {
+				assumptions = append(assumptions, chainState(f.graph.index(cell, candidate), true))
+			}
+			if placements, removed := conclude(assumptions); placements+removed > 0 {
+				return placements, removed
+			}
+		}
+
+	case forcingRegion:
+		size := solver.Puzzle.Kind.Size()
+		for _, group := range []Group{GroupCol, GroupRow, GroupBox, GroupHouse} {
+			houses := size
+			if group == GroupHouse {
+				houses = len(solver.Houses)
+			}
+			for houseIndex := 0; houseIndex < houses; houseIndex++ {
+				house := solver.GroupAt(group, houseIndex)
+				for candidate := 1; candidate <= size; candidate++ {
+					assumptions := make([]int, 0, len(house))
+					for _, cell := range house {
+						if cell.HasCandidate(candidate) {
+							assumptions = append(assumptions, chainState(f.graph.index(cell, candidate), true))
+						}
+					}
+					if placements, removed := conclude(assumptions); placements+removed > 0 {
+						return placements, removed
+					}
+				}
+			}
+		}
+
+	case forcingDigit:
+		for index, node := range f.graph.nodes {
+			if node.cell == nil {
+				continue
+			}
+			if placements, removed := conclude([]int{chainState(index, true), chainState(index, false)}); placements+removed > 0 {
+				return placements, removed
+			}
+		}
+	}
+
+	return 0, 0
+}
+
+// Places a candidate decided true and otherwise removes the candidates decided false. Only one candidate
+// is placed so the other steps (like constraints) see it before anything else is placed. Returns the number
+// of placements and removals. The pattern is the candidates assumed, and the implications from the
+// assumptions (by chain state) to what's placed or removed are logged.
+func applyForcing(solver *Solver, step *SolveStep, f *forcingNet, conclusions []int, pattern []candidateNode, assumptions []int) (int, int) {
+	placement := -1
+	eliminated := make([]int, 0)
+	for _, state := range conclusions {
+		node := f.graph.nodes[chainStateIndex(state)]
+		if chainStateOn(state) && node.cell.HasCandidate(node.candidate) {
+			placement = state
+			break
+		} else if !chainStateOn(state) {
+			eliminated = append(eliminated, state)
+		}
+	}
+	applied := eliminated
+	if placement != -1 {
+		applied = []int{placement}
+	}
+
+	// The assumptions are made again for their implications before the grid changes.
+	var paths [][]SolverImplication
+	var contradiction []Position
+	if solver.LogEnabled && len(applied) > 0 {
+		paths, contradiction = f.implications(assumptions, applied)
+	}
+
+	if placement != -1 {
+		node := f.graph.nodes[chainStateIndex(placement)]
+		solver.LogStep(step)
+		logPatternNodes(solver, pattern)
+		solver.LogImplications(paths...)
+		solver.LogContradiction(contradiction)
+		solver.LogBefore(node.cell)
+		solver.SetCell(node.cell, node.candidate)
+		solver.LogPlacement(node.cell)
+		return 1, 0
+	}
+
+	eliminations := make([]candidateNode, 0, len(eliminated))
+	for _, state := range eliminated {
+		eliminations = append(eliminations, f.graph.nodes[chainStateIndex(state)])
+	}
+	removed := removeCandidateNodes(solver, step, eliminations, pattern)
+	if removed > 0 {
+		solver.LogImplications(paths...)
+		solver.LogContradiction(contradiction)
+	}
+	return 0, removed
+}
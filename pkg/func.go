@@ -69,6 +69,16 @@ func sliceIndex[T any](source []T, where func(item T) bool) int {
 	return -1
 }
 
+func sliceWhere[T any](source []T, where func(item T) bool) []T {
+	matches := make([]T, 0, len(source))
+	for _, item := range source {
+		if where(item) {
+			matches = append(matches, item)
+		}
+	}
+	return matches
+}
+
 func sliceClone[T any](source []T) []T {
 	cloned := make([]T, len(source))
 	copy(cloned, source)
@@ -67,6 +67,9 @@ func (gen *Generator) GetRandomUnsolved() *Cell {
 }
 
 func (gen *Generator) Attempt() *Puzzle {
+	if len(gen.Kind.Constraints) > 0 {
+		return gen.attemptSearch()
+	}
 	for !gen.IsComplete() {
 		gen.solver.Solve(SolveLimit{})
 
@@ -86,6 +89,72 @@ func (gen *Generator) Attempt() *Puzzle {
 	return gen.Puzzle()
 }
 
+// Fills in the puzzle with a random value at a time like Attempt, going back to try the other values of a cell
+// when one leaves the puzzle without a solution. Random values rarely complete kinds with constraints otherwise.
+func (gen *Generator) attemptSearch() *Puzzle {
+	gen.solver.Steps = searchSolveSteps
+	nodes := 0
+	solver := gen.search(gen.solver, &nodes)
+	if solver == nil {
+		return nil
+	}
+	gen.solver = *solver
+	gen.solver.Steps = GenerateSolveSteps
+	return gen.Puzzle()
+}
+
+// The most random values a search tries before the attempt fails, since a search which goes wrong early can
+// take a long time to find out and a new attempt is faster.
+const searchMaxNodes = 200
+
+// The steps which fill in the puzzle between random values of a search, the constraints are applied first so
+// the singles aren't placed from stale candidates.
+var searchSolveSteps = []*SolveStep{
+	StepConstraints,
+	StepNakedSingle,
+}
+
+// Solves the puzzle of the solver and tries each candidate of the cell with the fewest in a random order until
+// one leads to a solution. Returns nil if none do or there were too many tries.
+func (gen *Generator) search(solver Solver, nodes *int) *Solver {
+	*nodes++
+	if *nodes > searchMaxNodes {
+		return nil
+	}
+	puzzle, solved := solver.Solve(SolveLimit{})
+	if solved {
+		if puzzle.IsSolved() {
+			return &solver
+		}
+		return nil
+	}
+	if !puzzle.constraintsValid() {
+		return nil
+	}
+
+	min := solver.GetMinCandidateCount()
+	cell := solver.GetCellWhere(func(cell *Cell) bool {
+		return cell.candidates.Count == min
+	})
+	if cell == nil || solver.GetCellWhere(func(cell *Cell) bool { return cell.candidates.Count == 0 }) != nil {
+		return nil
+	}
+
+	candidates := cell.Candidates()
+	gen.Random.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	for _, candidate := range candidates {
+		next := puzzle.Solver()
+		next.Steps = solver.Steps
+		next.SetCell(next.Puzzle.Get(cell.Col, cell.Row), candidate)
+		if found := gen.search(next, nodes); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
 func (gen *Generator) Attempts(tries int) (*Puzzle, int) {
 	var generated *Puzzle = nil
 	for i := 0; i < tries; i++ {
@@ -22,6 +22,25 @@ func TestGenerate(t *testing.T) {
 	fmt.Printf("TestGenerate in %s after %d attempts.\n", duration, attempts)
 }
 
+func TestGenerateConstraints(t *testing.T) {
+	kinds := map[string]*Kind{
+		"non-consecutive": Classic.WithRules(RuleNonConsecutive),
+		"anti-king":       Classic.WithRules(RuleAntiKing),
+	}
+
+	for name, kind := range kinds {
+		for _, seed := range []int64{1, 3} {
+			gen := NewSeededGenerator(kind, seed)
+			solution, _ := gen.Generate()
+			if solution == nil {
+				t.Errorf("%s: failed to generate a puzzle with seed %d", name, seed)
+			} else if !solution.IsSolved() {
+				t.Errorf("%s: generated puzzle with seed %d breaks its constraints:\n%s", name, seed, solution.ToConsoleString())
+			}
+		}
+	}
+}
+
 func TestGenerateClear(t *testing.T) {
 	gen := Classic.Generator()
 
@@ -0,0 +1,122 @@
+package sudogo
+
+// How much of a hint is revealed.
+type HintLevel int
+
+const (
+	// Only the technique which can be used.
+	HintTechnique HintLevel = iota
+	// The technique and where it can be used.
+	HintRegion
+	// The technique, where it can be used, and what it places or eliminates.
+	HintDeduction
+)
+
+// The easiest deduction which can be made on a grid, found without changing the grid.
+type SolverHint struct {
+	// The step which makes the deduction.
+	Step *SolveStep
+	// The cost of the step given the techniques the solver has used so far.
+	Cost int
+	// The houses the deduction is found in, or the boxes of its pattern when the step doesn't search houses.
+	Region []SolverHouse
+	// What was found and what it places or eliminates.
+	Deduction SolverDeduction
+	// The logs of each cell changed by the deduction as if it was applied.
+	Logs []SolverLog
+}
+
+// Returns the deduction the solver would make next without changing the grid, or nil if no step can make
+// one. The steps are tried in order which goes from easiest to hardest. Only the limits on chains and
+// forcing steps and the optional techniques are used from the limits.
+func (solver *Solver) Hint(limits SolveLimit) *SolverHint {
+	if solver.Solved() {
+		return nil
+	}
+
+	stepLimits := SolveLimit{
+		MaxBatches:      1,
+		MaxChainLength:  limits.MaxChainLength,
+		MaxForcingDepth: limits.MaxForcingDepth,
+		MaxForcingNodes: limits.MaxForcingNodes,
+	}
+
+	// Steps which don't make a deduction don't change the grid, so one trial is enough.
+	trial := solver.trial()
+	for _, step := range solver.GetSteps(limits) {
+		step.Logic(&trial, stepLimits, step)
+		if len(trial.Logs) == 0 {
+			continue
+		}
+
+		first := trial.Logs[0]
+		hint := &SolverHint{
+			Step:      step,
+			Cost:      first.Cost,
+			Deduction: *first.Deduction,
+			Logs:      sliceWhere(trial.Logs, func(log SolverLog) bool { return log.Batch == first.Batch }),
+		}
+		hint.Region = hint.Deduction.Houses
+		if len(hint.Region) == 0 {
+			for _, position := range hint.Deduction.Cells {
+				box := SolverHouse{GroupBox, solver.Puzzle.Get(position.Col, position.Row).Box}
+				if sliceIndex(hint.Region, func(h SolverHouse) bool { return h == box }) == -1 {
+					hint.Region = append(hint.Region, box)
+				}
+			}
+		}
+		return hint
+	}
+	return nil
+}
+
+// Returns the deduction a solver would make next on the puzzle without changing it, or nil if there is none.
+func (puzzle *Puzzle) Hint(limits SolveLimit) *SolverHint {
+	solver := puzzle.Solver()
+	return solver.Hint(limits)
+}
+
+// A copy of the solver with its own grid which logs every change.
+func (solver *Solver) trial() Solver {
+	trial := NewSolver(solver.Puzzle)
+	trial.Steps = solver.Steps
+	trial.AssumeUnique = solver.AssumeUnique
+	trial.LogEnabled = true
+	copy(trial.givens, solver.givens)
+	for technique, count := range solver.LogTechniques {
+		trial.LogTechniques[technique] = count
+	}
+	return trial
+}
+
+// The name of the technique which makes the deduction.
+func (hint *SolverHint) Technique() string {
+	return hint.Step.Technique
+}
+
+// Where the deduction is found, like "rows 2,7" or "b4".
+func (hint *SolverHint) Where() string {
+	if len(hint.Region) == 0 {
+		return ""
+	}
+	return explainHouses(hint.Region)
+}
+
+// The deduction in standard notation, like "X-Wing on 4 in rows 2,7 (c3,c8) => r5c3<>4".
+func (hint *SolverHint) Explanation() string {
+	return hint.Logs[0].Explanation()
+}
+
+// Returns the hint revealed up to the given level.
+func (hint *SolverHint) Text(level HintLevel) string {
+	switch level {
+	case HintTechnique:
+		return hint.Technique()
+	case HintRegion:
+		if where := hint.Where(); where != "" {
+			return hint.Technique() + " in " + where
+		}
+		return hint.Technique()
+	}
+	return hint.Explanation()
+}
@@ -0,0 +1,74 @@
+package sudogo
+
+import "testing"
+
+func TestHint(t *testing.T) {
+	puzzle := Classic.Create([][]int{
+		{0, 4, 1, 7, 2, 9, 0, 3, 0},
+		{7, 6, 9, 0, 0, 3, 4, 0, 2},
+		{0, 3, 2, 6, 4, 0, 7, 1, 9},
+		{4, 0, 3, 9, 0, 0, 1, 7, 0},
+		{6, 0, 7, 0, 0, 4, 9, 0, 3},
+		{1, 9, 5, 3, 7, 0, 0, 2, 4},
+		{2, 1, 4, 5, 6, 7, 3, 9, 8},
+		{3, 7, 6, 0, 9, 0, 5, 4, 1},
+		{9, 5, 8, 4, 3, 1, 2, 6, 7},
+	})
+	before := puzzle.ToConsoleCandidatesString()
+
+	hint := puzzle.Hint(SolveLimit{})
+	if hint == nil {
+		t.Fatal("Expected a hint")
+	}
+	if puzzle.ToConsoleCandidatesString() != before {
+		t.Error("The hint changed the puzzle")
+	}
+
+	expected := map[HintLevel]string{
+		HintTechnique: "Empty Rectangle",
+		HintRegion:    "Empty Rectangle in b6",
+		HintDeduction: "Empty Rectangle on 5 in b6 (r2c5,r2c8,r4c9,r5c8) => r4c5<>5",
+	}
+	for level, text := range expected {
+		if hint.Text(level) != text {
+			t.Errorf("Expected hint %q at level %d, got %q", text, level, hint.Text(level))
+		}
+	}
+	if hint.Cost != StepEmptyRectangle.FirstCost || len(hint.Logs) != 1 {
+		t.Errorf("Unexpected cost %d or logs %d", hint.Cost, len(hint.Logs))
+	}
+}
+
+func TestHintSolver(t *testing.T) {
+	puzzle := Classic.Create([][]int{
+		{5, 3, 0, 0, 7, 0, 0, 0, 0},
+		{6, 0, 0, 1, 9, 5, 0, 0, 0},
+		{0, 9, 8, 0, 0, 0, 0, 6, 0},
+		{8, 0, 0, 0, 6, 0, 0, 0, 3},
+		{4, 0, 0, 8, 0, 3, 0, 0, 1},
+		{7, 0, 0, 0, 2, 0, 0, 0, 6},
+		{0, 6, 0, 0, 0, 0, 2, 8, 0},
+		{0, 0, 0, 4, 1, 9, 0, 0, 5},
+		{0, 0, 0, 0, 8, 0, 0, 7, 9},
+	})
+	solution := puzzle.Solver()
+	solution.Solve(SolveLimit{})
+
+	// Placing the value of every hint solves the puzzle.
+	solver := puzzle.Solver()
+	for !solver.Solved() {
+		hint := solver.Hint(SolveLimit{})
+		if hint == nil || len(hint.Deduction.Placements) == 0 {
+			t.Fatal("Expected a hint with a placement")
+		}
+		placement := hint.Deduction.Placements[0]
+		if solution.Puzzle.Get(placement.Col, placement.Row).Value != placement.Value {
+			t.Fatalf("The hint %s is not in the solution", hint.Explanation())
+		}
+		solver.Set(placement.Col, placement.Row, placement.Value)
+	}
+
+	if solver.Hint(SolveLimit{}) != nil {
+		t.Error("Expected no hint for a solved puzzle")
+	}
+}
@@ -0,0 +1,611 @@
+package sudogo
+
+import (
+	"sync"
+)
+
+// ==================================================
+// Constraint: Killer Cage
+// ==================================================
+
+// A killer cage: the cells can't repeat a digit and they sum to Sum. The candidates of a cell are the
+// digits which are in a combination of the cage that the other cells can still take.
+type ConstraintCage struct {
+	Sum   int
+	Cells []Position
+}
+
+func (c *ConstraintCage) Affects(cell *Cell) bool {
+	return cellExists(cell, c.Cells)
+}
+
+func (c *ConstraintCage) RemoveCandidates(cell *Cell, puzzle *Puzzle, remove *Candidates) {
+	placed := Candidates{}
+	others := make([]*Cell, 0, len(c.Cells))
+
+	for _, pos := range c.Cells {
+		other := getAbsoluteCell(puzzle, pos)
+		if other.HasValue() {
+			if placed.Has(other.Value) {
+				remove.Clear()
+				return
+			}
+			placed.Set(other.Value, true)
+		} else if other.Id != cell.Id {
+			others = append(others, other)
+		}
+	}
+
+	possible := Candidates{}
+	for _, combo := range cageCombinations(puzzle.Kind.Digits(), len(c.Cells), c.Sum) {
+		if placed.Differences(combo) {
+			continue
+		}
+		rest := combo
+		rest.Remove(placed)
+		for candidate := rest.First(); candidate <= rest.Last(); candidate++ {
+			if !rest.Has(candidate) || possible.Has(candidate) || !remove.Has(candidate) {
+				continue
+			}
+			digits := rest
+			digits.Set(candidate, false)
+			if cellsFit(others, digits) {
+				possible.Set(candidate, true)
+			}
+		}
+	}
+
+	remove.And(possible)
+}
+
+func (c *ConstraintCage) Validate(puzzle *Puzzle) []Position {
+	return validateCandidates(c, puzzle)
+}
+
+func (c *ConstraintCage) Technique() *SolveStep {
+	return StepKillerCombination
+}
+
+// The digits in every combination of the cage which the cells can still take are removed from the cells
+// outside of the cage which see every cell of the cage that could be the digit.
+func (c *ConstraintCage) Eliminations(puzzle *Puzzle) []SolverCandidate {
+	placed := Candidates{}
+	unsolved := make([]*Cell, 0, len(c.Cells))
+	for _, cell := range c.GetCells(puzzle) {
+		if cell.HasValue() {
+			placed.Set(cell.Value, true)
+		} else {
+			unsolved = append(unsolved, cell)
+		}
+	}
+
+	required := Candidates{}
+	found := false
+	for _, combo := range cageCombinations(puzzle.Kind.Digits(), len(c.Cells), c.Sum) {
+		if placed.Differences(combo) {
+			continue
+		}
+		rest := combo
+		rest.Remove(placed)
+		if !cellsFit(unsolved, rest) {
+			continue
+		}
+		if found {
+			required.And(rest)
+		} else {
+			required = rest
+			found = true
+		}
+	}
+
+	return requiredEliminations(puzzle, unsolved, required)
+}
+
+// The cells of the cage in the puzzle.
+func (c *ConstraintCage) GetCells(puzzle *Puzzle) []*Cell {
+	cells := make([]*Cell, len(c.Cells))
+	for i, pos := range c.Cells {
+		cells[i] = getAbsoluteCell(puzzle, pos)
+	}
+	return cells
+}
+
+// The top left cell of the cage, which is where its sum is drawn.
+func (c *ConstraintCage) First() Position {
+	first := c.Cells[0]
+	for _, pos := range c.Cells[1:] {
+		if pos.Row < first.Row || (pos.Row == first.Row && pos.Col < first.Col) {
+			first = pos
+		}
+	}
+	return first
+}
+
+// Returns the digit combinations (as sets) of the given number of distinct digits from 1 to digits
+// which add up to the sum.
+func CageCombinations(digits int, cells int, sum int) []Candidates {
+	return sliceClone(cageCombinations(digits, cells, sum))
+}
+
+// The combinations by digits, cells, and sum which have been computed, which are shared and should not be modified.
+var cageCombinationTables = map[[3]int][]Candidates{}
+var cageCombinationLock sync.Mutex
+
+func cageCombinations(digits int, cells int, sum int) []Candidates {
+	key := [3]int{digits, cells, sum}
+
+	cageCombinationLock.Lock()
+	defer cageCombinationLock.Unlock()
+
+	combos, exists := cageCombinationTables[key]
+	if !exists {
+		combos = make([]Candidates, 0)
+		var add func(combo Candidates, next int, cells int, sum int)
+		add = func(combo Candidates, next int, cells int, sum int) {
+			if cells == 0 {
+				if sum == 0 {
+					combos = append(combos, combo)
+				}
+				return
+			}
+			// The smallest and largest sums of the remaining cells starting at next.
+			spread := cells * (cells - 1) / 2
+			if sum < cells*next+spread || sum > cells*digits-spread {
+				return
+			}
+			for digit := next; digit <= digits; digit++ {
+				with := combo
+				with.Set(digit, true)
+				add(with, digit+1, cells-1, sum-digit)
+			}
+		}
+		add(Candidates{}, 1, cells, sum)
+		cageCombinationTables[key] = combos
+	}
+	return combos
+}
+
+// The killer cages of the kind's constraints.
+func (kind *Kind) Cages() []*ConstraintCage {
+	cages := make([]*ConstraintCage, 0)
+	for _, constraint := range kind.Constraints {
+		if cage, ok := constraint.(*ConstraintCage); ok {
+			cages = append(cages, cage)
+		}
+	}
+	return cages
+}
+
+// The index of the cage of each cell (by id) or -1 for cells without a cage. Returns false if a cell is in more
+// than one cage or a cage is outside the kind.
+func (kind *Kind) CageIndexes() ([]int, bool) {
+	size := kind.Size()
+	indexes := make([]int, size*size)
+	for i := range indexes {
+		indexes[i] = -1
+	}
+	for cageIndex, cage := range kind.Cages() {
+		for _, pos := range cage.Cells {
+			if pos.Col < 0 || pos.Row < 0 || pos.Col >= size || pos.Row >= size {
+				return indexes, false
+			}
+			id := pos.Row*size + pos.Col
+			if indexes[id] != -1 {
+				return indexes, false
+			}
+			indexes[id] = cageIndex
+		}
+	}
+	return indexes, true
+}
+
+// ==================================================
+// Step: Killer Combination
+//		https://www.sudokuwiki.org/Killer_Combinations
+// ==================================================
+var StepKillerCombination = CreateStepConstraint("Killer Combination", 300, 150)
+
+// ==================================================
+// Step: Innies & Outies
+//		https://www.sudokuwiki.org/Killer_Sudoku
+//
+// ==================================================
+// The digits of a row, column, or box add up to the same total, so the cells of the house which aren't in a
+// cage inside it (the innies) add up to the total minus those cages. When the house is covered by cages, the
+// cells of those cages outside the house (the outies) add up to the cages minus the total. Sums of up to
+// four unsolved cells are used to remove candidates.
+var StepInniesOuties = &SolveStep{
+	Technique:      "Innies & Outies",
+	FirstCost:      400,
+	SubsequentCost: 250,
+	Logic: func(solver *Solver, limits SolveLimit, step *SolveStep) (int, bool) {
+		kind := solver.Puzzle.Kind
+		cages := kind.Cages()
+		if len(cages) == 0 {
+			return 0, false
+		}
+		cageIndexes, disjoint := kind.CageIndexes()
+		if !disjoint {
+			return 0, false
+		}
+
+		size := kind.Size()
+		total := size * (size + 1) / 2
+		removed := 0
+
+		for _, group := range []Group{GroupRow, GroupCol, GroupBox} {
+			for index := 0; index < size; index++ {
+				if !solver.CanContinueStep(limits, step) {
+					return 0, removed > 0
+				}
+
+				house := SolverHouse{group, index}
+				inside := make([]int, len(cages))
+				for i := range solver.Puzzle.Cells {
+					cell := &solver.Puzzle.Cells[i]
+					if cell.GetGroup(group) == index && cageIndexes[cell.Id] != -1 {
+						inside[cageIndexes[cell.Id]]++
+					}
+				}
+
+				innies := make([]*Cell, 0, size)
+				innieSum := total
+				for cageIndex, cage := range cages {
+					if inside[cageIndex] == len(cage.Cells) {
+						innieSum -= cage.Sum
+					}
+				}
+				covered := true
+				for i := range solver.Puzzle.Cells {
+					cell := &solver.Puzzle.Cells[i]
+					if cell.GetGroup(group) != index {
+						continue
+					}
+					cageIndex := cageIndexes[cell.Id]
+					if cageIndex == -1 {
+						covered = false
+					}
+					if cageIndex == -1 || inside[cageIndex] != len(cages[cageIndex].Cells) {
+						innies = append(innies, cell)
+					}
+				}
+				removed += doRemoveCageSum(solver, step, house, innies, innieSum, true)
+
+				if covered {
+					outies := make([]*Cell, 0, size)
+					outieSum := -total
+					for cageIndex, cage := range cages {
+						if inside[cageIndex] == 0 {
+							continue
+						}
+						outieSum += cage.Sum
+						if inside[cageIndex] == len(cage.Cells) {
+							continue
+						}
+						for _, outie := range cage.GetCells(&solver.Puzzle) {
+							if outie.GetGroup(group) != index {
+								outies = append(outies, outie)
+							}
+						}
+					}
+					removed += doRemoveCageSum(solver, step, house, outies, outieSum, false)
+				}
+			}
+		}
+
+		return 0, removed > 0
+	},
+}
+
+// Removes the candidates of the cells which can't be part of the cells adding up to the sum, where the cells
+// are all different when distinct or otherwise when they see each other. Returns the number of candidates removed.
+func doRemoveCageSum(solver *Solver, step *SolveStep, house SolverHouse, cells []*Cell, sum int, distinct bool) int {
+	unsolved := make([]*Cell, 0, len(cells))
+	for _, cell := range cells {
+		if cell.HasValue() {
+			sum -= cell.Value
+		} else {
+			unsolved = append(unsolved, cell)
+		}
+	}
+	if len(unsolved) == 0 || len(unsolved) > 4 {
+		return 0
+	}
+
+	possible := make([]Candidates, len(unsolved))
+	chosen := make([]int, len(unsolved))
+	var search func(i int, remaining int)
+	search = func(i int, remaining int) {
+		if i == len(unsolved) {
+			if remaining == 0 {
+				for k, value := range chosen {
+					possible[k].Set(value, true)
+				}
+			}
+			return
+		}
+		for _, candidate := range unsolved[i].Candidates() {
+			if candidate > remaining {
+				break
+			}
+			seen := false
+			for k := 0; k < i && !seen; k++ {
+				seen = chosen[k] == candidate && (distinct || unsolved[k].InGroup(unsolved[i]))
+			}
+			if !seen {
+				chosen[i] = candidate
+				search(i+1, remaining-candidate)
+			}
+		}
+	}
+	search(0, sum)
+
+	removed := 0
+	for i, cell := range unsolved {
+		if !cell.candidates.Differences(possible[i]) {
+			continue
+		}
+		if removed == 0 {
+			solver.LogStep(step)
+			solver.LogHouse(house.Group, house.Index)
+			solver.LogPattern(unsolved)
+		}
+		solver.LogBefore(cell)
+		removed += cell.candidates.And(possible[i])
+		solver.LogAfter(cell)
+	}
+	return removed
+}
+
+// ==================================================
+// Generator: Killer Cages
+// ==================================================
+
+// The limits on generating killer cages from a solution. The cost limits of the solve limit are the target
+// difficulty of the generated puzzle.
+type CageLimit struct {
+	SolveLimit
+	// The smallest and largest number of cells in a cage, which default to 2 and 5.
+	MinSize int
+	MaxSize int
+	// The most cells which can be given when the cages alone can't be solved.
+	MaxGivens int
+	// The number of partitions to try before giving up, which defaults to 64.
+	MaxAttempts int
+}
+
+// Generates killer cages for a solution of the generator's kind. The solution is partitioned into random
+// cages which are split (or cells are given) until the solver can solve the cages, and the puzzle is returned
+// when its cost is in the limits. The puzzle's kind has the cages as constraints. Returns nil if no
+// cages could be found in the attempts and the number of attempts.
+func (gen *Generator) GenerateCages(solution *Puzzle, limits CageLimit) (*Puzzle, int) {
+	if solution == nil || !solution.IsSolved() {
+		return nil, 0
+	}
+	minSize := limits.MinSize
+	if minSize <= 0 {
+		minSize = 2
+	}
+	maxSize := limits.MaxSize
+	if maxSize <= 0 {
+		maxSize = 5
+	}
+	maxSize = Max(minSize, maxSize)
+	attempts := limits.MaxAttempts
+	if attempts <= 0 {
+		attempts = 64
+	}
+
+	for attempt := 1; attempt <= attempts; attempt++ {
+		cages := gen.partitionCages(solution, minSize, maxSize)
+		givens := make([]Position, 0, limits.MaxGivens)
+
+		for {
+			kind := gen.Kind.Clone()
+			for _, cells := range cages {
+				sum := 0
+				for _, pos := range cells {
+					sum += solution.Get(pos.Col, pos.Row).Value
+				}
+				kind.Constraints = append(kind.Constraints, &ConstraintCage{Sum: sum, Cells: cells})
+			}
+			puzzle := kind.Empty()
+			for _, pos := range givens {
+				puzzle.Set(pos.Col, pos.Row, solution.Get(pos.Col, pos.Row).Value)
+			}
+
+			solver := puzzle.Solver()
+			solved, isSolved := solver.Solve(SolveLimit{})
+			if isSolved {
+				cost := solver.GetLastLog().RunningCost
+				if limits.MaxCost > 0 && cost > limits.MaxCost && len(givens) < limits.MaxGivens {
+					givens = append(givens, gen.randomEmptyPosition(&puzzle))
+					continue
+				}
+				if (limits.MaxCost == 0 || cost <= limits.MaxCost) && (limits.MinCost == 0 || cost >= limits.MinCost) {
+					return &puzzle, attempt
+				}
+				break
+			}
+
+			// The solver is stuck on a cell, split its cage or give it.
+			stuck := randomPointer(gen.Random, pointersWhere(solved.Cells, func(cell *Cell) bool {
+				return cell.Empty()
+			}))
+			if stuck == nil {
+				break
+			}
+			stuckPos := Position{stuck.Col, stuck.Row}
+			if split := gen.splitCage(cages, stuckPos, minSize); split != nil {
+				cages = split
+			} else if len(givens) < limits.MaxGivens {
+				givens = append(givens, stuckPos)
+			} else {
+				break
+			}
+		}
+	}
+
+	return nil, attempts
+}
+
+// A random empty cell of the puzzle.
+func (gen *Generator) randomEmptyPosition(puzzle *Puzzle) Position {
+	cell := randomPointer(gen.Random, pointersWhere(puzzle.Cells, func(cell *Cell) bool {
+		return cell.Empty()
+	}))
+	return Position{cell.Col, cell.Row}
+}
+
+// Partitions the solution into connected cages of random sizes where no digit repeats in a cage. Cages which
+// can't grow to the min size are merged into a neighboring cage when possible.
+func (gen *Generator) partitionCages(solution *Puzzle, minSize int, maxSize int) [][]Position {
+	size := gen.Kind.Size()
+	cageOf := make([]int, size*size)
+	for i := range cageOf {
+		cageOf[i] = -1
+	}
+	cages := make([][]Position, 0, size*size/minSize)
+
+	neighbors := func(pos Position) []Position {
+		adjacent := make([]Position, 0, 4)
+		for _, offset := range []Position{{0, -1}, {1, 0}, {0, 1}, {-1, 0}} {
+			next := Position{pos.Col + offset.Col, pos.Row + offset.Row}
+			if next.Col >= 0 && next.Row >= 0 && next.Col < size && next.Row < size {
+				adjacent = append(adjacent, next)
+			}
+		}
+		return adjacent
+	}
+	id := func(pos Position) int {
+		return pos.Row*size + pos.Col
+	}
+	digits := func(cells []Position) Candidates {
+		values := Candidates{}
+		for _, pos := range cells {
+			values.Set(solution.Get(pos.Col, pos.Row).Value, true)
+		}
+		return values
+	}
+
+	for _, start := range gen.Random.Perm(size * size) {
+		if cageOf[start] != -1 {
+			continue
+		}
+		cageIndex := len(cages)
+		cells := []Position{{start % size, start / size}}
+		cageOf[start] = cageIndex
+		values := digits(cells)
+		target := minSize + gen.Random.Intn(maxSize-minSize+1)
+
+		for len(cells) < target {
+			frontier := make([]Position, 0)
+			for _, pos := range cells {
+				for _, next := range neighbors(pos) {
+					if cageOf[id(next)] == -1 && !values.Has(solution.Get(next.Col, next.Row).Value) {
+						frontier = append(frontier, next)
+					}
+				}
+			}
+			if len(frontier) == 0 {
+				break
+			}
+			next := frontier[gen.Random.Intn(len(frontier))]
+			cageOf[id(next)] = cageIndex
+			values.Set(solution.Get(next.Col, next.Row).Value, true)
+			cells = append(cells, next)
+		}
+
+		cages = append(cages, cells)
+	}
+
+	for cageIndex, cells := range cages {
+		if len(cells) == 0 || len(cells) >= minSize {
+			continue
+		}
+		values := digits(cells)
+		for _, pos := range cells {
+			merged := false
+			for _, next := range neighbors(pos) {
+				other := cageOf[id(next)]
+				if other == cageIndex || len(cages[other])+len(cells) > maxSize || values.Overlaps(digits(cages[other])) {
+					continue
+				}
+				for _, moved := range cells {
+					cageOf[id(moved)] = other
+				}
+				cages[other] = append(cages[other], cells...)
+				cages[cageIndex] = nil
+				merged = true
+				break
+			}
+			if merged {
+				break
+			}
+		}
+	}
+
+	return sliceWhere(cages, func(cells []Position) bool {
+		return len(cells) > 0
+	})
+}
+
+// Splits the cage with the position into two connected cages of at least the min size, where the cage with
+// the position is grown from it. Returns nil if the cage can't be split.
+func (gen *Generator) splitCage(cages [][]Position, pos Position, minSize int) [][]Position {
+	cageIndex := sliceIndex(cages, func(cells []Position) bool {
+		return positionExists(pos, cells)
+	})
+	if cageIndex == -1 {
+		return nil
+	}
+	cells := cages[cageIndex]
+	if len(cells) < minSize*2 {
+		return nil
+	}
+
+	adjacent := func(a Position, b Position) bool {
+		return AbsInt(a.Col-b.Col)+AbsInt(a.Row-b.Row) == 1
+	}
+	connected := func(part []Position) bool {
+		reached := []Position{part[0]}
+		for i := 0; i < len(reached); i++ {
+			for _, other := range part {
+				if adjacent(reached[i], other) && !positionExists(other, reached) {
+					reached = append(reached, other)
+				}
+			}
+		}
+		return len(reached) == len(part)
+	}
+
+	for tries := 0; tries < len(cells); tries++ {
+		target := minSize + gen.Random.Intn(len(cells)-minSize*2+1)
+		first := []Position{pos}
+		for len(first) < target {
+			frontier := make([]Position, 0)
+			for _, a := range first {
+				for _, b := range cells {
+					if adjacent(a, b) && !positionExists(b, first) {
+						frontier = append(frontier, b)
+					}
+				}
+			}
+			if len(frontier) == 0 {
+				break
+			}
+			first = append(first, frontier[gen.Random.Intn(len(frontier))])
+		}
+		second := sliceWhere(cells, func(cell Position) bool {
+			return !positionExists(cell, first)
+		})
+		if len(first) < minSize || len(second) < minSize || !connected(second) {
+			continue
+		}
+		split := sliceClone(cages)
+		split[cageIndex] = first
+		return append(split, second)
+	}
+
+	return nil
+}
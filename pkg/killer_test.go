@@ -0,0 +1,280 @@
+package sudogo
+
+import (
+	"fmt"
+	"testing"
+)
+
+// A 4x4 killer with a unique solution once the top left 1 is given:
+//
+//	1 2 | 3 4
+//	3 4 | 1 2
+//	----+----
+//	2 1 | 4 3
+//	4 3 | 2 1
+var killer2x2 = func() *Kind {
+	kind := Kind2x2.Clone()
+	kind.Constraints = []Constraint{
+		&ConstraintCage{Sum: 3, Cells: []Position{{0, 0}, {1, 0}}},
+		&ConstraintCage{Sum: 7, Cells: []Position{{2, 0}, {3, 0}}},
+		&ConstraintCage{Sum: 5, Cells: []Position{{0, 1}, {0, 2}}},
+		&ConstraintCage{Sum: 5, Cells: []Position{{1, 1}, {1, 2}}},
+		&ConstraintCage{Sum: 3, Cells: []Position{{2, 1}, {3, 1}}},
+		&ConstraintCage{Sum: 7, Cells: []Position{{0, 3}, {1, 3}}},
+		&ConstraintCage{Sum: 6, Cells: []Position{{2, 2}, {2, 3}}},
+		&ConstraintCage{Sum: 4, Cells: []Position{{3, 2}, {3, 3}}},
+	}
+	return kind
+}()
+
+func TestCageCombinations(t *testing.T) {
+	tests := []struct {
+		digits   int
+		cells    int
+		sum      int
+		expected string
+	}{
+		{digits: 9, cells: 2, sum: 3, expected: "[[1 2]]"},
+		{digits: 9, cells: 3, sum: 24, expected: "[[7 8 9]]"},
+		{digits: 9, cells: 2, sum: 10, expected: "[[1 9] [2 8] [3 7] [4 6]]"},
+		{digits: 9, cells: 9, sum: 45, expected: "[[1 2 3 4 5 6 7 8 9]]"},
+		{digits: 9, cells: 2, sum: 18, expected: "[]"},
+		{digits: 4, cells: 3, sum: 9, expected: "[[2 3 4]]"},
+	}
+
+	for _, test := range tests {
+		combos := CageCombinations(test.digits, test.cells, test.sum)
+		slices := make([][]int, len(combos))
+		for i := range combos {
+			slices[i] = combos[i].ToSlice()
+		}
+		actual := fmt.Sprintf("%v", slices)
+		if actual != test.expected {
+			t.Errorf("Combinations of %d cells summing to %d: expected %s, got %s", test.cells, test.sum, test.expected, actual)
+		}
+	}
+}
+
+func TestConstraintCage(t *testing.T) {
+	tests := []struct {
+		puzzle        Puzzle
+		constraint    ConstraintCage
+		cellsExpected []string
+	}{
+		{
+			puzzle:        Classic.Empty(),
+			constraint:    ConstraintCage{Sum: 23, Cells: []Position{{0, 0}, {1, 0}, {2, 0}}},
+			cellsExpected: []string{"[6 8 9]", "[6 8 9]", "[6 8 9]"},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 9, 0, 0, 0, 0, 0, 0, 0},
+			}),
+			constraint:    ConstraintCage{Sum: 23, Cells: []Position{{0, 0}, {1, 0}, {2, 0}}},
+			cellsExpected: []string{"[6 8 9]", "[6 8]", "[6 8 9]"},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{0, 0, 8, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0, 0},
+			}),
+			constraint:    ConstraintCage{Sum: 23, Cells: []Position{{0, 0}, {1, 0}, {2, 0}}},
+			cellsExpected: []string{"[6 9]", "[6 9]", "[]"},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{7, 5, 0, 0, 0, 0, 0, 0, 0},
+			}),
+			constraint:    ConstraintCage{Sum: 30, Cells: []Position{{0, 0}, {1, 0}, {2, 0}}},
+			cellsExpected: []string{"[]", "[]", "[]"}, // 18
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{7, 5, 0, 0, 0, 0, 0, 0, 0},
+			}),
+			constraint:    ConstraintCage{Sum: 10, Cells: []Position{{0, 0}, {1, 0}, {2, 0}}},
+			cellsExpected: []string{"[]", "[]", "[]"}, // -2
+		},
+	}
+
+	for testIndex, test := range tests {
+		for cellIndex, pos := range test.constraint.Cells {
+			cell := test.puzzle.Get(pos.Col, pos.Row)
+			cand := cell.candidates
+
+			if cell.Empty() {
+				test.constraint.RemoveCandidates(cell, &test.puzzle, &cand)
+			}
+
+			expected := test.cellsExpected[cellIndex]
+			actual := fmt.Sprintf("%v", cand.ToSlice())
+
+			if expected != actual {
+				t.Errorf("TestConstraintCage cells failed at index %d for test %d, actual: %s, expected: %s", cellIndex, testIndex, actual, expected)
+			}
+		}
+	}
+}
+
+func TestInniesOuties(t *testing.T) {
+	// The first 8 cells of the top row add up to 36, so the last cell is 9 and the cell below it finishes its cage.
+	kind := Classic.Clone()
+	kind.Constraints = []Constraint{
+		&ConstraintCage{Sum: 36, Cells: []Position{{0, 0}, {1, 0}, {2, 0}, {3, 0}, {4, 0}, {5, 0}, {6, 0}, {7, 0}}},
+		&ConstraintCage{Sum: 12, Cells: []Position{{8, 0}, {8, 1}}},
+	}
+
+	puzzle := kind.Empty()
+	solver := puzzle.Solver()
+	solver.LogEnabled = true
+	StepInniesOuties.Logic(&solver, SolveLimit{}, StepInniesOuties)
+
+	innie := solver.Puzzle.Get(8, 0).Candidates()
+	if fmt.Sprintf("%v", innie) != "[9]" {
+		t.Errorf("Expected the innie to be 9, got %v", innie)
+	}
+	outie := solver.Puzzle.Get(8, 1).Candidates()
+	if fmt.Sprintf("%v", outie) != "[3]" {
+		t.Errorf("Expected the outie to be 3, got %v", outie)
+	}
+	if solver.LogTechniques["Innies & Outies"] == 0 {
+		t.Errorf("Expected the technique to be logged")
+	}
+}
+
+func TestKillerCombination(t *testing.T) {
+	// A cage of 3 in two cells has a 1 and 2, which the rest of the row and box can't have.
+	kind := Classic.Clone()
+	kind.Constraints = []Constraint{
+		&ConstraintCage{Sum: 3, Cells: []Position{{0, 0}, {1, 0}}},
+	}
+
+	puzzle := kind.Empty()
+	solver := puzzle.Solver()
+	solver.LogEnabled = true
+	StepKillerCombination.Logic(&solver, SolveLimit{}, StepKillerCombination)
+
+	if solver.LogTechniques["Killer Combination"] != 1 {
+		t.Fatalf("Expected one killer combination, got %d", solver.LogTechniques["Killer Combination"])
+	}
+	expected := map[Position]string{
+		{0, 0}: "[1 2]",
+		{1, 0}: "[1 2]",
+		{5, 0}: "[3 4 5 6 7 8 9]",
+		{2, 2}: "[3 4 5 6 7 8 9]",
+		{0, 5}: "[1 2 3 4 5 6 7 8 9]",
+	}
+	for pos, candidates := range expected {
+		actual := fmt.Sprintf("%v", solver.Puzzle.Get(pos.Col, pos.Row).Candidates())
+		if actual != candidates {
+			t.Errorf("Expected r%dc%d to be %s, got %s", pos.Row+1, pos.Col+1, candidates, actual)
+		}
+	}
+}
+
+func TestKillerCombinationFallback(t *testing.T) {
+	// Without the technique's step the cage is applied by the constraints step.
+	puzzle := killer2x2.Empty()
+	solver := puzzle.Solver()
+	solver.Steps = []*SolveStep{StepConstraints}
+	StepConstraints.Logic(&solver, SolveLimit{}, StepConstraints)
+
+	if solver.LogTechniques["Constraints"] == 0 {
+		t.Errorf("Expected the constraints step to apply the cages")
+	}
+
+	solver = puzzle.Solver()
+	StepConstraints.Logic(&solver, SolveLimit{}, StepConstraints)
+
+	if solver.LogTechniques["Constraints"] != 0 {
+		t.Errorf("Expected the constraints step to leave the cages to their technique")
+	}
+}
+
+func TestKillerSolve(t *testing.T) {
+	puzzle := killer2x2.Create([][]int{
+		{1, 0, 0, 0},
+	})
+
+	solutions := puzzle.GetSolutions(SolutionsLimit{MaxSolutions: 2})
+	if len(solutions) != 1 {
+		t.Fatalf("Expected a unique solution, got %d", len(solutions))
+	}
+
+	solver := puzzle.Solver()
+	solution, solved := solver.Solve(SolveLimit{})
+	if !solved {
+		t.Fatalf("Failed to solve the killer")
+	}
+	if solution.String() != "1234341221434321" {
+		t.Errorf("Unexpected solution %s", solution.String())
+	}
+}
+
+func TestKillerPrint(t *testing.T) {
+	puzzle := killer2x2.Create([][]int{
+		{1, 0, 0, 0},
+	})
+
+	println(puzzle.ToConsoleString())
+	println(puzzle.ToConsoleCandidatesString())
+}
+
+func TestGenerateCages(t *testing.T) {
+	gen := NewSeededGenerator(Classic, 1)
+	solution, _ := gen.Generate()
+
+	limits := CageLimit{MinSize: 2, MaxSize: 5, MaxGivens: 4}
+	puzzle, _ := gen.GenerateCages(solution, limits)
+	if puzzle == nil {
+		t.Fatalf("Failed to generate cages")
+	}
+
+	cageIndexes, disjoint := puzzle.Kind.CageIndexes()
+	if !disjoint {
+		t.Errorf("Expected the cages to not overlap")
+	}
+	for id, cageIndex := range cageIndexes {
+		if cageIndex == -1 {
+			t.Errorf("Expected cell %d to be in a cage", id)
+		}
+	}
+	for _, cage := range puzzle.Kind.Cages() {
+		if len(cage.Cells) > limits.MaxSize {
+			t.Errorf("Expected a cage of at most %d cells, got %d", limits.MaxSize, len(cage.Cells))
+		}
+	}
+
+	givens := len(pointersWhere(puzzle.Cells, func(cell *Cell) bool {
+		return cell.HasValue()
+	}))
+	if givens > limits.MaxGivens {
+		t.Errorf("Expected at most %d givens, got %d", limits.MaxGivens, givens)
+	}
+
+	solver := puzzle.Solver()
+	solved, isSolved := solver.Solve(SolveLimit{})
+	if !isSolved || solved.String() != solution.String() {
+		t.Errorf("Expected the solver to find the solution")
+	}
+	if !puzzle.HasUniqueSolution() {
+		t.Errorf("Expected a unique solution")
+	}
+
+	puzzle.PrintConsole()
+}
@@ -29,8 +29,35 @@ var Kind4x4 = &Kind{
 	BoxSize: Size{4, 4},
 }
 
+// The classic 9x9 puzzle where both diagonals also have digits 1-9.
+var SudokuX = &Kind{
+	BoxSize: Size{3, 3},
+	Houses:  DiagonalHouses(9),
+}
+
+// The classic 9x9 puzzle with four more 3x3 windows (also known as Hyper Sudoku) which have digits 1-9.
+var Windoku = &Kind{
+	BoxSize: Size{3, 3},
+	Houses:  WindowHouses(3, 3),
+}
+
+// The classic 9x9 puzzle where the center cells of the boxes also have digits 1-9.
+var CenterDot = &Kind{
+	BoxSize: Size{3, 3},
+	Houses:  CenterDotHouses(3, 3),
+}
+
 type Kind struct {
-	BoxSize     Size
+	BoxSize Size
+	// The box of each cell by row and then column. When given the boxes are irregular (jigsaw) regions and
+	// BoxSize only determines the number of digits and how candidates are laid out in a printed cell.
+	Regions [][]int
+	// Extra houses which have every digit once like the rows, columns, and boxes. Each house has a cell for
+	// every digit.
+	Houses [][]Position
+	// The relative positions of the cells each cell sees besides its houses, which can't have the same digit,
+	// like the knight's moves of Anti-Knight.
+	Links       []Position
 	Constraints []Constraint
 }
 
@@ -43,13 +70,108 @@ func NewKind(boxWidth int, boxHeight int) *Kind {
 	}
 }
 
+// Creates a kind with irregular (jigsaw) boxes given the box of each cell by row and then column. The
+// kind is nil if the regions are not square or each box doesn't have one cell for every digit.
+func NewJigsawKind(regions [][]int) *Kind {
+	size := len(regions)
+	boxHeight := 1
+	for h := 2; h*h <= size; h++ {
+		if size%h == 0 {
+			boxHeight = h
+		}
+	}
+	kind := &Kind{
+		BoxSize: Size{
+			Width:  size / boxHeight,
+			Height: boxHeight,
+		},
+		Regions: regions,
+	}
+	if size == 0 || !kind.ValidRegions() {
+		return nil
+	}
+	return kind
+}
+
 func (kind *Kind) Clone() *Kind {
 	return &Kind{
 		BoxSize:     kind.BoxSize,
+		Regions:     sliceClone(kind.Regions),
+		Houses:      sliceClone(kind.Houses),
+		Links:       sliceClone(kind.Links),
 		Constraints: sliceClone(kind.Constraints),
 	}
 }
 
+// Whether the boxes are irregular (jigsaw) regions instead of rectangles.
+func (kind *Kind) Irregular() bool {
+	return len(kind.Regions) > 0
+}
+
+// Returns whether the regions have a box for every cell and each box has one cell for every digit.
+// Kinds without regions are valid.
+func (kind *Kind) ValidRegions() bool {
+	if !kind.Irregular() {
+		return true
+	}
+	size := kind.Size()
+	if len(kind.Regions) != size {
+		return false
+	}
+	counts := make([]int, size)
+	for _, row := range kind.Regions {
+		if len(row) != size {
+			return false
+		}
+		for _, box := range row {
+			if box < 0 || box >= size {
+				return false
+			}
+			counts[box]++
+		}
+	}
+	for _, count := range counts {
+		if count != size {
+			return false
+		}
+	}
+	return true
+}
+
+// The box of the cell at the given column and row.
+func (kind *Kind) BoxAt(col int, row int) int {
+	if kind.Irregular() {
+		return kind.Regions[row][col]
+	}
+	boxsWide, _, boxWidth, boxHeight, _ := kind.GetDimensions()
+	return ((row / boxHeight) * boxsWide) + (col / boxWidth)
+}
+
+// The most cells a box can share with a row (GroupRow) or column (GroupCol).
+func (kind *Kind) BoxOverlap(line Group) int {
+	if !kind.Irregular() {
+		if line == GroupRow {
+			return kind.BoxSize.Width
+		}
+		return kind.BoxSize.Height
+	}
+	size := kind.Size()
+	most := 0
+	for i := 0; i < size; i++ {
+		counts := make([]int, size)
+		for j := 0; j < size; j++ {
+			col, row := j, i
+			if line == GroupCol {
+				col, row = i, j
+			}
+			box := kind.Regions[row][col]
+			counts[box]++
+			most = Max(most, counts[box])
+		}
+	}
+	return most
+}
+
 // The width, height, and number of digits in this puzzle kind.
 func (kind *Kind) Size() int {
 	return kind.BoxSize.Width * kind.BoxSize.Height
@@ -60,6 +182,15 @@ func (kind *Kind) Digits() int {
 	return kind.Size()
 }
 
+// The values a cell can have in this puzzle kind, from 1 to the number of digits.
+func (kind *Kind) Candidates() []int {
+	candidates := make([]int, kind.Digits())
+	for i := range candidates {
+		candidates[i] = i + 1
+	}
+	return candidates
+}
+
 // How many boxes wide the puzzle would be.
 func (kind *Kind) BoxesWide() int {
 	return kind.BoxSize.Height
@@ -104,6 +235,99 @@ func (kind *Kind) Generator() Generator {
 	return NewGenerator(kind)
 }
 
+// The indices of the extra houses the cell is in.
+func (kind *Kind) HousesFor(cell *Cell) []int {
+	var houses []int
+	for i, house := range kind.Houses {
+		if sliceIndex(house, func(p Position) bool { return p.Col == cell.Col && p.Row == cell.Row }) != -1 {
+			houses = append(houses, i)
+		}
+	}
+	return houses
+}
+
+// The ids of the cells the cell sees through the kind's links.
+func (kind *Kind) LinksFor(cell *Cell) []int {
+	var links []int
+	size := kind.Size()
+	for _, link := range kind.Links {
+		col, row := cell.Col+link.Col, cell.Row+link.Row
+		if col < 0 || col >= size || row < 0 || row >= size || (col == cell.Col && row == cell.Row) {
+			continue
+		}
+		id := row*size + col
+		if sliceIndex(links, func(other int) bool { return other == id }) == -1 {
+			links = append(links, id)
+		}
+	}
+	return links
+}
+
+// The two diagonals of a puzzle with the given size, from the top left and the top right.
+func DiagonalHouses(size int) [][]Position {
+	down := make([]Position, size)
+	up := make([]Position, size)
+	for i := 0; i < size; i++ {
+		down[i] = Position{Col: i, Row: i}
+		up[i] = Position{Col: size - i - 1, Row: i}
+	}
+	return [][]Position{down, up}
+}
+
+// The windows of a puzzle with the given box size, which are boxes one cell in from the top left corner with a
+// cell between each window and the edges of the puzzle.
+func WindowHouses(boxWidth int, boxHeight int) [][]Position {
+	size := boxWidth * boxHeight
+	houses := [][]Position{}
+	for top := 1; top+boxHeight < size; top += boxHeight + 1 {
+		for left := 1; left+boxWidth < size; left += boxWidth + 1 {
+			house := make([]Position, 0, size)
+			for row := top; row < top+boxHeight; row++ {
+				for col := left; col < left+boxWidth; col++ {
+					house = append(house, Position{Col: col, Row: row})
+				}
+			}
+			houses = append(houses, house)
+		}
+	}
+	return houses
+}
+
+// The center cell of every box of a puzzle with the given box size. There are no center cells and so no
+// houses when the width or height of the box is even.
+func CenterDotHouses(boxWidth int, boxHeight int) [][]Position {
+	if boxWidth%2 == 0 || boxHeight%2 == 0 {
+		return nil
+	}
+	size := boxWidth * boxHeight
+	house := make([]Position, 0, size)
+	for top := 0; top < size; top += boxHeight {
+		for left := 0; left < size; left += boxWidth {
+			house = append(house, Position{Col: left + boxWidth/2, Row: top + boxHeight/2})
+		}
+	}
+	return [][]Position{house}
+}
+
+// The cells in the same position of every box of a puzzle with the given box size, which is a house for each
+// cell of a box (also known as Disjoint Groups).
+func DisjointHouses(boxWidth int, boxHeight int) [][]Position {
+	size := boxWidth * boxHeight
+	houses := make([][]Position, 0, size)
+	for y := 0; y < boxHeight; y++ {
+		for x := 0; x < boxWidth; x++ {
+			house := make([]Position, 0, size)
+			for top := 0; top < size; top += boxHeight {
+				for left := 0; left < size; left += boxWidth {
+					house = append(house, Position{Col: left + x, Row: top + y})
+				}
+			}
+			houses = append(houses, house)
+		}
+	}
+	return houses
+}
+
 func (kind *Kind) ConstraintsFor(cell *Cell) []Constraint {
 	constraints := make([]Constraint, 0, len(kind.Constraints))
 	for _, c := range kind.Constraints {
@@ -0,0 +1,353 @@
+package sudogo
+
+import (
+	"fmt"
+	"testing"
+)
+
+var jigsawRegions = [][]int{
+	{0, 0, 0, 1, 1, 1, 2, 2, 2},
+	{0, 0, 3, 1, 1, 1, 2, 5, 2},
+	{0, 0, 3, 1, 1, 1, 2, 5, 2},
+	{0, 0, 3, 4, 4, 4, 5, 5, 2},
+	{3, 3, 3, 4, 4, 4, 5, 5, 2},
+	{3, 3, 3, 4, 4, 4, 5, 5, 5},
+	{7, 7, 7, 7, 7, 7, 8, 8, 8},
+	{6, 7, 6, 6, 6, 7, 8, 8, 8},
+	{6, 6, 6, 6, 6, 7, 8, 8, 8},
+}
+
+func TestNewJigsawKind(t *testing.T) {
+	tests := []struct {
+		name    string
+		regions [][]int
+		valid   bool
+		size    Size
+	}{
+		{
+			name:    "jigsaw",
+			regions: jigsawRegions,
+			valid:   true,
+			size:    Size{3, 3},
+		},
+		{
+			name: "rectangle",
+			regions: [][]int{
+				{0, 0, 0, 1, 1, 1},
+				{0, 0, 0, 1, 1, 1},
+				{2, 2, 2, 3, 3, 3},
+				{2, 2, 2, 3, 3, 3},
+				{4, 4, 4, 5, 5, 5},
+				{4, 4, 4, 5, 5, 5},
+			},
+			valid: true,
+			size:  Size{3, 2},
+		},
+		{
+			name:    "empty",
+			regions: [][]int{},
+		},
+		{
+			name: "not square",
+			regions: [][]int{
+				{0, 0, 1, 1},
+				{0, 0, 1, 1},
+				{2, 2, 3, 3},
+			},
+		},
+		{
+			name: "box too large",
+			regions: [][]int{
+				{0, 0, 0, 1},
+				{0, 0, 1, 1},
+				{2, 2, 3, 3},
+				{2, 2, 3, 3},
+			},
+		},
+		{
+			name: "box out of range",
+			regions: [][]int{
+				{0, 0, 1, 1},
+				{0, 0, 1, 1},
+				{2, 2, 3, 3},
+				{2, 2, 3, 4},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		kind := NewJigsawKind(test.regions)
+		if (kind != nil) != test.valid {
+			t.Errorf("%s: expected valid %v, got %v", test.name, test.valid, kind != nil)
+		} else if kind != nil && kind.BoxSize != test.size {
+			t.Errorf("%s: expected box size %v, got %v", test.name, test.size, kind.BoxSize)
+		}
+	}
+}
+
+func TestJigsawBoxes(t *testing.T) {
+	kind := NewJigsawKind(jigsawRegions)
+	puzzle := kind.Empty()
+
+	for _, cell := range puzzle.Cells {
+		if cell.Box != jigsawRegions[cell.Row][cell.Col] {
+			t.Errorf("Cell r%dc%d expected box %d, got %d", cell.Row+1, cell.Col+1, jigsawRegions[cell.Row][cell.Col], cell.Box)
+		}
+	}
+
+	if overlap := kind.BoxOverlap(GroupRow); overlap != 6 {
+		t.Errorf("Expected a box to overlap a row by at most 6, got %d", overlap)
+	}
+	if overlap := kind.BoxOverlap(GroupCol); overlap != 5 {
+		t.Errorf("Expected a box to overlap a column by at most 5, got %d", overlap)
+	}
+	if overlap := Classic.BoxOverlap(GroupRow); overlap != 3 {
+		t.Errorf("Expected a classic box to overlap a row by 3, got %d", overlap)
+	}
+}
+
+func TestJigsawSolve(t *testing.T) {
+	kind := NewJigsawKind(jigsawRegions)
+	puzzle := kind.Create([][]int{
+		{0, 0, 0, 6, 0, 0, 5, 0, 0},
+		{0, 0, 3, 1, 0, 0, 0, 6, 0},
+		{6, 0, 1, 5, 7, 0, 0, 2, 0},
+		{0, 0, 8, 0, 5, 6, 9, 0, 3},
+		{4, 0, 7, 0, 1, 9, 0, 0, 0},
+		{0, 9, 0, 0, 0, 0, 0, 0, 8},
+		{0, 6, 0, 3, 4, 0, 0, 0, 0},
+		{8, 0, 5, 9, 6, 0, 0, 3, 0},
+		{0, 0, 0, 7, 0, 0, 0, 9, 0},
+	})
+	expected := "349682517753194862681573429218456973427819356596237148962345781875961234134728695"
+
+	if !puzzle.HasUniqueSolution() {
+		t.Fatalf("Expected the jigsaw to have a unique solution")
+	}
+
+	solver := puzzle.Solver()
+	solution, solved := solver.Solve(SolveLimit{})
+	if !solved || !solution.IsValid() {
+		t.Fatalf("Failed to solve the jigsaw:\n%s", solution.ToConsoleCandidatesString())
+	}
+	if solution.String() != expected {
+		t.Errorf("Expected solution %s, got %s", expected, solution.String())
+	}
+
+	println(puzzle.ToConsoleString())
+	println(puzzle.ToConsoleCandidatesString())
+}
+
+func TestJigsawEncodedString(t *testing.T) {
+	kind := NewJigsawKind(jigsawRegions)
+	puzzle := kind.Create([][]int{
+		{0, 0, 0, 6, 0, 0, 5, 0, 0},
+		{0, 0, 3, 1, 0, 0, 0, 6, 0},
+		{6, 0, 1, 5, 7, 0, 0, 2, 0},
+		{0, 0, 8, 0, 5, 6, 9, 0, 3},
+		{4, 0, 7, 0, 1, 9, 0, 0, 0},
+		{0, 9, 0, 0, 0, 0, 0, 0, 8},
+		{0, 6, 0, 3, 4, 0, 0, 0, 0},
+		{8, 0, 5, 9, 6, 0, 0, 3, 0},
+		{0, 0, 0, 7, 0, 0, 0, 9, 0},
+	})
+
+	decoded := FromEncoded(puzzle.EncodedString())
+	if decoded == nil {
+		t.Fatalf("Jigsaw failed to parse from its encoded string.")
+	}
+	if fmt.Sprint(decoded.Kind.Regions) != fmt.Sprint(jigsawRegions) {
+		t.Errorf("Expected regions %v, got %v", jigsawRegions, decoded.Kind.Regions)
+	}
+	if decoded.Kind.BoxSize != kind.BoxSize {
+		t.Errorf("Expected box size %v, got %v", kind.BoxSize, decoded.Kind.BoxSize)
+	}
+	comparePuzzles(t, decoded, &puzzle)
+}
+
+func TestJigsawEmptyRectangle(t *testing.T) {
+	// The corner of an empty rectangle can be outside an irregular box.
+	kind := NewJigsawKind([][]int{
+		{0, 0, 0, 1, 1, 1, 2, 2, 2},
+		{0, 0, 0, 1, 1, 4, 2, 2, 2},
+		{0, 0, 0, 1, 1, 4, 2, 2, 2},
+		{3, 3, 3, 1, 1, 4, 5, 8, 8},
+		{3, 3, 3, 4, 4, 4, 5, 5, 8},
+		{3, 3, 3, 4, 4, 4, 5, 5, 8},
+		{6, 6, 6, 7, 5, 5, 5, 5, 8},
+		{6, 6, 6, 7, 7, 7, 7, 7, 8},
+		{6, 6, 6, 7, 7, 7, 8, 8, 8},
+	})
+	puzzle := kind.Create([][]int{
+		{5, 0, 0, 9, 0, 0, 0, 0, 0},
+		{0, 2, 0, 0, 1, 7, 0, 5, 8},
+		{7, 0, 0, 8, 0, 0, 0, 0, 0},
+		{0, 1, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 5, 0, 6, 0, 0, 4, 0},
+		{9, 0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 5, 0, 0, 0, 0, 0, 0, 3},
+		{0, 9, 6, 0, 5, 4, 0, 0, 0},
+		{0, 0, 0, 0, 0, 3, 0, 0, 0},
+	})
+	expected := "538926174629417358741835926413572689875369241962148537254681793396754812187293465"
+
+	solver := puzzle.Solver()
+	solver.Steps = append(sliceClone(StandardSolveSteps), OptionalSolveSteps...)
+	solver.AssumeUnique = true
+	solver.LogEnabled = true
+	solution, _ := solver.Solve(SolveLimit{})
+
+	for i, cell := range solution.Cells {
+		value := int(expected[i] - '0')
+		if (cell.HasValue() && cell.Value != value) || (cell.Empty() && !cell.HasCandidate(value)) {
+			t.Fatalf("Removed the solution %d from r%dc%d", value, cell.Row+1, cell.Col+1)
+		}
+	}
+}
+
+func TestJigsawGenerate(t *testing.T) {
+	kind := NewJigsawKind(jigsawRegions)
+	gen := kind.Generator()
+	solution, _ := gen.Attempts(64)
+	if solution == nil {
+		t.Skip("Failed to generate a jigsaw solution")
+	}
+	if !solution.IsSolved() {
+		t.Fatalf("Generated jigsaw is not solved:\n%s", solution.ToConsoleString())
+	}
+	for box := 0; box < kind.Size(); box++ {
+		digits := Bitset{}
+		for _, cell := range solution.Cells {
+			if cell.Box == box {
+				digits.Set(cell.Value, true)
+			}
+		}
+		if digits.Count != kind.Size() {
+			t.Errorf("Generated jigsaw box %d has repeated digits:\n%s", box+1, solution.ToConsoleString())
+		}
+	}
+}
+
+func TestExtraHouses(t *testing.T) {
+	tests := []struct {
+		name     string
+		houses   [][]Position
+		expected [][]Position
+	}{
+		{
+			name:   "diagonals",
+			houses: DiagonalHouses(4),
+			expected: [][]Position{
+				{{0, 0}, {1, 1}, {2, 2}, {3, 3}},
+				{{3, 0}, {2, 1}, {1, 2}, {0, 3}},
+			},
+		},
+		{
+			name:   "windows",
+			houses: WindowHouses(3, 3),
+			expected: [][]Position{
+				{{1, 1}, {2, 1}, {3, 1}, {1, 2}, {2, 2}, {3, 2}, {1, 3}, {2, 3}, {3, 3}},
+				{{5, 1}, {6, 1}, {7, 1}, {5, 2}, {6, 2}, {7, 2}, {5, 3}, {6, 3}, {7, 3}},
+				{{1, 5}, {2, 5}, {3, 5}, {1, 6}, {2, 6}, {3, 6}, {1, 7}, {2, 7}, {3, 7}},
+				{{5, 5}, {6, 5}, {7, 5}, {5, 6}, {6, 6}, {7, 6}, {5, 7}, {6, 7}, {7, 7}},
+			},
+		},
+		{
+			name:   "center dot",
+			houses: CenterDotHouses(3, 3),
+			expected: [][]Position{
+				{{1, 1}, {4, 1}, {7, 1}, {1, 4}, {4, 4}, {7, 4}, {1, 7}, {4, 7}, {7, 7}},
+			},
+		},
+		{
+			name:     "center dot of even boxes",
+			houses:   CenterDotHouses(2, 2),
+			expected: nil,
+		},
+	}
+
+	for _, test := range tests {
+		if len(test.houses) != len(test.expected) {
+			t.Errorf("%s: expected %d houses, got %d", test.name, len(test.expected), len(test.houses))
+			continue
+		}
+		for i, house := range test.houses {
+			if len(house) != len(test.expected[i]) {
+				t.Errorf("%s: expected house %d to be %v, got %v", test.name, i, test.expected[i], house)
+				continue
+			}
+			for k := range house {
+				if house[k] != test.expected[i][k] {
+					t.Errorf("%s: expected house %d to be %v, got %v", test.name, i, test.expected[i], house)
+					break
+				}
+			}
+		}
+	}
+
+	puzzle := SudokuX.Empty()
+	center := puzzle.Get(4, 4)
+	if len(center.Houses) != 2 {
+		t.Errorf("Expected the center of Sudoku-X to be in both diagonals, got %v", center.Houses)
+	}
+}
+
+func TestExtraHouseHiddenSingle(t *testing.T) {
+	// The only place for 1 on the main diagonal is r9c9, which isn't a single in its row, column, or box.
+	puzzle := SudokuX.Create([][]int{
+		{0, 1, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 1, 0, 0},
+		{0, 0, 0, 0, 1, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 1, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0, 0},
+	})
+
+	hint := puzzle.Hint(SolveLimit{})
+	if hint == nil {
+		t.Fatalf("Expected a hint")
+	}
+	if hint.Technique() != "Hidden Single" || hint.Where() != "h1" {
+		t.Errorf("Expected a hidden single in h1, got %s", hint.Text(HintRegion))
+	}
+	expected := SolverCandidate{Position{8, 8}, 1}
+	if len(hint.Deduction.Placements) != 1 || hint.Deduction.Placements[0] != expected {
+		t.Errorf("Expected 1 placed at r9c9, got %s", hint.Explanation())
+	}
+}
+
+func TestExtraHousesGenerate(t *testing.T) {
+	for _, kind := range []*Kind{SudokuX, Windoku, CenterDot} {
+		gen := NewSeededGenerator(kind, 1)
+		solution, _ := gen.Generate()
+		if solution == nil {
+			t.Fatalf("Failed to generate a puzzle with houses %v", kind.Houses)
+		}
+		if !solution.IsSolved() {
+			t.Fatalf("Generated puzzle is not solved:\n%s", solution.ToConsoleString())
+		}
+		for i, house := range kind.Houses {
+			digits := Bitset{}
+			for _, position := range house {
+				digits.Set(solution.Get(position.Col, position.Row).Value, true)
+			}
+			if digits.Count != kind.Size() {
+				t.Errorf("Generated puzzle has repeated digits in house %d:\n%s", i+1, solution.ToConsoleString())
+			}
+		}
+
+		puzzle, _ := gen.ClearCells(solution, ClearLimit{SolveLimit: SolveLimit{MaxPlacements: 50}})
+		if puzzle.CountSolutions(2) != 1 {
+			t.Errorf("Cleared puzzle doesn't have a unique solution: %s", puzzle.String())
+		}
+
+		solver := puzzle.Solver()
+		solved, _ := solver.Solve(SolveLimit{})
+		if solved.IsSolved() && solved.String() != solution.String() {
+			t.Errorf("Solved %s instead of %s", solved.String(), solution.String())
+		}
+	}
+}
@@ -0,0 +1,590 @@
+package sudogo
+
+// The type of a line constraint, which is how it's drawn.
+type LineType int
+
+const (
+	LineThermo LineType = iota
+	LineSlowThermo
+	LineRenban
+	LineGermanWhispers
+	LineDutchWhispers
+	LineWhispers
+	LinePalindrome
+	LineBetween
+	LineRegionSum
+)
+
+var lineTypeNames = []string{
+	"Thermo",
+	"Slow Thermo",
+	"Renban",
+	"German Whispers",
+	"Dutch Whispers",
+	"Whispers",
+	"Palindrome",
+	"Between Line",
+	"Region Sum Line",
+}
+
+func (t LineType) String() string {
+	return lineTypeNames[t]
+}
+
+// A constraint along an ordered path of cells, which is drawn as a line through the cells.
+type LineConstraint interface {
+	Constraint
+	LineType() LineType
+	LinePath() []Position
+}
+
+// The line constraints of the kind's constraints.
+func (kind *Kind) Lines() []LineConstraint {
+	lines := make([]LineConstraint, 0)
+	for _, constraint := range kind.Constraints {
+		if line, ok := constraint.(LineConstraint); ok {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// The index of the cell on the path or -1.
+func pathIndex(cell *Cell, path []Position) int {
+	for i, pos := range path {
+		if isSame(cell, pos) {
+			return i
+		}
+	}
+	return -1
+}
+
+// The cells of the path in the puzzle.
+func pathCells(puzzle *Puzzle, path []Position) []*Cell {
+	cells := make([]*Cell, len(path))
+	for i, pos := range path {
+		cells[i] = getAbsoluteCell(puzzle, pos)
+	}
+	return cells
+}
+
+// ==================================================
+// Constraint: Thermometer
+// ==================================================
+
+// The digits increase along the path from the bulb at its first cell. A slow thermometer's digits can stay
+// the same between cells which don't see each other.
+type ConstraintThermo struct {
+	Path []Position
+	Slow bool
+}
+
+func (c *ConstraintThermo) LineType() LineType {
+	if c.Slow {
+		return LineSlowThermo
+	}
+	return LineThermo
+}
+
+func (c *ConstraintThermo) Technique() *SolveStep {
+	return StepThermoBound
+}
+
+func (c *ConstraintThermo) LinePath() []Position {
+	return c.Path
+}
+
+func (c *ConstraintThermo) Affects(cell *Cell) bool {
+	return cellExists(cell, c.Path)
+}
+
+func (c *ConstraintThermo) RemoveCandidates(cell *Cell, puzzle *Puzzle, remove *Candidates) {
+	index := pathIndex(cell, c.Path)
+	if index == -1 {
+		return
+	}
+	cells := pathCells(puzzle, c.Path)
+	digits := puzzle.Kind.Digits()
+
+	// How much a digit needs to increase from the cell before.
+	step := func(i int) int {
+		if !c.Slow || cells[i-1].InGroup(cells[i]) {
+			return 1
+		}
+		return 0
+	}
+
+	// The smallest digit each cell up to the cell can be, and the largest each cell after it can be.
+	min := 1
+	for i := 0; i <= index; i++ {
+		if i > 0 {
+			min += step(i)
+		}
+		values := cellValues(cells[i])
+		if i == index {
+			values = *remove
+		}
+		for min <= digits && !values.Has(min) {
+			min++
+		}
+	}
+	max := digits
+	for i := len(cells) - 1; i >= index; i-- {
+		if i < len(cells)-1 {
+			max -= step(i + 1)
+		}
+		values := cellValues(cells[i])
+		if i == index {
+			values = *remove
+		}
+		for max >= 1 && !values.Has(max) {
+			max--
+		}
+	}
+
+	for candidate := 1; candidate <= digits; candidate++ {
+		if candidate < min || candidate > max {
+			remove.Set(candidate, false)
+		}
+	}
+}
+
+func (c *ConstraintThermo) Validate(puzzle *Puzzle) []Position {
+	return validateCandidates(c, puzzle)
+}
+
+// ==================================================
+// Step: Thermo Bound
+// ==================================================
+var StepThermoBound = CreateStepConstraint("Thermo Bound", 200, 100)
+
+// ==================================================
+// Constraint: Renban
+// ==================================================
+
+// The digits on the path are a set of consecutive digits in any order without repeats.
+type ConstraintRenban struct {
+	Path []Position
+}
+
+func (c *ConstraintRenban) LineType() LineType {
+	return LineRenban
+}
+
+func (c *ConstraintRenban) Technique() *SolveStep {
+	return StepRenbanSet
+}
+
+func (c *ConstraintRenban) LinePath() []Position {
+	return c.Path
+}
+
+func (c *ConstraintRenban) Affects(cell *Cell) bool {
+	return cellExists(cell, c.Path)
+}
+
+func (c *ConstraintRenban) RemoveCandidates(cell *Cell, puzzle *Puzzle, remove *Candidates) {
+	others := make([]*Cell, 0, len(c.Path))
+	for _, other := range pathCells(puzzle, c.Path) {
+		if other.Id != cell.Id {
+			others = append(others, other)
+		}
+	}
+	digits := puzzle.Kind.Digits()
+	size := len(c.Path)
+
+	possible := Candidates{}
+	for start := 1; start+size-1 <= digits; start++ {
+		run := Candidates{}
+		for digit := start; digit < start+size; digit++ {
+			run.Set(digit, true)
+		}
+		for _, candidate := range run.ToSlice() {
+			if !remove.Has(candidate) || possible.Has(candidate) {
+				continue
+			}
+			rest := run
+			rest.Set(candidate, false)
+			if cellsFit(others, rest) {
+				possible.Set(candidate, true)
+			}
+		}
+	}
+
+	remove.And(possible)
+}
+
+func (c *ConstraintRenban) Validate(puzzle *Puzzle) []Position {
+	return validateCandidates(c, puzzle)
+}
+
+// The digits in every set of consecutive digits the cells can still take are removed from the cells off the
+// line which see every cell of the line that could be the digit.
+func (c *ConstraintRenban) Eliminations(puzzle *Puzzle) []SolverCandidate {
+	placed := Candidates{}
+	unsolved := make([]*Cell, 0, len(c.Path))
+	for _, cell := range pathCells(puzzle, c.Path) {
+		if cell.HasValue() {
+			placed.Set(cell.Value, true)
+		} else {
+			unsolved = append(unsolved, cell)
+		}
+	}
+	digits := puzzle.Kind.Digits()
+	size := len(c.Path)
+
+	required := Candidates{}
+	found := false
+	for start := 1; start+size-1 <= digits; start++ {
+		run := Candidates{}
+		for digit := start; digit < start+size; digit++ {
+			run.Set(digit, true)
+		}
+		if placed.Differences(run) {
+			continue
+		}
+		run.Remove(placed)
+		if !cellsFit(unsolved, run) {
+			continue
+		}
+		if found {
+			required.And(run)
+		} else {
+			required = run
+			found = true
+		}
+	}
+
+	return requiredEliminations(puzzle, unsolved, required)
+}
+
+// ==================================================
+// Step: Renban Set
+// ==================================================
+var StepRenbanSet = CreateStepConstraint("Renban Set", 350, 200)
+
+// ==================================================
+// Constraint: Whispers
+// ==================================================
+
+// Neighboring digits on the path differ by at least the difference.
+type ConstraintWhispers struct {
+	Path       []Position
+	Difference int
+}
+
+// German whispers, where neighboring digits differ by at least 5.
+func ConstraintGermanWhispers(path []Position) ConstraintWhispers {
+	return ConstraintWhispers{
+		Path:       path,
+		Difference: 5,
+	}
+}
+
+// Dutch whispers, where neighboring digits differ by at least 4.
+func ConstraintDutchWhispers(path []Position) ConstraintWhispers {
+	return ConstraintWhispers{
+		Path:       path,
+		Difference: 4,
+	}
+}
+
+func (c *ConstraintWhispers) LineType() LineType {
+	switch c.Difference {
+	case 5:
+		return LineGermanWhispers
+	case 4:
+		return LineDutchWhispers
+	}
+	return LineWhispers
+}
+
+func (c *ConstraintWhispers) Technique() *SolveStep {
+	return StepWhispersDifference
+}
+
+func (c *ConstraintWhispers) LinePath() []Position {
+	return c.Path
+}
+
+func (c *ConstraintWhispers) Affects(cell *Cell) bool {
+	return cellExists(cell, c.Path)
+}
+
+func (c *ConstraintWhispers) RemoveCandidates(cell *Cell, puzzle *Puzzle, remove *Candidates) {
+	index := pathIndex(cell, c.Path)
+	if index == -1 {
+		return
+	}
+	neighbors := make([]Candidates, 0, 2)
+	if index > 0 {
+		neighbors = append(neighbors, cellValues(getAbsoluteCell(puzzle, c.Path[index-1])))
+	}
+	if index < len(c.Path)-1 {
+		neighbors = append(neighbors, cellValues(getAbsoluteCell(puzzle, c.Path[index+1])))
+	}
+
+	for _, candidate := range remove.ToSlice() {
+		for _, neighbor := range neighbors {
+			supported := false
+			for _, other := range neighbor.ToSlice() {
+				if AbsInt(candidate-other) >= c.Difference {
+					supported = true
+					break
+				}
+			}
+			if !supported {
+				remove.Set(candidate, false)
+				break
+			}
+		}
+	}
+}
+
+func (c *ConstraintWhispers) Validate(puzzle *Puzzle) []Position {
+	return validateCandidates(c, puzzle)
+}
+
+// ==================================================
+// Step: Whispers Difference
+// ==================================================
+var StepWhispersDifference = CreateStepConstraint("Whispers Difference", 250, 150)
+
+// ==================================================
+// Constraint: Palindrome
+// ==================================================
+
+// The digits on the path read the same from either end.
+type ConstraintPalindrome struct {
+	Path []Position
+}
+
+func (c *ConstraintPalindrome) LineType() LineType {
+	return LinePalindrome
+}
+
+func (c *ConstraintPalindrome) Technique() *SolveStep {
+	return StepPalindromeMirror
+}
+
+func (c *ConstraintPalindrome) LinePath() []Position {
+	return c.Path
+}
+
+func (c *ConstraintPalindrome) Affects(cell *Cell) bool {
+	return cellExists(cell, c.Path)
+}
+
+func (c *ConstraintPalindrome) RemoveCandidates(cell *Cell, puzzle *Puzzle, remove *Candidates) {
+	index := pathIndex(cell, c.Path)
+	if index == -1 {
+		return
+	}
+	mirror := getAbsoluteCell(puzzle, c.Path[len(c.Path)-1-index])
+	if mirror.Id == cell.Id {
+		return
+	}
+	if mirror.InGroup(cell) {
+		remove.Clear()
+		return
+	}
+	remove.And(cellValues(mirror))
+}
+
+func (c *ConstraintPalindrome) Validate(puzzle *Puzzle) []Position {
+	return validateCandidates(c, puzzle)
+}
+
+// ==================================================
+// Step: Palindrome Mirror
+// ==================================================
+var StepPalindromeMirror = CreateStepConstraint("Palindrome Mirror", 200, 100)
+
+// ==================================================
+// Constraint: Between Line
+// ==================================================
+
+// The digits on the path between the circles at its ends are between the digits in the circles.
+type ConstraintBetween struct {
+	Path []Position
+}
+
+func (c *ConstraintBetween) LineType() LineType {
+	return LineBetween
+}
+
+func (c *ConstraintBetween) Technique() *SolveStep {
+	return StepBetweenBound
+}
+
+func (c *ConstraintBetween) LinePath() []Position {
+	return c.Path
+}
+
+func (c *ConstraintBetween) Affects(cell *Cell) bool {
+	return cellExists(cell, c.Path)
+}
+
+func (c *ConstraintBetween) RemoveCandidates(cell *Cell, puzzle *Puzzle, remove *Candidates) {
+	index := pathIndex(cell, c.Path)
+	last := len(c.Path) - 1
+	if index == -1 || last < 2 {
+		return
+	}
+	cells := pathCells(puzzle, c.Path)
+
+	// Whether every cell strictly inside the line can be between the ends.
+	fits := func(low int, high int) bool {
+		for _, other := range cells[1:last] {
+			values := cellValues(other)
+			if other.Id == cell.Id {
+				values = *remove
+			}
+			found := false
+			for digit := low + 1; digit < high && !found; digit++ {
+				found = values.Has(digit)
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	}
+
+	firstValues, lastValues := cellValues(cells[0]), cellValues(cells[last])
+	if index == 0 {
+		firstValues = *remove
+	} else if index == last {
+		lastValues = *remove
+	}
+
+	possible := Candidates{}
+	for _, first := range firstValues.ToSlice() {
+		for _, end := range lastValues.ToSlice() {
+			low, high := Min(first, end), Max(first, end)
+			if high-low < 2 || !fits(low, high) {
+				continue
+			}
+			if index == 0 {
+				possible.Set(first, true)
+			} else if index == last {
+				possible.Set(end, true)
+			} else {
+				for digit := low + 1; digit < high; digit++ {
+					possible.Set(digit, true)
+				}
+			}
+		}
+	}
+
+	remove.And(possible)
+}
+
+func (c *ConstraintBetween) Validate(puzzle *Puzzle) []Position {
+	return validateCandidates(c, puzzle)
+}
+
+// ==================================================
+// Step: Between Line Bound
+// ==================================================
+var StepBetweenBound = CreateStepConstraint("Between Line Bound", 300, 150)
+
+// ==================================================
+// Constraint: Region Sum Line
+// ==================================================
+
+// The digits on the path in each box it passes through have the same sum.
+type ConstraintRegionSum struct {
+	Path []Position
+}
+
+func (c *ConstraintRegionSum) LineType() LineType {
+	return LineRegionSum
+}
+
+func (c *ConstraintRegionSum) Technique() *SolveStep {
+	return StepRegionSum
+}
+
+func (c *ConstraintRegionSum) LinePath() []Position {
+	return c.Path
+}
+
+func (c *ConstraintRegionSum) Affects(cell *Cell) bool {
+	return cellExists(cell, c.Path)
+}
+
+func (c *ConstraintRegionSum) RemoveCandidates(cell *Cell, puzzle *Puzzle, remove *Candidates) {
+	// The cells of the path in each box, in the order the path enters the boxes.
+	boxes := make([][]*Cell, 0)
+	boxIndex := map[int]int{}
+	for _, other := range pathCells(puzzle, c.Path) {
+		i, exists := boxIndex[other.Box]
+		if !exists {
+			i = len(boxes)
+			boxIndex[other.Box] = i
+			boxes = append(boxes, nil)
+		}
+		boxes[i] = append(boxes[i], other)
+	}
+	if len(boxes) < 2 {
+		return
+	}
+
+	setsOf := func(cells []*Cell) []Candidates {
+		sets := make([]Candidates, len(cells))
+		for i, other := range cells {
+			sets[i] = cellValues(other)
+			if other.Id == cell.Id {
+				sets[i] = *remove
+			}
+		}
+		return sets
+	}
+
+	// The sums each box can have, which the box with the cell has to share with every other box.
+	maxSum := puzzle.Kind.Digits() * len(c.Path)
+	sums := make([]bool, maxSum+1)
+	for sum := range sums {
+		sums[sum] = sum > 0
+	}
+	for _, cells := range boxes {
+		sets := setsOf(cells)
+		for sum := range sums {
+			sums[sum] = sums[sum] && sumPossible(cells, sets, sum)
+		}
+	}
+
+	mine := boxes[boxIndex[cell.Box]]
+	sets := setsOf(mine)
+	index := 0
+	for i, other := range mine {
+		if other.Id == cell.Id {
+			index = i
+		}
+	}
+
+	for _, candidate := range remove.ToSlice() {
+		sets[index].Clear()
+		sets[index].Set(candidate, true)
+		supported := false
+		for sum, possible := range sums {
+			if possible && sumPossible(mine, sets, sum) {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			remove.Set(candidate, false)
+		}
+	}
+}
+
+func (c *ConstraintRegionSum) Validate(puzzle *Puzzle) []Position {
+	return validateCandidates(c, puzzle)
+}
+
+// ==================================================
+// Step: Region Sum
+// ==================================================
+var StepRegionSum = CreateStepConstraint("Region Sum", 450, 300)
@@ -0,0 +1,133 @@
+package sudogo
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestConstraintLines(t *testing.T) {
+	germanWhispers := ConstraintGermanWhispers([]Position{{0, 0}, {1, 0}})
+
+	tests := []struct {
+		name          string
+		puzzle        Puzzle
+		constraint    LineConstraint
+		cellsExpected []string
+	}{
+		{
+			name:          "thermo",
+			puzzle:        Classic.Empty(),
+			constraint:    &ConstraintThermo{Path: []Position{{0, 0}, {1, 0}, {2, 0}}},
+			cellsExpected: []string{"[1 2 3 4 5 6 7]", "[2 3 4 5 6 7 8]", "[3 4 5 6 7 8 9]"},
+		},
+		{
+			name: "slow thermo",
+			puzzle: Classic.Create([][]int{
+				{5, 0, 0, 0, 0, 0, 0, 0, 0},
+			}),
+			constraint:    &ConstraintThermo{Path: []Position{{0, 0}, {3, 1}, {6, 2}}, Slow: true},
+			cellsExpected: []string{"[]", "[5 6 7 8 9]", "[5 6 7 8 9]"},
+		},
+		{
+			name: "renban",
+			puzzle: Classic.Create([][]int{
+				{5, 0, 0, 0, 0, 0, 0, 0, 0},
+			}),
+			constraint:    &ConstraintRenban{Path: []Position{{0, 0}, {1, 0}, {2, 0}}},
+			cellsExpected: []string{"[]", "[3 4 6 7]", "[3 4 6 7]"},
+		},
+		{
+			name:          "german whispers",
+			puzzle:        Classic.Empty(),
+			constraint:    &germanWhispers,
+			cellsExpected: []string{"[1 2 3 4 6 7 8 9]", "[1 2 3 4 6 7 8 9]"},
+		},
+		{
+			name: "german whispers given",
+			puzzle: Classic.Create([][]int{
+				{3, 0, 0, 0, 0, 0, 0, 0, 0},
+			}),
+			constraint:    &germanWhispers,
+			cellsExpected: []string{"[]", "[8 9]"},
+		},
+		{
+			name: "palindrome",
+			puzzle: Classic.Create([][]int{
+				{0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 7, 0, 0, 0, 0, 0, 0},
+			}),
+			constraint:    &ConstraintPalindrome{Path: []Position{{2, 2}, {3, 3}}},
+			cellsExpected: []string{"[]", "[7]"},
+		},
+		{
+			name:          "palindrome same row",
+			puzzle:        Classic.Empty(),
+			constraint:    &ConstraintPalindrome{Path: []Position{{0, 0}, {1, 1}, {2, 0}}},
+			cellsExpected: []string{"[]", "[1 2 3 4 5 6 7 8 9]", "[]"},
+		},
+		{
+			name: "between",
+			puzzle: Classic.Create([][]int{
+				{2, 0, 0, 0, 0, 0, 0, 0, 0},
+			}),
+			constraint:    &ConstraintBetween{Path: []Position{{0, 0}, {1, 0}, {2, 0}}},
+			cellsExpected: []string{"[]", "[3 4 5 6 7 8]", "[4 5 6 7 8 9]"},
+		},
+		{
+			name:          "region sum",
+			puzzle:        Classic.Empty(),
+			constraint:    &ConstraintRegionSum{Path: []Position{{2, 0}, {3, 0}, {3, 1}}},
+			cellsExpected: []string{"[3 4 5 6 7 8 9]", "[1 2 3 4 5 6 7 8]", "[1 2 3 4 5 6 7 8]"},
+		},
+	}
+
+	for _, test := range tests {
+		testConstraintCandidates(t, "TestConstraintLines "+test.name, test.puzzle, test.constraint, test.constraint.LinePath(), test.cellsExpected)
+	}
+}
+
+func TestLineTechniques(t *testing.T) {
+	// Every set of 5 consecutive digits has a 5, which the rest of the row can't have.
+	kind := Classic.Clone()
+	kind.Constraints = []Constraint{
+		&ConstraintRenban{Path: []Position{{0, 0}, {1, 0}, {2, 0}, {3, 0}, {4, 0}}},
+		&ConstraintThermo{Path: []Position{{0, 4}, {1, 4}, {2, 4}}},
+	}
+
+	puzzle := kind.Empty()
+	solver := puzzle.Solver()
+	solver.Solve(SolveLimit{MaxBatches: 2})
+
+	if solver.LogTechniques["Renban Set"] != 1 || solver.LogTechniques["Thermo Bound"] != 1 {
+		t.Errorf("Expected the renban and thermo techniques, got %v", solver.LogTechniques)
+	}
+	if solver.LogTechniques["Constraints"] != 0 {
+		t.Errorf("Expected the lines to not be applied by the constraints step")
+	}
+	if solver.Puzzle.Get(6, 0).candidates.Has(5) {
+		t.Errorf("Expected 5 to be removed from the rest of the row")
+	}
+	if fmt.Sprintf("%v", solver.Puzzle.Get(0, 4).Candidates()) != "[1 2 3 4 5 6 7]" {
+		t.Errorf("Expected the thermo bulb to be bound, got %v", solver.Puzzle.Get(0, 4).Candidates())
+	}
+}
+
+func TestLinesPrint(t *testing.T) {
+	kind := Classic.Clone()
+	whispers := ConstraintDutchWhispers([]Position{{0, 4}, {1, 5}, {2, 6}})
+	kind.Constraints = []Constraint{
+		&ConstraintThermo{Path: []Position{{0, 0}, {1, 0}, {2, 1}}},
+		&ConstraintBetween{Path: []Position{{4, 7}, {5, 7}, {6, 7}}},
+		&whispers,
+	}
+
+	lines := kind.Lines()
+	if len(lines) != 3 || lines[2].LineType() != LineDutchWhispers || lines[2].LineType().String() != "Dutch Whispers" {
+		t.Fatalf("Expected the lines of the kind")
+	}
+
+	puzzle := kind.Empty()
+	println(puzzle.ToConsoleString())
+	println(puzzle.ToConsoleCandidatesString())
+}
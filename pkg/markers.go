@@ -0,0 +1,218 @@
+package sudogo
+
+// The type of a marker on the border between two cells, which is the relation between their digits.
+type MarkerType int
+
+const (
+	// The digits are consecutive.
+	MarkerWhiteDot MarkerType = iota
+	// One digit is double the other.
+	MarkerBlackDot
+	// The digits add up to 10.
+	MarkerX
+	// The digits add up to 5.
+	MarkerV
+)
+
+var markerTypeNames = []string{
+	"White Dot",
+	"Black Dot",
+	"X",
+	"V",
+}
+
+func (t MarkerType) String() string {
+	return markerTypeNames[t]
+}
+
+// Returns whether the digits have the relation of the marker type.
+func (t MarkerType) Allows(a int, b int) bool {
+	switch t {
+	case MarkerWhiteDot:
+		return a-b == 1 || b-a == 1
+	case MarkerBlackDot:
+		return a == b*2 || b == a*2
+	case MarkerX:
+		return a+b == 10
+	case MarkerV:
+		return a+b == 5
+	}
+	return false
+}
+
+// A marker on the border between two orthogonally adjacent cells.
+type EdgeMarker struct {
+	First  Position
+	Second Position
+	Type   MarkerType
+}
+
+// Returns whether the marker is between the two positions, in either order.
+func (m EdgeMarker) Between(a Position, b Position) bool {
+	return (m.First == a && m.Second == b) || (m.First == b && m.Second == a)
+}
+
+// A constraint which is drawn as markers on the borders between cells.
+type MarkerConstraint interface {
+	Constraint
+	EdgeMarkers() []EdgeMarker
+}
+
+// The markers of the marker constraints of the kind's constraints.
+func (kind *Kind) Markers() []EdgeMarker {
+	markers := make([]EdgeMarker, 0)
+	for _, constraint := range kind.Constraints {
+		if marked, ok := constraint.(MarkerConstraint); ok {
+			markers = append(markers, marked.EdgeMarkers()...)
+		}
+	}
+	return markers
+}
+
+// Returns whether the cell is on one side of a marker.
+func markersAffect(cell *Cell, markers []EdgeMarker) bool {
+	for _, marker := range markers {
+		if isSame(cell, marker.First) || isSame(cell, marker.Second) {
+			return true
+		}
+	}
+	return false
+}
+
+// Removes the candidates of the cell which no digit of an orthogonally adjacent cell allows. Neighbors with a
+// marker between them must have its relation, and when negative the neighbors without one can't have any
+// relation of the types.
+func removeMarkerCandidates(cell *Cell, puzzle *Puzzle, remove *Candidates, markers []EdgeMarker, negative []MarkerType) {
+	pos := Position{cell.Col, cell.Row}
+
+	for _, offset := range OrthogonalLinks {
+		other := getRelativeCell(puzzle, offset, cell)
+		if other == nil {
+			continue
+		}
+		otherPos := Position{other.Col, other.Row}
+
+		var allows func(a int, b int) bool
+		for _, marker := range markers {
+			if marker.Between(pos, otherPos) {
+				allows = marker.Type.Allows
+				break
+			}
+		}
+		if allows == nil {
+			if len(negative) == 0 {
+				continue
+			}
+			allows = func(a int, b int) bool {
+				for _, t := range negative {
+					if t.Allows(a, b) {
+						return false
+					}
+				}
+				return true
+			}
+		}
+
+		possible := cellValues(other)
+		available := *remove
+		for available.Count > 0 {
+			candidate := available.First()
+			available.Set(candidate, false)
+
+			allowed := false
+			values := possible
+			for values.Count > 0 {
+				value := values.First()
+				values.Set(value, false)
+				if value != candidate && allows(candidate, value) {
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
+				remove.Set(candidate, false)
+			}
+		}
+	}
+}
+
+// ==================================================
+// Constraint: Kropki
+// ==================================================
+
+// White dots between consecutive digits and black dots between digits where one is double the other. When
+// Negative every dot is given, so neighbors without a dot are neither consecutive nor double.
+type ConstraintKropki struct {
+	Dots     []EdgeMarker
+	Negative bool
+}
+
+func (c *ConstraintKropki) EdgeMarkers() []EdgeMarker {
+	return c.Dots
+}
+
+func (c *ConstraintKropki) Technique() *SolveStep {
+	return StepKropkiPair
+}
+
+func (c *ConstraintKropki) Affects(cell *Cell) bool {
+	return c.Negative || markersAffect(cell, c.Dots)
+}
+
+func (c *ConstraintKropki) RemoveCandidates(cell *Cell, puzzle *Puzzle, remove *Candidates) {
+	var negative []MarkerType
+	if c.Negative {
+		negative = []MarkerType{MarkerWhiteDot, MarkerBlackDot}
+	}
+	removeMarkerCandidates(cell, puzzle, remove, c.Dots, negative)
+}
+
+func (c *ConstraintKropki) Validate(puzzle *Puzzle) []Position {
+	return validateCandidates(c, puzzle)
+}
+
+// ==================================================
+// Step: Kropki Pair
+//		https://en.wikipedia.org/wiki/Kropki_Sudoku
+// ==================================================
+var StepKropkiPair = CreateStepConstraint("Kropki Pair", 250, 150)
+
+// ==================================================
+// Constraint: XV
+// ==================================================
+
+// An X between digits which add up to 10 and a V between digits which add up to 5. When Negative every marker
+// is given, so neighbors without one don't add up to 5 or 10.
+type ConstraintXV struct {
+	Markers  []EdgeMarker
+	Negative bool
+}
+
+func (c *ConstraintXV) EdgeMarkers() []EdgeMarker {
+	return c.Markers
+}
+
+func (c *ConstraintXV) Technique() *SolveStep {
+	return StepXVPair
+}
+
+func (c *ConstraintXV) Affects(cell *Cell) bool {
+	return c.Negative || markersAffect(cell, c.Markers)
+}
+
+func (c *ConstraintXV) RemoveCandidates(cell *Cell, puzzle *Puzzle, remove *Candidates) {
+	var negative []MarkerType
+	if c.Negative {
+		negative = []MarkerType{MarkerX, MarkerV}
+	}
+	removeMarkerCandidates(cell, puzzle, remove, c.Markers, negative)
+}
+
+func (c *ConstraintXV) Validate(puzzle *Puzzle) []Position {
+	return validateCandidates(c, puzzle)
+}
+
+// ==================================================
+// Step: XV Pair
+// ==================================================
+var StepXVPair = CreateStepConstraint("XV Pair", 250, 150)
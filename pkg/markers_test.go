@@ -0,0 +1,164 @@
+package sudogo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConstraintMarkers(t *testing.T) {
+	white := EdgeMarker{Position{0, 0}, Position{1, 0}, MarkerWhiteDot}
+	black := EdgeMarker{Position{0, 0}, Position{1, 0}, MarkerBlackDot}
+	x := EdgeMarker{Position{0, 0}, Position{0, 1}, MarkerX}
+	v := EdgeMarker{Position{0, 0}, Position{0, 1}, MarkerV}
+
+	tests := []struct {
+		name          string
+		puzzle        Puzzle
+		constraint    Constraint
+		cells         []Position
+		cellsExpected []string
+	}{
+		{
+			name: "white dot",
+			puzzle: Classic.Create([][]int{
+				{5, 0, 0, 0, 0, 0, 0, 0, 0},
+			}),
+			constraint:    &ConstraintKropki{Dots: []EdgeMarker{white}},
+			cells:         []Position{{1, 0}},
+			cellsExpected: []string{"[4 6]"},
+		},
+		{
+			name: "black dot",
+			puzzle: Classic.Create([][]int{
+				{3, 0, 0, 0, 0, 0, 0, 0, 0},
+			}),
+			constraint:    &ConstraintKropki{Dots: []EdgeMarker{black}},
+			cells:         []Position{{1, 0}},
+			cellsExpected: []string{"[6]"},
+		},
+		{
+			name:          "black dot empty",
+			puzzle:        Classic.Empty(),
+			constraint:    &ConstraintKropki{Dots: []EdgeMarker{black}},
+			cells:         []Position{{0, 0}, {1, 0}},
+			cellsExpected: []string{"[1 2 3 4 6 8]", "[1 2 3 4 6 8]"},
+		},
+		{
+			name: "kropki negative",
+			puzzle: Classic.Create([][]int{
+				{0, 4, 0, 0, 0, 0, 0, 0, 0},
+			}),
+			constraint:    &ConstraintKropki{Negative: true},
+			cells:         []Position{{0, 0}, {1, 1}},
+			cellsExpected: []string{"[1 6 7 9]", "[1 6 7 9]"},
+		},
+		{
+			name:          "x",
+			puzzle:        Classic.Empty(),
+			constraint:    &ConstraintXV{Markers: []EdgeMarker{x}},
+			cells:         []Position{{0, 0}, {0, 1}},
+			cellsExpected: []string{"[1 2 3 4 6 7 8 9]", "[1 2 3 4 6 7 8 9]"},
+		},
+		{
+			name: "v",
+			puzzle: Classic.Create([][]int{
+				{0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{1, 0, 0, 0, 0, 0, 0, 0, 0},
+			}),
+			constraint:    &ConstraintXV{Markers: []EdgeMarker{v}},
+			cells:         []Position{{0, 0}},
+			cellsExpected: []string{"[4]"},
+		},
+		{
+			name: "xv negative",
+			puzzle: Classic.Create([][]int{
+				{0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{6, 0, 0, 0, 0, 0, 0, 0, 0},
+			}),
+			constraint:    &ConstraintXV{Markers: []EdgeMarker{white}, Negative: true},
+			cells:         []Position{{0, 0}, {1, 1}},
+			cellsExpected: []string{"[1 2 3 5 7 8 9]", "[1 2 3 5 7 8 9]"},
+		},
+	}
+
+	for _, test := range tests {
+		testConstraintCandidates(t, test.name, test.puzzle, test.constraint, test.cells, test.cellsExpected)
+	}
+}
+
+func TestMarkersSolve(t *testing.T) {
+	// Every dot of a solution with the negative rule has the solution as its only one.
+	solution := Classic.Create([][]int{
+		{5, 3, 4, 6, 7, 8, 9, 1, 2},
+		{6, 7, 2, 1, 9, 5, 3, 4, 8},
+		{1, 9, 8, 3, 4, 2, 5, 6, 7},
+		{8, 5, 9, 7, 6, 1, 4, 2, 3},
+		{4, 2, 6, 8, 5, 3, 7, 9, 1},
+		{7, 1, 3, 9, 2, 4, 8, 5, 6},
+		{9, 6, 1, 5, 3, 7, 2, 8, 4},
+		{2, 8, 7, 4, 1, 9, 6, 3, 5},
+		{3, 4, 5, 2, 8, 6, 1, 7, 9},
+	})
+	kropki := &ConstraintKropki{Negative: true}
+	xv := &ConstraintXV{Negative: true}
+	for _, cell := range solution.Cells {
+		for _, offset := range []Position{{1, 0}, {0, 1}} {
+			other := getRelativeCell(&solution, offset, &cell)
+			if other == nil {
+				continue
+			}
+			for _, markerType := range []MarkerType{MarkerWhiteDot, MarkerBlackDot, MarkerX, MarkerV} {
+				if markerType.Allows(cell.Value, other.Value) {
+					marker := EdgeMarker{Position{cell.Col, cell.Row}, Position{other.Col, other.Row}, markerType}
+					if markerType == MarkerX || markerType == MarkerV {
+						xv.Markers = append(xv.Markers, marker)
+					} else {
+						kropki.Dots = append(kropki.Dots, marker)
+					}
+					break
+				}
+			}
+		}
+	}
+
+	kind := Classic.Clone()
+	kind.Constraints = []Constraint{kropki, xv}
+
+	puzzle := kind.Create([][]int{})
+	for i, cell := range solution.Cells {
+		if i%3 == 0 {
+			puzzle.Set(cell.Col, cell.Row, cell.Value)
+		}
+	}
+
+	solver := puzzle.Solver()
+	solver.LogEnabled = true
+	solved, _ := solver.Solve(SolveLimit{})
+	if solved.String() != solution.String() {
+		t.Fatalf("Solved %s instead of %s", solved.String(), solution.String())
+	}
+	if solver.LogTechniques["Kropki Pair"] == 0 {
+		t.Errorf("Expected the markers to be used by their technique")
+	}
+}
+
+func TestMarkersPrint(t *testing.T) {
+	kind := Classic.Clone()
+	kind.Constraints = []Constraint{
+		&ConstraintKropki{Dots: []EdgeMarker{{Position{0, 0}, Position{1, 0}, MarkerWhiteDot}, {Position{0, 0}, Position{0, 1}, MarkerBlackDot}}, Negative: true},
+		&ConstraintXV{Markers: []EdgeMarker{{Position{4, 4}, Position{5, 4}, MarkerX}, {Position{4, 4}, Position{4, 5}, MarkerV}}},
+	}
+
+	if len(kind.Markers()) != 4 {
+		t.Fatalf("Expected the markers of the kind")
+	}
+
+	puzzle := kind.Empty()
+	console := puzzle.ToConsoleString()
+	for _, expected := range []string{"┃ ○ │", "┠●┼", "│ X ┃", "┼V┼", "Kropki: all dots are given"} {
+		if !strings.Contains(console, expected) {
+			t.Errorf("Expected %q in the console output:\n%s", expected, console)
+		}
+	}
+	println(puzzle.ToConsoleCandidatesString())
+}
@@ -0,0 +1,739 @@
+package sudogo
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// A grid of a multi-grid puzzle and the column and row of its top left cell in the layout of the puzzle.
+type MultiGrid struct {
+	Kind *Kind
+	Col  int
+	Row  int
+}
+
+// A puzzle made of grids which overlap, where the cells in more than one grid are shared and have the same value
+// in each grid. The grids should have the same number of digits. The constraints of the kinds are followed by the
+// solver but are ignored when counting solutions.
+type MultiKind struct {
+	Grids []MultiGrid
+}
+
+// Five classic grids where the center grid shares a corner box with each of the other grids.
+var Samurai = &MultiKind{
+	Grids: []MultiGrid{
+		{Kind: Classic, Col: 0, Row: 0},
+		{Kind: Classic, Col: 12, Row: 0},
+		{Kind: Classic, Col: 6, Row: 6},
+		{Kind: Classic, Col: 0, Row: 12},
+		{Kind: Classic, Col: 12, Row: 12},
+	},
+}
+
+// Four classic grids in a 12x12 square where each grid shares six boxes with the grids beside it.
+var Butterfly = &MultiKind{
+	Grids: []MultiGrid{
+		{Kind: Classic, Col: 0, Row: 0},
+		{Kind: Classic, Col: 3, Row: 0},
+		{Kind: Classic, Col: 0, Row: 3},
+		{Kind: Classic, Col: 3, Row: 3},
+	},
+}
+
+// Five classic grids where the center grid shares six boxes with each of the other grids, which are the petals
+// above, left, right, and below it.
+var Flower = &MultiKind{
+	Grids: []MultiGrid{
+		{Kind: Classic, Col: 3, Row: 0},
+		{Kind: Classic, Col: 0, Row: 3},
+		{Kind: Classic, Col: 3, Row: 3},
+		{Kind: Classic, Col: 6, Row: 3},
+		{Kind: Classic, Col: 3, Row: 6},
+	},
+}
+
+// A cell of a grid of a multi-grid puzzle.
+type multiCell struct {
+	grid int
+	id   int
+}
+
+// The number of columns in the layout.
+func (kind *MultiKind) Width() int {
+	width := 0
+	for _, grid := range kind.Grids {
+		width = Max(width, grid.Col+grid.Kind.Size())
+	}
+	return width
+}
+
+// The number of rows in the layout.
+func (kind *MultiKind) Height() int {
+	height := 0
+	for _, grid := range kind.Grids {
+		height = Max(height, grid.Row+grid.Kind.Size())
+	}
+	return height
+}
+
+// The largest number of digits of the grids.
+func (kind *MultiKind) Digits() int {
+	digits := 0
+	for _, grid := range kind.Grids {
+		digits = Max(digits, grid.Kind.Digits())
+	}
+	return digits
+}
+
+// Returns whether any grid has a cell at the position in the layout.
+func (kind *MultiKind) Contains(col int, row int) bool {
+	return len(kind.cellsAt(col, row)) > 0
+}
+
+// The positions in the layout which have a cell, by row and then column.
+func (kind *MultiKind) Positions() []Position {
+	width, height := kind.Width(), kind.Height()
+	positions := make([]Position, 0, width*height)
+	for row := 0; row < height; row++ {
+		for col := 0; col < width; col++ {
+			if kind.Contains(col, row) {
+				positions = append(positions, Position{Col: col, Row: row})
+			}
+		}
+	}
+	return positions
+}
+
+// The cells of the grids at the position in the layout.
+func (kind *MultiKind) cellsAt(col int, row int) []multiCell {
+	var cells []multiCell
+	for i, grid := range kind.Grids {
+		size := grid.Kind.Size()
+		gridCol, gridRow := col-grid.Col, row-grid.Row
+		if gridCol >= 0 && gridRow >= 0 && gridCol < size && gridRow < size {
+			cells = append(cells, multiCell{grid: i, id: gridRow*size + gridCol})
+		}
+	}
+	return cells
+}
+
+// The cells of each position in the layout which is in more than one grid.
+func (kind *MultiKind) sharedCells() [][]multiCell {
+	shared := make([][]multiCell, 0)
+	for _, position := range kind.Positions() {
+		cells := kind.cellsAt(position.Col, position.Row)
+		if len(cells) > 1 {
+			shared = append(shared, cells)
+		}
+	}
+	return shared
+}
+
+// The box of the cell at the position in the layout, which is unique across the grids, or -1 if there is
+// no cell. Shared cells are in the box of the first grid they're in.
+func (kind *MultiKind) boxAt(col int, row int) int {
+	offset := 0
+	for _, grid := range kind.Grids {
+		size := grid.Kind.Size()
+		gridCol, gridRow := col-grid.Col, row-grid.Row
+		if gridCol >= 0 && gridRow >= 0 && gridCol < size && gridRow < size {
+			return offset + grid.Kind.BoxAt(gridCol, gridRow)
+		}
+		offset += size
+	}
+	return -1
+}
+
+func (kind *MultiKind) Empty() MultiPuzzle {
+	return NewMulti(kind)
+}
+
+func (kind *MultiKind) Create(values [][]int) MultiPuzzle {
+	instance := NewMulti(kind)
+	instance.SetAll(values)
+	return instance
+}
+
+func (kind *MultiKind) Generator() MultiGenerator {
+	return NewMultiGenerator(kind)
+}
+
+// A puzzle of a multi-grid kind with a puzzle for each grid. The shared cells are in each of their grids' puzzles.
+type MultiPuzzle struct {
+	Kind  *MultiKind
+	Grids []Puzzle
+}
+
+func NewMulti(kind *MultiKind) MultiPuzzle {
+	grids := make([]Puzzle, len(kind.Grids))
+	for i, grid := range kind.Grids {
+		grids[i] = New(grid.Kind)
+	}
+	return MultiPuzzle{kind, grids}
+}
+
+func (puzzle *MultiPuzzle) Clone() MultiPuzzle {
+	grids := make([]Puzzle, len(puzzle.Grids))
+	for i := range puzzle.Grids {
+		grids[i] = puzzle.Grids[i].Clone()
+	}
+	return MultiPuzzle{puzzle.Kind, grids}
+}
+
+func (puzzle *MultiPuzzle) Clear() {
+	for i := range puzzle.Grids {
+		puzzle.Grids[i].Clear()
+	}
+}
+
+func (puzzle *MultiPuzzle) Solver() MultiSolver {
+	return NewMultiSolver(*puzzle)
+}
+
+func (puzzle *MultiPuzzle) Contains(col int, row int) bool {
+	return puzzle.Kind.Contains(col, row)
+}
+
+// The cells at the position in the layout, one for each grid the position is in.
+func (puzzle *MultiPuzzle) Get(col int, row int) []*Cell {
+	cells := puzzle.Kind.cellsAt(col, row)
+	gets := make([]*Cell, len(cells))
+	for i, cell := range cells {
+		gets[i] = &puzzle.Grids[cell.grid].Cells[cell.id]
+	}
+	return gets
+}
+
+// The value at the position in the layout, or 0 if there is none.
+func (puzzle *MultiPuzzle) Value(col int, row int) int {
+	for _, cell := range puzzle.Get(col, row) {
+		if cell.HasValue() {
+			return cell.Value
+		}
+	}
+	return 0
+}
+
+// Sets the value of the cells at the position in the layout if it's a candidate of every cell.
+func (puzzle *MultiPuzzle) Set(col int, row int, value int) bool {
+	cells := puzzle.Kind.cellsAt(col, row)
+	if len(cells) == 0 {
+		return false
+	}
+	for _, cell := range cells {
+		if !puzzle.Grids[cell.grid].Cells[cell.id].HasCandidate(value) {
+			return false
+		}
+	}
+	for _, cell := range cells {
+		grid := &puzzle.Grids[cell.grid]
+		grid.SetCell(&grid.Cells[cell.id], value)
+	}
+	return true
+}
+
+// Removes the value of the cells at the position in the layout.
+func (puzzle *MultiPuzzle) Remove(col int, row int) bool {
+	removed := false
+	for _, cell := range puzzle.Kind.cellsAt(col, row) {
+		grid := &puzzle.Grids[cell.grid]
+		if grid.RemoveCell(&grid.Cells[cell.id]) {
+			removed = true
+		}
+	}
+	return removed
+}
+
+// Sets the values of the puzzle from rows of the layout and returns how many were set.
+func (puzzle *MultiPuzzle) SetAll(values [][]int) int {
+	sets := 0
+
+	puzzle.Clear()
+
+	for y, row := range values {
+		for x, value := range row {
+			if value > 0 && puzzle.Set(x, y, value) {
+				sets++
+			}
+		}
+	}
+
+	return sets
+}
+
+// The values of the puzzle by rows of the layout, where positions without a value or a cell are 0.
+func (puzzle *MultiPuzzle) GetAll() [][]int {
+	width, height := puzzle.Kind.Width(), puzzle.Kind.Height()
+	all := make([][]int, height)
+
+	for y := 0; y < height; y++ {
+		all[y] = make([]int, width)
+		for x := 0; x < width; x++ {
+			all[y][x] = puzzle.Value(x, y)
+		}
+	}
+
+	return all
+}
+
+func (puzzle *MultiPuzzle) IsSolved() bool {
+	for i := range puzzle.Grids {
+		if !puzzle.Grids[i].IsSolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns whether every grid is valid and the shared cells have the same value in each grid.
+func (puzzle *MultiPuzzle) IsValid() bool {
+	for i := range puzzle.Grids {
+		if !puzzle.Grids[i].IsValid() {
+			return false
+		}
+	}
+	for _, cells := range puzzle.Kind.sharedCells() {
+		value := puzzle.Grids[cells[0].grid].Cells[cells[0].id].Value
+		for _, cell := range cells[1:] {
+			if puzzle.Grids[cell.grid].Cells[cell.id].Value != value {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// The rows of the layout on separate lines where empty cells are "." and positions without a cell are " ".
+func (puzzle *MultiPuzzle) String() string {
+	width, height := puzzle.Kind.Width(), puzzle.Kind.Height()
+	sb := strings.Builder{}
+	for row := 0; row < height; row++ {
+		if row > 0 {
+			sb.WriteString("\n")
+		}
+		for col := 0; col < width; col++ {
+			if !puzzle.Contains(col, row) {
+				sb.WriteString(" ")
+			} else if value := puzzle.Value(col, row); value > 0 {
+				sb.WriteString(strconv.Itoa(value))
+			} else {
+				sb.WriteString(".")
+			}
+		}
+	}
+	return sb.String()
+}
+
+// Returns the number of solutions to the puzzle, counting no more than max (when max > 0). The solutions are
+// counted with dancing links, so the constraints of the grids' kinds are ignored.
+func (puzzle *MultiPuzzle) CountSolutions(max int) int {
+	dl, valid := newMultiDancingLinks(puzzle)
+	if !valid {
+		return 0
+	}
+	count := 0
+	dl.search(func(candidates []int) bool {
+		count++
+		return max <= 0 || count < max
+	})
+	return count
+}
+
+func (puzzle *MultiPuzzle) HasUniqueSolution() bool {
+	return puzzle.CountSolutions(2) == 1
+}
+
+// Builds the matrix for the puzzle's values where each position in the layout is a cell which covers the
+// row, column, box, and extra house rules of every grid it's in. Returns false if the values break a rule.
+func newMultiDancingLinks(puzzle *MultiPuzzle) (*dancingLinks, bool) {
+	positions := puzzle.Kind.Positions()
+	values := make([]int, len(positions))
+	cells := make([][]multiCell, len(positions))
+	for i, position := range positions {
+		values[i] = puzzle.Value(position.Col, position.Row)
+		cells[i] = puzzle.Kind.cellsAt(position.Col, position.Row)
+	}
+
+	// The first rule of each grid.
+	offsets := make([]int, len(puzzle.Grids))
+	columns := len(positions)
+	for i := range puzzle.Grids {
+		kind := puzzle.Grids[i].Kind
+		offsets[i] = columns
+		columns += kind.Area()*3 + len(kind.Houses)*kind.Size()
+	}
+
+	return newDancingLinksRules(values, puzzle.Kind.Digits(), columns, 0, func(position int, digit int) []int {
+		d := digit - 1
+		rules := []int{position}
+		for _, multi := range cells[position] {
+			cell := &puzzle.Grids[multi.grid].Cells[multi.id]
+			size := puzzle.Grids[multi.grid].Kind.Size()
+			area := size * size
+			offset := offsets[multi.grid]
+			rules = append(rules, offset+cell.Row*size+d, offset+area+cell.Col*size+d, offset+area*2+cell.Box*size+d)
+			for _, house := range cell.Houses {
+				rules = append(rules, offset+area*3+house*size+d)
+			}
+		}
+		return rules
+	})
+}
+
+// ==================================================
+// Step: Shared Cell
+// ==================================================
+// The value or candidates of a cell shared with another grid of a multi-grid puzzle. It's applied by the
+// multi-grid solver between the steps of the grids' solvers so it has no logic of its own.
+var StepSharedCell = &SolveStep{
+	Technique:      "Shared Cell",
+	FirstCost:      0,
+	SubsequentCost: 0,
+	Logic: func(solver *Solver, limits SolveLimit, step *SolveStep) (int, bool) {
+		return 0, false
+	},
+}
+
+// Solves a multi-grid puzzle with a solver for each grid, which share the values and candidates of the shared
+// cells whenever the solvers can't go further. Limits apply to the whole puzzle, so the costs, placements, and
+// logs of all grids are added up.
+type MultiSolver struct {
+	Kind    *MultiKind
+	Solvers []Solver
+	puzzle  MultiPuzzle
+	shared  [][]multiCell
+}
+
+func NewMultiSolver(starting MultiPuzzle) MultiSolver {
+	solvers := make([]Solver, len(starting.Grids))
+	grids := make([]Puzzle, len(starting.Grids))
+	for i := range starting.Grids {
+		solvers[i] = NewSolver(starting.Grids[i])
+		grids[i] = solvers[i].Puzzle
+	}
+
+	solver := MultiSolver{
+		Kind:    starting.Kind,
+		Solvers: solvers,
+		puzzle:  MultiPuzzle{starting.Kind, grids},
+		shared:  starting.Kind.sharedCells(),
+	}
+	solver.share()
+
+	return solver
+}
+
+// The puzzle being solved, which shares its cells with the solvers.
+func (solver *MultiSolver) Puzzle() *MultiPuzzle {
+	return &solver.puzzle
+}
+
+// Sets the steps of each grid's solver.
+func (solver *MultiSolver) SetSteps(steps []*SolveStep) {
+	for i := range solver.Solvers {
+		solver.Solvers[i].Steps = steps
+	}
+}
+
+// Enables logging in each grid's solver.
+func (solver *MultiSolver) SetLogging(enabled bool, state bool) {
+	for i := range solver.Solvers {
+		solver.Solvers[i].LogEnabled = enabled
+		solver.Solvers[i].LogState = state
+	}
+}
+
+// Sets the value of the cells at the position in the layout if it's a candidate of every cell.
+func (solver *MultiSolver) Set(col int, row int, value int) bool {
+	cells := solver.Kind.cellsAt(col, row)
+	if len(cells) == 0 {
+		return false
+	}
+	for _, cell := range cells {
+		if !solver.Solvers[cell.grid].Puzzle.Cells[cell.id].HasCandidate(value) {
+			return false
+		}
+	}
+	for _, cell := range cells {
+		grid := &solver.Solvers[cell.grid]
+		grid.SetCell(&grid.Puzzle.Cells[cell.id], value)
+	}
+	return true
+}
+
+func (solver *MultiSolver) Solved() bool {
+	for i := range solver.Solvers {
+		if !solver.Solvers[i].Solved() {
+			return false
+		}
+	}
+	return true
+}
+
+// The fewest candidates of an unsolved cell in any grid, which is 0 when a cell has no candidates left.
+func (solver *MultiSolver) GetMinCandidateCount() int {
+	min := -1
+	for i := range solver.Solvers {
+		for _, cell := range solver.Solvers[i].Unsolved {
+			if min == -1 || min > cell.candidates.Count {
+				min = cell.candidates.Count
+			}
+		}
+	}
+	return Max(min, 0)
+}
+
+// The log which adds up the costs, placements, and logs of every grid.
+func (solver *MultiSolver) GetTotalLog() SolverLog {
+	total := SolverLog{}
+	for i := range solver.Solvers {
+		last := solver.Solvers[i].GetLastLog()
+		total.Index += last.Index
+		total.Batch += last.Batch
+		total.RunningCost += last.RunningCost
+		total.RunningPlacements += last.RunningPlacements
+	}
+	return total
+}
+
+// The number of times each technique was used by every grid.
+func (solver *MultiSolver) GetTotalTechniques() map[string]int {
+	techniques := map[string]int{}
+	for i := range solver.Solvers {
+		for technique, count := range solver.Solvers[i].LogTechniques {
+			techniques[technique] += count
+		}
+	}
+	return techniques
+}
+
+func (solver *MultiSolver) CanContinue(limits SolveLimit, cost int) bool {
+	total := solver.GetTotalLog()
+	return canContinue(limits, &total, solver.GetTotalTechniques(), cost)
+}
+
+func (solver *MultiSolver) Solve(limits SolveLimit) (*MultiPuzzle, bool) {
+	for solver.CanContinue(limits, 0) {
+		for i := range solver.Solvers {
+			if !solver.CanContinue(limits, 0) {
+				break
+			}
+			solver.Solvers[i].Solve(solver.gridLimits(limits, i))
+		}
+		if !solver.share() {
+			break
+		}
+	}
+	return &solver.puzzle, solver.Solved()
+}
+
+// The limits of a grid's solver, which are what's left of the limits after the other grids.
+func (solver *MultiSolver) gridLimits(limits SolveLimit, grid int) SolveLimit {
+	others := SolverLog{}
+	for i := range solver.Solvers {
+		if i != grid {
+			last := solver.Solvers[i].GetLastLog()
+			others.Index += last.Index
+			others.Batch += last.Batch
+			others.RunningCost += last.RunningCost
+			others.RunningPlacements += last.RunningPlacements
+		}
+	}
+	remaining := func(limit int, used int) int {
+		if limit <= 0 {
+			return limit
+		}
+		return Max(limit-used, 1)
+	}
+
+	gridLimits := limits
+	gridLimits.MinCost = remaining(limits.MinCost, others.RunningCost)
+	gridLimits.MaxCost = remaining(limits.MaxCost, others.RunningCost)
+	gridLimits.MaxPlacements = remaining(limits.MaxPlacements, others.RunningPlacements)
+	gridLimits.MaxLogs = remaining(limits.MaxLogs, others.Index)
+	gridLimits.MaxBatches = remaining(limits.MaxBatches, others.Batch)
+	return gridLimits
+}
+
+// Shares the values and candidates of the shared cells between their grids until they agree. A shared cell
+// which can't take the value it has in another grid loses all its candidates. Returns whether any cell changed.
+func (solver *MultiSolver) share() bool {
+	changed := false
+	for sharing := true; sharing; {
+		sharing = false
+		for _, cells := range solver.shared {
+			value := 0
+			candidates := Candidates{}
+			candidates.Fill(solver.Kind.Digits())
+			for _, multi := range cells {
+				cell := &solver.Solvers[multi.grid].Puzzle.Cells[multi.id]
+				if cell.HasValue() {
+					value = cell.Value
+				} else {
+					candidates.And(cell.candidates)
+				}
+			}
+
+			for _, multi := range cells {
+				grid := &solver.Solvers[multi.grid]
+				cell := &grid.Puzzle.Cells[multi.id]
+				if cell.HasValue() || (value == 0 && cell.candidates.Equals(candidates)) {
+					continue
+				}
+				if value != 0 && !cell.HasCandidate(value) {
+					if cell.candidates.Count > 0 {
+						cell.candidates.Clear()
+						changed = true
+					}
+					continue
+				}
+
+				grid.LogStep(StepSharedCell)
+				grid.LogPattern([]*Cell{cell})
+				grid.LogBefore(cell)
+				if value != 0 {
+					grid.SetCell(cell, value)
+					grid.LogPlacement(cell)
+				} else {
+					cell.candidates.And(candidates)
+					grid.LogAfter(cell)
+				}
+				sharing = true
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// Generates multi-grid puzzles of the given kind using a particular random number generator.
+type MultiGenerator struct {
+	Kind   *MultiKind
+	solver MultiSolver
+	Random *rand.Rand
+}
+
+func NewMultiGenerator(kind *MultiKind) MultiGenerator {
+	return NewRandomMultiGenerator(kind, Random())
+}
+
+func NewSeededMultiGenerator(kind *MultiKind, seed int64) MultiGenerator {
+	return NewRandomMultiGenerator(kind, RandomSeeded(seed))
+}
+
+func multiGeneratorSolver(kind *MultiKind) MultiSolver {
+	solver := NewMultiSolver(kind.Empty())
+	solver.SetSteps(GenerateSolveSteps)
+	return solver
+}
+
+func NewRandomMultiGenerator(kind *MultiKind, random *rand.Rand) MultiGenerator {
+	return MultiGenerator{kind, multiGeneratorSolver(kind), random}
+}
+
+func (gen *MultiGenerator) Reset() {
+	gen.solver = multiGeneratorSolver(gen.Kind)
+}
+
+func (gen *MultiGenerator) Puzzle() *MultiPuzzle {
+	return gen.solver.Puzzle()
+}
+
+func (gen *MultiGenerator) Solver() *MultiSolver {
+	return &gen.solver
+}
+
+func (gen *MultiGenerator) IsComplete() bool {
+	return gen.solver.Solved()
+}
+
+// The position in the layout of a random unsolved cell.
+func (gen *MultiGenerator) GetRandomUnsolved() (Position, *Cell) {
+	unsolved := make([]int, 0, len(gen.solver.Solvers))
+	for i := range gen.solver.Solvers {
+		if !gen.solver.Solvers[i].Solved() {
+			unsolved = append(unsolved, i)
+		}
+	}
+	grid := randomElement(gen.Random, unsolved, 0)
+	cell := randomPointer(gen.Random, gen.solver.Solvers[grid].Unsolved)
+	layout := gen.Kind.Grids[grid]
+	return Position{Col: layout.Col + cell.Col, Row: layout.Row + cell.Row}, cell
+}
+
+func (gen *MultiGenerator) Attempt() *MultiPuzzle {
+	for !gen.IsComplete() {
+		gen.solver.Solve(SolveLimit{})
+
+		if gen.IsComplete() {
+			break
+		}
+
+		if gen.solver.GetMinCandidateCount() == 0 {
+			return nil
+		}
+
+		position, randomCell := gen.GetRandomUnsolved()
+		randomValue := randomElement(gen.Random, randomCell.Candidates(), 0)
+
+		gen.solver.Set(position.Col, position.Row, randomValue)
+	}
+	return gen.Puzzle()
+}
+
+func (gen *MultiGenerator) Attempts(tries int) (*MultiPuzzle, int) {
+	for i := 0; i < tries; i++ {
+		generated := gen.Attempt()
+		if generated != nil {
+			return generated, i + 1
+		} else {
+			gen.Reset()
+		}
+	}
+	return nil, tries
+}
+
+func (gen *MultiGenerator) Generate() (*MultiPuzzle, int) {
+	return gen.Attempts(1 << 14)
+}
+
+// Clears cells from the solved puzzle like Generator.ClearCells, keeping a unique solution. The limits apply
+// to the whole puzzle and symmetry is across the center of the layout.
+func (gen *MultiGenerator) ClearCells(puzzle *MultiPuzzle, limits ClearLimit) (*MultiPuzzle, int) {
+	if puzzle == nil {
+		return nil, 0
+	}
+	return clearCells[*MultiPuzzle](gen.Random, puzzle, limits)
+}
+
+func (puzzle *MultiPuzzle) clone() *MultiPuzzle {
+	clone := puzzle.Clone()
+	return &clone
+}
+
+func (puzzle *MultiPuzzle) valuePositions() []Position {
+	return sliceWhere(puzzle.Kind.Positions(), func(position Position) bool {
+		return puzzle.Value(position.Col, position.Row) != 0
+	})
+}
+
+func (puzzle *MultiPuzzle) symmetric(position Position) (Position, bool) {
+	symmetric := Position{Col: puzzle.Kind.Width() - 1 - position.Col, Row: puzzle.Kind.Height() - 1 - position.Row}
+	return symmetric, symmetric != position && puzzle.Value(symmetric.Col, symmetric.Row) != 0
+}
+
+func (puzzle *MultiPuzzle) rate(limits ClearLimit) clearSolver {
+	if limits.Fast {
+		solver := puzzle.Solver()
+		if _, solved := solver.Solve(limits.SolveLimit); solved {
+			return &solver
+		}
+	} else if puzzle.CountSolutions(2) == 1 {
+		// The solver only rates the puzzle, it doesn't need to solve it.
+		solver := puzzle.Solver()
+		solver.Solve(limits.SolveLimit)
+		return &solver
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package sudogo
+
+import (
+	"testing"
+)
+
+func TestMultiLayouts(t *testing.T) {
+	tests := []struct {
+		name   string
+		kind   *MultiKind
+		width  int
+		height int
+		cells  int
+		shared int
+	}{
+		{name: "samurai", kind: Samurai, width: 21, height: 21, cells: 369, shared: 36},
+		{name: "butterfly", kind: Butterfly, width: 12, height: 12, cells: 144, shared: 108},
+		{name: "flower", kind: Flower, width: 15, height: 15, cells: 189, shared: 81},
+	}
+
+	for _, test := range tests {
+		if test.kind.Width() != test.width || test.kind.Height() != test.height {
+			t.Errorf("%s: expected %dx%d, got %dx%d", test.name, test.width, test.height, test.kind.Width(), test.kind.Height())
+		}
+		if cells := len(test.kind.Positions()); cells != test.cells {
+			t.Errorf("%s: expected %d cells, got %d", test.name, test.cells, cells)
+		}
+		if shared := len(test.kind.sharedCells()); shared != test.shared {
+			t.Errorf("%s: expected %d shared cells, got %d", test.name, test.shared, shared)
+		}
+	}
+
+	if Samurai.Contains(10, 1) || !Samurai.Contains(10, 7) {
+		t.Errorf("Samurai should only contain the center grid between the top grids")
+	}
+}
+
+func TestMultiSet(t *testing.T) {
+	puzzle := Samurai.Empty()
+
+	// The bottom right box of the top left grid is the top left box of the center grid.
+	if !puzzle.Set(7, 7, 5) {
+		t.Fatalf("Failed to set a shared cell")
+	}
+	cells := puzzle.Get(7, 7)
+	if len(cells) != 2 || cells[0].Value != 5 || cells[1].Value != 5 {
+		t.Errorf("Expected the value in both grids")
+	}
+	if puzzle.Grids[2].Get(10, 1).HasCandidate(5) {
+		t.Errorf("Expected the value to be removed from the center grid's row")
+	}
+	if puzzle.Set(7, 13, 5) {
+		t.Errorf("Expected 5 not to be a candidate in the column of the center grid")
+	}
+	if puzzle.Set(10, 1, 1) {
+		t.Errorf("Expected no cell between the top grids")
+	}
+
+	puzzle.Remove(7, 7)
+	if puzzle.Value(7, 7) != 0 || !puzzle.Grids[2].Get(10, 1).HasCandidate(5) {
+		t.Errorf("Expected the value to be removed from both grids")
+	}
+}
+
+func TestMultiGenerate(t *testing.T) {
+	for _, kind := range []*MultiKind{Samurai, Butterfly, Flower} {
+		gen := NewSeededMultiGenerator(kind, 7)
+		solution, _ := gen.Generate()
+		if solution == nil {
+			t.Fatalf("Failed to generate a multi-grid puzzle")
+		}
+		if !solution.IsSolved() || !solution.IsValid() {
+			t.Fatalf("Generated an invalid multi-grid puzzle:\n%s", solution.String())
+		}
+
+		puzzle, _ := gen.ClearCells(solution, ClearLimit{SolveLimit: SolveLimit{MaxPlacements: 150}, Symmetric: true})
+		if puzzle == nil {
+			t.Fatalf("Failed to clear cells of a multi-grid puzzle")
+		}
+		if !puzzle.HasUniqueSolution() {
+			t.Errorf("Expected a unique solution:\n%s", puzzle.String())
+		}
+
+		solver := puzzle.Solver()
+		solved, isSolved := solver.Solve(SolveLimit{})
+		if !isSolved || solved.String() != solution.String() {
+			t.Errorf("Expected the solver to find the generated solution:\n%s", solved.String())
+		}
+
+		puzzle.PrintConsole()
+	}
+}
+
+func TestMultiCountSolutions(t *testing.T) {
+	puzzle := Butterfly.Empty()
+	puzzle.Set(0, 0, 1)
+	puzzle.Set(11, 11, 1)
+
+	if puzzle.CountSolutions(2) != 2 {
+		t.Errorf("Expected an almost empty puzzle to have many solutions")
+	}
+
+	// Values set in one grid still cover the rules of the other grids they're in, so these 1s conflict in
+	// the top row of the top left grid.
+	conflicting := Butterfly.Empty()
+	conflicting.Grids[0].Set(3, 0, 1)
+	conflicting.Grids[1].Set(1, 0, 1)
+	if conflicting.CountSolutions(0) != 0 {
+		t.Errorf("Expected conflicting shared cells to have no solutions")
+	}
+}
+
+func TestMultiPrintCandidates(t *testing.T) {
+	puzzle := Flower.Empty()
+	puzzle.Set(7, 7, 1)
+
+	println(puzzle.ToConsoleCandidatesString())
+}
@@ -34,10 +34,20 @@ type PuzzlePDF struct {
 	BorderThinWidth  float64
 	StateColor       Color
 	SolutionColor    Color
+	CageColor        Color
+	CageWidth        float64
+	CageSumScale     float64
+	LineColors       map[LineType]Color
+	LineWidthScale   float64
+	ParityColor      Color
+	MarkerColor      Color
+	MarkerScale      float64
 }
 
 type PuzzlePDFItem struct {
-	Puzzle         *Puzzle
+	Puzzle *Puzzle
+	// A multi-grid puzzle drawn instead of Puzzle, which doesn't have state or solution strings.
+	Multi          *MultiPuzzle
 	Candidates     bool
 	StateString    bool
 	SolutionString bool
@@ -84,6 +94,24 @@ func NewPDF() PuzzlePDF {
 		BorderThinWidth:  0.5,
 		StateColor:       ColorBlack,
 		SolutionColor:    ColorGray,
+		CageColor:        ColorBlack,
+		CageWidth:        0.75,
+		CageSumScale:     0.22,
+		LineColors: map[LineType]Color{
+			LineThermo:         {200, 200, 200},
+			LineSlowThermo:     {200, 200, 200},
+			LineRenban:         {230, 170, 230},
+			LineGermanWhispers: {120, 220, 120},
+			LineDutchWhispers:  {255, 180, 100},
+			LineWhispers:       {120, 220, 120},
+			LinePalindrome:     {180, 180, 180},
+			LineBetween:        {150, 200, 255},
+			LineRegionSum:      {100, 160, 255},
+		},
+		LineWidthScale: 0.15,
+		ParityColor:    Color{220, 220, 220},
+		MarkerColor:    ColorBlack,
+		MarkerScale:    0.24,
 	}
 }
 
@@ -96,6 +124,14 @@ func (pdf *PuzzlePDF) Add(puzzle *Puzzle, candidates bool, stateString bool, sol
 	})
 }
 
+// Adds a multi-grid puzzle, which is drawn with its combined layout scaled to fit in one puzzle's space.
+func (pdf *PuzzlePDF) AddMulti(puzzle *MultiPuzzle, candidates bool) {
+	pdf.Puzzles = append(pdf.Puzzles, PuzzlePDFItem{
+		Multi:      puzzle,
+		Candidates: candidates,
+	})
+}
+
 func (pdf *PuzzlePDF) orientation() string {
 	if pdf.Landscape {
 		return "L"
@@ -156,25 +192,33 @@ func (pdf *PuzzlePDF) Generate() *gofpdf.Fpdf {
 	perPage := pdf.PuzzlesWide * pdf.PuzzlesHigh
 
 	for i, item := range pdf.Puzzles {
-		puzzle := item.Puzzle
 		pageIndex := i % perPage
+		pageCol := pageIndex % pdf.PuzzlesWide
+		pageRow := pageIndex / pdf.PuzzlesWide
+		originX := offsetX + float64(pageCol)*puzzleSeparation
+		originY := offsetY + float64(pageRow)*puzzleSeparation
+
+		if pageIndex == 0 {
+			p.AddPage()
+		}
+
+		if item.Multi != nil {
+			pdf.generateMulti(p, item, originX, originY, puzzleSize)
+			continue
+		}
+
+		puzzle := item.Puzzle
 		size := puzzle.Kind.Size()
 		sizef := float64(size)
 		cellSize := puzzleSize / sizef
 		fontSize := cellSize * pdf.ValueFontScale
 		lineMargin := pdf.BorderThickWidth * 2
-		candidateSpace := cellSize - lineMargin
 		candidateSize := cellSize * pdf.CandidateScale
-		pageCol := pageIndex % pdf.PuzzlesWide
-		pageRow := pageIndex / pdf.PuzzlesWide
-		originX := offsetX + float64(pageCol)*puzzleSeparation
-		originY := offsetY + float64(pageRow)*puzzleSeparation
 		boxW := float64(puzzle.Kind.BoxSize.Width) * cellSize
 		boxH := float64(puzzle.Kind.BoxSize.Height) * cellSize
 
-		if pageIndex == 0 {
-			p.AddPage()
-		}
+		pdf.generateParities(p, puzzle, originX, originY, cellSize)
+		pdf.generateLines(p, puzzle, originX, originY, cellSize)
 
 		p.SetLineWidth(pdf.BorderThinWidth)
 		p.SetDrawColor(pdf.BorderThinColor.R, pdf.BorderThinColor.G, pdf.BorderThinColor.B)
@@ -182,49 +226,33 @@ func (pdf *PuzzlePDF) Generate() *gofpdf.Fpdf {
 
 		for y := 0; y < size; y++ {
 			for x := 0; x < size; x++ {
-				cell := puzzle.Get(x, y)
-
-				p.SetXY(originX+float64(x)*cellSize, originY+float64(y)*cellSize)
-				if cell.HasValue() {
-					cellValue := strconv.Itoa(cell.Value)
-					fill := !pdf.ValueBackColor.Is(ColorWhite)
-
-					p.SetFont(pdf.Font, "B", fontSize)
-					p.SetTextColor(pdf.ValueFontColor.R, pdf.ValueFontColor.G, pdf.ValueFontColor.B)
-					p.CellFormat(cellSize, cellSize, cellValue, "1", 0, "CM", fill, 0, "")
-				} else {
-					p.SetFont(pdf.Font, "", fontSize)
-					p.CellFormat(cellSize, cellSize, "", "1", 0, "CM", false, 0, "")
-
-					if item.Candidates {
-						cand := fmt.Sprintf("%v", cell.Candidates())
-						cand = strings.Trim(cand, "[]")
-
-						p.SetFont(pdf.Font, "", candidateSize)
-
-						candWidth := p.GetStringWidth(cand)
-						if candWidth > candidateSpace {
-							p.SetFontSize(candidateSpace / candWidth * candidateSize)
-						}
-						p.SetTextColor(pdf.CandidateColor.R, pdf.CandidateColor.G, pdf.CandidateColor.B)
-						p.SetXY(originX+float64(x)*cellSize, originY+float64(y)*cellSize+lineMargin)
-						p.CellFormat(cellSize, cellSize, cand, "0", 0, "CT", false, 0, "")
-					}
-				}
+				pdf.generateCell(p, puzzle.Get(x, y), originX+float64(x)*cellSize, originY+float64(y)*cellSize, cellSize, item.Candidates)
 			}
 		}
 
 		p.SetLineWidth(pdf.BorderThickWidth)
 		p.SetLineCapStyle("round")
 
-		for y := 0.0; y <= puzzleSize+0.0001; y += boxH {
-			p.Line(originX, originY+y, originX+puzzleSize, originY+y)
-		}
+		if puzzle.Kind.Irregular() {
+			pdf.generateRegions(p, originX, originY, size, size, cellSize, func(col int, row int) int {
+				if col < 0 || row < 0 || col >= size || row >= size {
+					return -1
+				}
+				return puzzle.Get(col, row).Box
+			})
+		} else {
+			for y := 0.0; y <= puzzleSize+0.0001; y += boxH {
+				p.Line(originX, originY+y, originX+puzzleSize, originY+y)
+			}
 
-		for x := 0.0; x <= puzzleSize+0.0001; x += boxW {
-			p.Line(originX+x, originY, originX+x, originY+puzzleSize)
+			for x := 0.0; x <= puzzleSize+0.0001; x += boxW {
+				p.Line(originX+x, originY, originX+x, originY+puzzleSize)
+			}
 		}
 
+		pdf.generateCages(p, puzzle, originX, originY, cellSize)
+		pdf.generateMarkers(p, puzzle, originX, originY, cellSize)
+
 		if item.StateString {
 			p.SetFontSize(fontSize)
 			stateString := puzzle.ToStateString(true, ".")
@@ -257,3 +285,258 @@ func (pdf *PuzzlePDF) Generate() *gofpdf.Fpdf {
 
 	return p
 }
+
+// Draws the cell with its top left corner at x and y, with its candidates when it's empty and candidates is true.
+func (pdf *PuzzlePDF) generateCell(p *gofpdf.Fpdf, cell *Cell, x float64, y float64, cellSize float64, candidates bool) {
+	fontSize := cellSize * pdf.ValueFontScale
+	lineMargin := pdf.BorderThickWidth * 2
+	candidateSpace := cellSize - lineMargin
+	candidateSize := cellSize * pdf.CandidateScale
+
+	p.SetXY(x, y)
+	if cell.HasValue() {
+		cellValue := strconv.Itoa(cell.Value)
+		fill := !pdf.ValueBackColor.Is(ColorWhite)
+
+		p.SetFont(pdf.Font, "B", fontSize)
+		p.SetTextColor(pdf.ValueFontColor.R, pdf.ValueFontColor.G, pdf.ValueFontColor.B)
+		p.CellFormat(cellSize, cellSize, cellValue, "1", 0, "CM", fill, 0, "")
+	} else {
+		p.SetFont(pdf.Font, "", fontSize)
+		p.CellFormat(cellSize, cellSize, "", "1", 0, "CM", false, 0, "")
+
+		if candidates {
+			cand := fmt.Sprintf("%v", cell.Candidates())
+			cand = strings.Trim(cand, "[]")
+
+			p.SetFont(pdf.Font, "", candidateSize)
+
+			candWidth := p.GetStringWidth(cand)
+			if candWidth > candidateSpace {
+				p.SetFontSize(candidateSpace / candWidth * candidateSize)
+			}
+			p.SetTextColor(pdf.CandidateColor.R, pdf.CandidateColor.G, pdf.CandidateColor.B)
+			p.SetXY(x, y+lineMargin)
+			p.CellFormat(cellSize, cellSize, cand, "0", 0, "CT", false, 0, "")
+		}
+	}
+}
+
+// Draws a thick line between each pair of neighboring cells in different boxes of a layout of cols by rows
+// cells, where boxAt returns the box of a cell or -1 where there is no cell.
+func (pdf *PuzzlePDF) generateRegions(p *gofpdf.Fpdf, originX float64, originY float64, cols int, rows int, cellSize float64, boxAt func(col int, row int) int) {
+	for y := -1; y < rows; y++ {
+		for x := -1; x < cols; x++ {
+			box := boxAt(x, y)
+			cellX := originX + float64(x)*cellSize
+			cellY := originY + float64(y)*cellSize
+			if right := boxAt(x+1, y); right != box && y >= 0 {
+				p.Line(cellX+cellSize, cellY, cellX+cellSize, cellY+cellSize)
+			}
+			if below := boxAt(x, y+1); below != box && x >= 0 {
+				p.Line(cellX, cellY+cellSize, cellX+cellSize, cellY+cellSize)
+			}
+		}
+	}
+}
+
+// Draws the multi-grid puzzle of the item in the square at the origin.
+func (pdf *PuzzlePDF) generateMulti(p *gofpdf.Fpdf, item PuzzlePDFItem, originX float64, originY float64, puzzleSize float64) {
+	puzzle := item.Multi
+	cols, rows := puzzle.Kind.Width(), puzzle.Kind.Height()
+	cellSize := puzzleSize / float64(Max(cols, rows))
+	originX += (puzzleSize - cellSize*float64(cols)) / 2
+	originY += (puzzleSize - cellSize*float64(rows)) / 2
+
+	p.SetLineWidth(pdf.BorderThinWidth)
+	p.SetDrawColor(pdf.BorderThinColor.R, pdf.BorderThinColor.G, pdf.BorderThinColor.B)
+	p.SetFillColor(pdf.ValueBackColor.R, pdf.ValueBackColor.G, pdf.ValueBackColor.B)
+
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			if cells := puzzle.Get(x, y); len(cells) > 0 {
+				pdf.generateCell(p, cells[0], originX+float64(x)*cellSize, originY+float64(y)*cellSize, cellSize, item.Candidates)
+			}
+		}
+	}
+
+	p.SetLineWidth(pdf.BorderThickWidth)
+	p.SetLineCapStyle("round")
+	p.SetDrawColor(pdf.BorderThickColor.R, pdf.BorderThickColor.G, pdf.BorderThickColor.B)
+
+	pdf.generateRegions(p, originX, originY, cols, rows, cellSize, puzzle.Kind.boxAt)
+}
+
+// Draws a dashed outline just inside the cells of each cage of the puzzle's kind and the cage's sum in the
+// top left corner of its top left cell.
+func (pdf *PuzzlePDF) generateCages(p *gofpdf.Fpdf, puzzle *Puzzle, originX float64, originY float64, cellSize float64) {
+	cages := puzzle.Kind.Cages()
+	if len(cages) == 0 {
+		return
+	}
+	size := puzzle.Kind.Size()
+	cageIndexes, _ := puzzle.Kind.CageIndexes()
+	cageAt := func(col int, row int) int {
+		if col < 0 || row < 0 || col >= size || row >= size {
+			return -1
+		}
+		return cageIndexes[row*size+col]
+	}
+	inset := cellSize * 0.08
+	dash := cellSize * 0.06
+
+	p.SetLineWidth(pdf.CageWidth)
+	p.SetLineCapStyle("butt")
+	p.SetDrawColor(pdf.CageColor.R, pdf.CageColor.G, pdf.CageColor.B)
+	p.SetDashPattern([]float64{dash, dash}, 0)
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			cage := cageAt(x, y)
+			if cage == -1 {
+				continue
+			}
+			same := func(dx int, dy int) bool {
+				return cageAt(x+dx, y+dy) == cage
+			}
+			// Where an edge starts or ends: inset at a corner of the cage, at the cell's edge when the edge
+			// continues into the next cell, and past it when the cage turns back around a missing cell.
+			end := func(edge float64, dir float64, beside bool, corner bool) float64 {
+				if !beside {
+					return edge - dir*inset
+				} else if corner {
+					return edge + dir*inset
+				}
+				return edge
+			}
+			left := originX + float64(x)*cellSize
+			top := originY + float64(y)*cellSize
+			right := left + cellSize
+			bottom := top + cellSize
+
+			if !same(0, -1) {
+				p.Line(end(left, -1, same(-1, 0), same(-1, -1)), top+inset, end(right, 1, same(1, 0), same(1, -1)), top+inset)
+			}
+			if !same(0, 1) {
+				p.Line(end(left, -1, same(-1, 0), same(-1, 1)), bottom-inset, end(right, 1, same(1, 0), same(1, 1)), bottom-inset)
+			}
+			if !same(-1, 0) {
+				p.Line(left+inset, end(top, -1, same(0, -1), same(-1, -1)), left+inset, end(bottom, 1, same(0, 1), same(-1, 1)))
+			}
+			if !same(1, 0) {
+				p.Line(right-inset, end(top, -1, same(0, -1), same(1, -1)), right-inset, end(bottom, 1, same(0, 1), same(1, 1)))
+			}
+		}
+	}
+
+	p.SetDashPattern([]float64{}, 0)
+
+	sumSize := cellSize * pdf.CageSumScale
+	p.SetFont(pdf.Font, "", sumSize)
+	p.SetTextColor(pdf.CageColor.R, pdf.CageColor.G, pdf.CageColor.B)
+	p.SetFillColor(pdf.ValueBackColor.R, pdf.ValueBackColor.G, pdf.ValueBackColor.B)
+	for _, cage := range cages {
+		first := cage.First()
+		sum := strconv.Itoa(cage.Sum)
+		p.SetXY(originX+float64(first.Col)*cellSize+inset*0.5, originY+float64(first.Row)*cellSize+inset*0.5)
+		p.CellFormat(p.GetStringWidth(sum)+inset, sumSize, sum, "0", 0, "LM", true, 0, "")
+	}
+}
+
+// Shades the even cells of the puzzle's kind with a square and the odd cells with a circle.
+func (pdf *PuzzlePDF) generateParities(p *gofpdf.Fpdf, puzzle *Puzzle, originX float64, originY float64, cellSize float64) {
+	p.SetFillColor(pdf.ParityColor.R, pdf.ParityColor.G, pdf.ParityColor.B)
+	inset := cellSize * 0.1
+
+	for _, parity := range puzzle.Kind.Parities() {
+		for _, pos := range parity.Cells {
+			x := originX + float64(pos.Col)*cellSize
+			y := originY + float64(pos.Row)*cellSize
+			if parity.Odd {
+				p.Circle(x+cellSize*0.5, y+cellSize*0.5, cellSize*0.5-inset, "F")
+			} else {
+				p.Rect(x+inset, y+inset, cellSize-inset*2, cellSize-inset*2, "F")
+			}
+		}
+	}
+}
+
+// Draws each marker of the puzzle's kind over the border between its cells, with white and black dots as circles and
+// X and V markers as letters on a small background.
+func (pdf *PuzzlePDF) generateMarkers(p *gofpdf.Fpdf, puzzle *Puzzle, originX float64, originY float64, cellSize float64) {
+	markers := puzzle.Kind.Markers()
+	if len(markers) == 0 {
+		return
+	}
+	markerSize := cellSize * pdf.MarkerScale
+
+	p.SetLineWidth(pdf.BorderThinWidth)
+	p.SetDrawColor(pdf.MarkerColor.R, pdf.MarkerColor.G, pdf.MarkerColor.B)
+	p.SetTextColor(pdf.MarkerColor.R, pdf.MarkerColor.G, pdf.MarkerColor.B)
+	p.SetFont(pdf.Font, "B", markerSize*1.2)
+
+	for _, marker := range markers {
+		x := originX + (float64(marker.First.Col+marker.Second.Col)*0.5+0.5)*cellSize
+		y := originY + (float64(marker.First.Row+marker.Second.Row)*0.5+0.5)*cellSize
+
+		switch marker.Type {
+		case MarkerWhiteDot:
+			p.SetFillColor(pdf.ValueBackColor.R, pdf.ValueBackColor.G, pdf.ValueBackColor.B)
+			p.Circle(x, y, markerSize*0.5, "DF")
+		case MarkerBlackDot:
+			p.SetFillColor(pdf.MarkerColor.R, pdf.MarkerColor.G, pdf.MarkerColor.B)
+			p.Circle(x, y, markerSize*0.5, "DF")
+		default:
+			p.SetFillColor(pdf.ValueBackColor.R, pdf.ValueBackColor.G, pdf.ValueBackColor.B)
+			p.SetXY(x-markerSize*0.5, y-markerSize*0.5)
+			p.CellFormat(markerSize, markerSize, marker.Type.String(), "0", 0, "CM", true, 0, "")
+		}
+	}
+}
+
+// Draws each line of the puzzle's kind through the centers of its cells, with a filled bulb at the start of a
+// thermometer and circles at the ends of a between line. Lines are drawn under the cells so values stay readable.
+func (pdf *PuzzlePDF) generateLines(p *gofpdf.Fpdf, puzzle *Puzzle, originX float64, originY float64, cellSize float64) {
+	lines := puzzle.Kind.Lines()
+	if len(lines) == 0 {
+		return
+	}
+	center := func(pos Position) (float64, float64) {
+		return originX + (float64(pos.Col)+0.5)*cellSize, originY + (float64(pos.Row)+0.5)*cellSize
+	}
+
+	p.SetLineWidth(cellSize * pdf.LineWidthScale)
+	p.SetLineCapStyle("round")
+	p.SetLineJoinStyle("round")
+
+	for _, line := range lines {
+		color, exists := pdf.LineColors[line.LineType()]
+		if !exists {
+			color = ColorGray
+		}
+		p.SetDrawColor(color.R, color.G, color.B)
+		p.SetFillColor(color.R, color.G, color.B)
+
+		path := line.LinePath()
+		for i := 1; i < len(path); i++ {
+			x0, y0 := center(path[i-1])
+			x1, y1 := center(path[i])
+			p.Line(x0, y0, x1, y1)
+		}
+
+		switch line.LineType() {
+		case LineThermo, LineSlowThermo:
+			x, y := center(path[0])
+			p.Circle(x, y, cellSize*0.35, "F")
+		case LineBetween:
+			p.SetFillColor(pdf.ValueBackColor.R, pdf.ValueBackColor.G, pdf.ValueBackColor.B)
+			for _, end := range []Position{path[0], path[len(path)-1]} {
+				x, y := center(end)
+				p.Circle(x, y, cellSize*0.4, "DF")
+			}
+		}
+	}
+
+	p.SetLineCapStyle("butt")
+	p.SetLineJoinStyle("miter")
+}
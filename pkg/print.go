@@ -27,6 +27,29 @@ func (puzzle *Puzzle) WriteConsole(out io.Writer) {
 	digitSize := puzzle.Kind.DigitsSize()
 	digitFormat := "%" + strconv.Itoa(digitSize) + "d"
 	empty := strings.Repeat(" ", digitSize)
+
+	writeCell := writeConsoleValue(out, puzzle.Kind)
+	if len(puzzle.Kind.Parities()) > 0 {
+		defer puzzle.writeConsoleParityLegend(out)
+	}
+	lines := len(puzzle.Kind.Lines()) > 0
+	if lines {
+		writeCell = puzzle.writeConsoleLinePaths(out, writeCell)
+		// The legend of the lines goes below the puzzle.
+		defer puzzle.writeConsoleLineLegend(out)
+	}
+	markers := len(puzzle.Kind.Markers()) > 0
+	defer puzzle.writeConsoleMarkerLegend(out)
+
+	if len(puzzle.Kind.Cages()) > 0 {
+		puzzle.writeConsoleCages(out, digitSize, 1, writeCell)
+		return
+	}
+	if puzzle.Kind.Irregular() || lines || markers {
+		puzzle.writeConsoleRegions(out, digitSize, 1, writeCell)
+		return
+	}
+
 	thickH := strings.Repeat("\u2550", digitSize)
 	thinH := strings.Repeat("\u2500", digitSize)
 	thickV := "\u2551"
@@ -141,9 +164,6 @@ func (puzzle *Puzzle) ToConsoleCandidatesString() string {
 func (puzzle *Puzzle) WriteConsoleCandidates(out io.Writer) {
 	boxsWide, boxsHigh, boxWidth, boxHeight, _ := puzzle.Kind.GetDimensions()
 	digitSize := puzzle.Kind.DigitsSize()
-	digitFormat := "%" + strconv.Itoa(digitSize) + "d"
-	empty := strings.Repeat(" ", digitSize)
-	solved := strings.Repeat("\u2591", digitSize)
 	thickH := strings.Repeat("\u2550", digitSize*boxWidth)
 	thinH := strings.Repeat("\u2500", digitSize*boxWidth)
 	thickV := "\u2551"
@@ -194,32 +214,29 @@ func (puzzle *Puzzle) WriteConsoleCandidates(out io.Writer) {
 		})
 	}
 
-	centerX := boxWidth / 2
-	centerY := (boxHeight - 1) / 2
+	writeCell := writeConsoleCandidates(out, puzzle.Kind)
+	if len(puzzle.Kind.Parities()) > 0 {
+		defer puzzle.writeConsoleParityLegend(out)
+	}
+	if len(puzzle.Kind.Lines()) > 0 {
+		// The legend of the lines goes below the puzzle.
+		defer puzzle.writeConsoleLineLegend(out)
+	}
+	defer puzzle.writeConsoleMarkerLegend(out)
+
+	if len(puzzle.Kind.Cages()) > 0 {
+		puzzle.writeConsoleCages(out, digitSize*boxWidth, boxHeight, writeCell)
+		return
+	}
+	if puzzle.Kind.Irregular() || len(puzzle.Kind.Markers()) > 0 {
+		puzzle.writeConsoleRegions(out, digitSize*boxWidth, boxHeight, writeCell)
+		return
+	}
 
 	appendRow := func(row []*Cell) {
 		for cellRow := 0; cellRow < boxHeight; cellRow++ {
-
 			appendLine(thickV, thinV, thickV, thickV, func(column int) {
-				cell := row[column]
-				if cell.Empty() {
-					for cellCol := 0; cellCol < boxWidth; cellCol++ {
-						candidate := cellRow*boxWidth + cellCol + 1
-						if cell.candidates.Has(candidate) {
-							io.WriteString(out, fmt.Sprintf(digitFormat, candidate))
-						} else {
-							io.WriteString(out, empty)
-						}
-					}
-				} else {
-					for cellCol := 0; cellCol < boxWidth; cellCol++ {
-						if cellCol == centerX && cellRow == centerY {
-							io.WriteString(out, fmt.Sprintf(digitFormat, cell.Value))
-						} else {
-							io.WriteString(out, solved)
-						}
-					}
-				}
+				writeCell(row[column], cellRow)
 			})
 		}
 	}
@@ -244,3 +261,429 @@ func (puzzle *Puzzle) WriteConsoleCandidates(out io.Writer) {
 		}
 	}
 }
+
+// Returns a function which writes the value of a cell of the kind, which is one line high.
+func writeConsoleValue(out io.Writer, kind *Kind) func(cell *Cell, line int) {
+	digitSize := kind.DigitsSize()
+	digitFormat := "%" + strconv.Itoa(digitSize) + "d"
+	empty := strings.Repeat(" ", digitSize)
+
+	return func(cell *Cell, line int) {
+		if cell.Empty() {
+			io.WriteString(out, empty)
+		} else {
+			io.WriteString(out, fmt.Sprintf(digitFormat, cell.Value))
+		}
+	}
+}
+
+// Returns a function which writes a line of the candidates of a cell of the kind, or its value in the center
+// of the cell when it has one. Cells are as many candidates wide and high as the kind's boxes.
+func writeConsoleCandidates(out io.Writer, kind *Kind) func(cell *Cell, line int) {
+	boxWidth := kind.BoxSize.Width
+	digitSize := kind.DigitsSize()
+	digitFormat := "%" + strconv.Itoa(digitSize) + "d"
+	empty := strings.Repeat(" ", digitSize)
+	solved := strings.Repeat("\u2591", digitSize)
+	centerX := boxWidth / 2
+	centerY := (kind.BoxSize.Height - 1) / 2
+
+	return func(cell *Cell, cellRow int) {
+		if cell.Empty() {
+			for cellCol := 0; cellCol < boxWidth; cellCol++ {
+				candidate := cellRow*boxWidth + cellCol + 1
+				if cell.candidates.Has(candidate) {
+					io.WriteString(out, fmt.Sprintf(digitFormat, candidate))
+				} else {
+					io.WriteString(out, empty)
+				}
+			}
+		} else {
+			for cellCol := 0; cellCol < boxWidth; cellCol++ {
+				if cellCol == centerX && cellRow == centerY {
+					io.WriteString(out, fmt.Sprintf(digitFormat, cell.Value))
+				} else {
+					io.WriteString(out, solved)
+				}
+			}
+		}
+	}
+}
+
+// The console junctions between borders by the weight of the border up, right, down, and left where 0 is
+// no border, 1 is between cells in the same box, and 2 is between boxes.
+var consoleJunctions = map[[4]int]string{
+	{0, 0, 0, 0}: " ",
+	{0, 0, 0, 1}: "\u2574",
+	{0, 0, 0, 2}: "\u2578",
+	{0, 0, 1, 0}: "\u2577",
+	{0, 0, 1, 1}: "\u2510",
+	{0, 0, 1, 2}: "\u2511",
+	{0, 0, 2, 0}: "\u257B",
+	{0, 0, 2, 1}: "\u2512",
+	{0, 0, 2, 2}: "\u2513",
+	{0, 1, 0, 0}: "\u2576",
+	{0, 1, 0, 1}: "\u2500",
+	{0, 1, 0, 2}: "\u257E",
+	{0, 1, 1, 0}: "\u250C",
+	{0, 1, 1, 1}: "\u252C",
+	{0, 1, 1, 2}: "\u252D",
+	{0, 1, 2, 0}: "\u250E",
+	{0, 1, 2, 1}: "\u2530",
+	{0, 1, 2, 2}: "\u2531",
+	{0, 2, 0, 0}: "\u257A",
+	{0, 2, 0, 1}: "\u257C",
+	{0, 2, 0, 2}: "\u2501",
+	{0, 2, 1, 0}: "\u250D",
+	{0, 2, 1, 1}: "\u252E",
+	{0, 2, 1, 2}: "\u252F",
+	{0, 2, 2, 0}: "\u250F",
+	{0, 2, 2, 1}: "\u2532",
+	{0, 2, 2, 2}: "\u2533",
+	{1, 0, 0, 0}: "\u2575",
+	{1, 0, 0, 1}: "\u2518",
+	{1, 0, 0, 2}: "\u2519",
+	{1, 0, 1, 0}: "\u2502",
+	{1, 0, 1, 1}: "\u2524",
+	{1, 0, 1, 2}: "\u2525",
+	{1, 0, 2, 0}: "\u257D",
+	{1, 0, 2, 1}: "\u2527",
+	{1, 0, 2, 2}: "\u252A",
+	{1, 1, 0, 0}: "\u2514",
+	{1, 1, 0, 1}: "\u2534",
+	{1, 1, 0, 2}: "\u2535",
+	{1, 1, 1, 0}: "\u251C",
+	{1, 1, 1, 1}: "\u253C",
+	{1, 1, 1, 2}: "\u253D",
+	{1, 1, 2, 0}: "\u251F",
+	{1, 1, 2, 1}: "\u2541",
+	{1, 1, 2, 2}: "\u2545",
+	{1, 2, 0, 0}: "\u2515",
+	{1, 2, 0, 1}: "\u2536",
+	{1, 2, 0, 2}: "\u2537",
+	{1, 2, 1, 0}: "\u251D",
+	{1, 2, 1, 1}: "\u253E",
+	{1, 2, 1, 2}: "\u253F",
+	{1, 2, 2, 0}: "\u2522",
+	{1, 2, 2, 1}: "\u2546",
+	{1, 2, 2, 2}: "\u2548",
+	{2, 0, 0, 0}: "\u2579",
+	{2, 0, 0, 1}: "\u251A",
+	{2, 0, 0, 2}: "\u251B",
+	{2, 0, 1, 0}: "\u257F",
+	{2, 0, 1, 1}: "\u2526",
+	{2, 0, 1, 2}: "\u2529",
+	{2, 0, 2, 0}: "\u2503",
+	{2, 0, 2, 1}: "\u2528",
+	{2, 0, 2, 2}: "\u252B",
+	{2, 1, 0, 0}: "\u2516",
+	{2, 1, 0, 1}: "\u2538",
+	{2, 1, 0, 2}: "\u2539",
+	{2, 1, 1, 0}: "\u251E",
+	{2, 1, 1, 1}: "\u2540",
+	{2, 1, 1, 2}: "\u2543",
+	{2, 1, 2, 0}: "\u2520",
+	{2, 1, 2, 1}: "\u2542",
+	{2, 1, 2, 2}: "\u2549",
+	{2, 2, 0, 0}: "\u2517",
+	{2, 2, 0, 1}: "\u253A",
+	{2, 2, 0, 2}: "\u253B",
+	{2, 2, 1, 0}: "\u2521",
+	{2, 2, 1, 1}: "\u2544",
+	{2, 2, 1, 2}: "\u2547",
+	{2, 2, 2, 0}: "\u2523",
+	{2, 2, 2, 1}: "\u254A",
+	{2, 2, 2, 2}: "\u254B",
+}
+
+// The console borders by weight, where 3 is a dotted border between cages which is drawn as a 1 at junctions.
+var consoleHorizontals = []string{" ", "\u2500", "\u2501", "\u2504"}
+var consoleVerticals = []string{" ", "\u2502", "\u2503", "\u2506"}
+var consoleJunctionWeights = []int{0, 1, 2, 1}
+
+// Writes the puzzle with a heavy border between cells in different boxes, which draws irregular boxes. Each
+// cell is width characters wide and height lines high, and writeCell writes one line of a cell.
+func (puzzle *Puzzle) writeConsoleRegions(out io.Writer, width int, height int, writeCell func(cell *Cell, line int)) {
+	size := puzzle.Kind.Size()
+	boxAt := func(col int, row int) int {
+		if col < 0 || row < 0 || col >= size || row >= size {
+			return -1
+		}
+		return puzzle.Get(col, row).Box
+	}
+	writeConsoleLayout(out, size, size, width, height, boxAt, nil, puzzle.consoleMarkerAt(), func(col int, row int, line int) {
+		writeCell(puzzle.Get(col, row), line)
+	})
+}
+
+// Writes the puzzle with a dotted border around each cage of its kind and the sum of each cage above the
+// value or candidates of its top left cell. Each cell is at least width characters wide and height + 1 lines
+// high, and writeCell writes one line of a cell.
+func (puzzle *Puzzle) writeConsoleCages(out io.Writer, width int, height int, writeCell func(cell *Cell, line int)) {
+	size := puzzle.Kind.Size()
+	cages := puzzle.Kind.Cages()
+	cageIndexes, _ := puzzle.Kind.CageIndexes()
+
+	labels := make(map[Position]string, len(cages))
+	cellWidth := width
+	for _, cage := range cages {
+		label := strconv.Itoa(cage.Sum)
+		labels[cage.First()] = label
+		cellWidth = Max(cellWidth, len(label))
+	}
+	padLeft := strings.Repeat(" ", (cellWidth-width+1)/2)
+	padRight := strings.Repeat(" ", (cellWidth-width)/2)
+
+	inside := func(col int, row int) bool {
+		return col >= 0 && row >= 0 && col < size && row < size
+	}
+	boxAt := func(col int, row int) int {
+		if !inside(col, row) {
+			return -1
+		}
+		return puzzle.Get(col, row).Box
+	}
+	cageAt := func(col int, row int) int {
+		if !inside(col, row) {
+			return -1
+		}
+		return cageIndexes[row*size+col]
+	}
+
+	writeConsoleLayout(out, size, size, cellWidth, height+1, boxAt, cageAt, puzzle.consoleMarkerAt(), func(col int, row int, line int) {
+		if line == 0 {
+			io.WriteString(out, fmt.Sprintf("%-*s", cellWidth, labels[Position{col, row}]))
+		} else {
+			io.WriteString(out, padLeft)
+			writeCell(puzzle.Get(col, row), line-1)
+			io.WriteString(out, padRight)
+		}
+	})
+}
+
+// The arrows from a cell on a line to the next cell by the direction to it.
+var consoleLineArrows = map[Position]string{
+	{1, 0}:   "\u2192",
+	{-1, 0}:  "\u2190",
+	{0, 1}:   "\u2193",
+	{0, -1}:  "\u2191",
+	{1, 1}:   "\u2198",
+	{1, -1}:  "\u2197",
+	{-1, 1}:  "\u2199",
+	{-1, -1}: "\u2196",
+}
+
+// Returns a function which writes a cell like writeCell, except for empty cells on a line of the puzzle's kind
+// which have a circle at the bulb of a thermometer and the ends of a between line, an arrow to the next cell
+// of the line, and a dot at the end of the line.
+func (puzzle *Puzzle) writeConsoleLinePaths(out io.Writer, writeCell func(cell *Cell, line int)) func(cell *Cell, line int) {
+	digitSize := puzzle.Kind.DigitsSize()
+	glyphs := make(map[Position]string)
+
+	for _, line := range puzzle.Kind.Lines() {
+		path := line.LinePath()
+		circles := line.LineType() == LineThermo || line.LineType() == LineSlowThermo || line.LineType() == LineBetween
+		for i, pos := range path {
+			if _, exists := glyphs[pos]; exists {
+				continue
+			}
+			glyph := "\u2022"
+			if circles && (i == 0 || (i == len(path)-1 && line.LineType() == LineBetween)) {
+				glyph = "\u25CB"
+			} else if i < len(path)-1 {
+				next := path[i+1]
+				if arrow, ok := consoleLineArrows[Position{next.Col - pos.Col, next.Row - pos.Row}]; ok {
+					glyph = arrow
+				}
+			}
+			glyphs[pos] = glyph
+		}
+	}
+
+	return func(cell *Cell, line int) {
+		glyph, exists := glyphs[Position{cell.Col, cell.Row}]
+		if cell.Empty() && exists {
+			io.WriteString(out, strings.Repeat(" ", digitSize-1)+glyph)
+		} else {
+			writeCell(cell, line)
+		}
+	}
+}
+
+// Writes the type and cells of each line of the puzzle's kind, one line each.
+func (puzzle *Puzzle) writeConsoleLineLegend(out io.Writer) {
+	for _, line := range puzzle.Kind.Lines() {
+		io.WriteString(out, line.LineType().String()+":")
+		for _, pos := range line.LinePath() {
+			io.WriteString(out, fmt.Sprintf(" r%dc%d", pos.Row+1, pos.Col+1))
+		}
+		io.WriteString(out, "\n")
+	}
+}
+
+// Writes the even and odd cells of the puzzle's kind, one line each.
+func (puzzle *Puzzle) writeConsoleParityLegend(out io.Writer) {
+	for _, parity := range puzzle.Kind.Parities() {
+		if parity.Odd {
+			io.WriteString(out, "Odd:")
+		} else {
+			io.WriteString(out, "Even:")
+		}
+		for _, pos := range parity.Cells {
+			io.WriteString(out, fmt.Sprintf(" r%dc%d", pos.Row+1, pos.Col+1))
+		}
+		io.WriteString(out, "\n")
+	}
+}
+
+// The console glyph of each marker type.
+var consoleMarkers = []string{"\u25CB", "\u25CF", "X", "V"}
+
+// Returns a function which returns the glyph of the marker between two cells of the puzzle's kind or an empty
+// string, or nil when the kind has no markers.
+func (puzzle *Puzzle) consoleMarkerAt() func(aCol int, aRow int, bCol int, bRow int) string {
+	markers := puzzle.Kind.Markers()
+	if len(markers) == 0 {
+		return nil
+	}
+	return func(aCol int, aRow int, bCol int, bRow int) string {
+		a, b := Position{aCol, aRow}, Position{bCol, bRow}
+		for _, marker := range markers {
+			if marker.Between(a, b) {
+				return consoleMarkers[marker.Type]
+			}
+		}
+		return ""
+	}
+}
+
+// Writes a line for each marker constraint of the puzzle's kind where every marker is given, since the missing
+// markers are part of the puzzle.
+func (puzzle *Puzzle) writeConsoleMarkerLegend(out io.Writer) {
+	for _, constraint := range puzzle.Kind.Constraints {
+		switch c := constraint.(type) {
+		case *ConstraintKropki:
+			if c.Negative {
+				io.WriteString(out, "Kropki: all dots are given\n")
+			}
+		case *ConstraintXV:
+			if c.Negative {
+				io.WriteString(out, "XV: all markers are given\n")
+			}
+		}
+	}
+}
+
+// Writes a layout of cols by rows cells with a heavy border between cells in different boxes and around the
+// cells. boxAt returns the box of the cell at a position or -1 where there is no cell, which is left blank.
+// When cageAt is given it returns the cage of a cell or -1, cells in the same cage have no border between them
+// and cells in different cages have a dotted border. When markerAt is given it returns the glyph drawn in the middle
+// of the border between two cells or an empty string. Each cell is width characters wide and height lines high,
+// and writeCell writes one line of a cell.
+func writeConsoleLayout(out io.Writer, cols int, rows int, width int, height int, boxAt func(col int, row int) int, cageAt func(col int, row int) int, markerAt func(aCol int, aRow int, bCol int, bRow int) string, writeCell func(col int, row int, line int)) {
+	blank := strings.Repeat(" ", width)
+	marker := func(aCol int, aRow int, bCol int, bRow int) string {
+		if markerAt == nil {
+			return ""
+		}
+		return markerAt(aCol, aRow, bCol, bRow)
+	}
+	border := func(aCol int, aRow int, bCol int, bRow int) int {
+		a, b := boxAt(aCol, aRow), boxAt(bCol, bRow)
+		if a == -1 && b == -1 {
+			return 0
+		} else if a != b {
+			return 2
+		} else if cageAt != nil {
+			aCage, bCage := cageAt(aCol, aRow), cageAt(bCol, bRow)
+			if aCage == bCage && aCage != -1 {
+				return 0
+			} else if aCage != -1 || bCage != -1 {
+				return 3
+			}
+		}
+		return 1
+	}
+
+	for row := 0; row <= rows; row++ {
+		for col := 0; col <= cols; col++ {
+			up := border(col-1, row-1, col, row-1)
+			right := border(col, row-1, col, row)
+			down := border(col-1, row, col, row)
+			left := border(col-1, row-1, col-1, row)
+			if up == 0 && down == 0 && left == 3 && right == 3 {
+				io.WriteString(out, consoleHorizontals[3])
+			} else if left == 0 && right == 0 && up == 3 && down == 3 {
+				io.WriteString(out, consoleVerticals[3])
+			} else {
+				junction := [4]int{consoleJunctionWeights[up], consoleJunctionWeights[right], consoleJunctionWeights[down], consoleJunctionWeights[left]}
+				io.WriteString(out, consoleJunctions[junction])
+			}
+			if col < cols {
+				if glyph := marker(col, row-1, col, row); glyph != "" {
+					io.WriteString(out, strings.Repeat(consoleHorizontals[right], (width-1)/2)+glyph+strings.Repeat(consoleHorizontals[right], width/2))
+				} else {
+					io.WriteString(out, strings.Repeat(consoleHorizontals[right], width))
+				}
+			}
+		}
+		io.WriteString(out, "\n")
+
+		if row == rows {
+			break
+		}
+		for line := 0; line < height; line++ {
+			for col := 0; col <= cols; col++ {
+				if glyph := marker(col-1, row, col, row); glyph != "" && line == height/2 {
+					io.WriteString(out, glyph)
+				} else {
+					io.WriteString(out, consoleVerticals[border(col-1, row, col, row)])
+				}
+				if col < cols {
+					if boxAt(col, row) == -1 {
+						io.WriteString(out, blank)
+					} else {
+						writeCell(col, row, line)
+					}
+				}
+			}
+			io.WriteString(out, "\n")
+		}
+	}
+}
+
+func (puzzle *MultiPuzzle) PrintConsole() {
+	print(puzzle.ToConsoleString())
+}
+
+func (puzzle *MultiPuzzle) ToConsoleString() string {
+	return toString(puzzle.WriteConsole)
+}
+
+// Writes the combined layout of the grids with a heavy border around each box and positions without a cell
+// left blank.
+func (puzzle *MultiPuzzle) WriteConsole(out io.Writer) {
+	kind := puzzle.Grids[0].Kind
+	puzzle.writeConsoleLayout(out, kind.DigitsSize(), 1, writeConsoleValue(out, kind))
+}
+
+func (puzzle *MultiPuzzle) PrintConsoleCandidates() {
+	print(puzzle.ToConsoleCandidatesString())
+}
+
+func (puzzle *MultiPuzzle) ToConsoleCandidatesString() string {
+	return toString(puzzle.WriteConsoleCandidates)
+}
+
+// Writes the combined layout of the grids with the candidates of each cell. The candidates of a shared
+// cell are those of the first grid it's in.
+func (puzzle *MultiPuzzle) WriteConsoleCandidates(out io.Writer) {
+	kind := puzzle.Grids[0].Kind
+	puzzle.writeConsoleLayout(out, kind.DigitsSize()*kind.BoxSize.Width, kind.BoxSize.Height, writeConsoleCandidates(out, kind))
+}
+
+func (puzzle *MultiPuzzle) writeConsoleLayout(out io.Writer, width int, height int, writeCell func(cell *Cell, line int)) {
+	writeConsoleLayout(out, puzzle.Kind.Width(), puzzle.Kind.Height(), width, height, puzzle.Kind.boxAt, nil, nil, func(col int, row int, line int) {
+		writeCell(puzzle.Get(col, row)[0], line)
+	})
+}
@@ -15,7 +15,7 @@ type Puzzle struct {
 }
 
 func New(kind *Kind) Puzzle {
-	boxsWide, _, boxWidth, boxHeight, size := kind.GetDimensions()
+	size := kind.Size()
 	cellCount := size * size
 	cells := make([]Cell, cellCount)
 
@@ -25,8 +25,10 @@ func New(kind *Kind) Puzzle {
 		cell.Value = 0
 		cell.Row = i / size
 		cell.Col = i % size
-		cell.Box = ((cell.Row / boxHeight) * boxsWide) + (cell.Col / boxWidth)
+		cell.Box = kind.BoxAt(cell.Col, cell.Row)
 		cell.Constraints = kind.ConstraintsFor(cell)
+		cell.Houses = kind.HousesFor(cell)
+		cell.Links = kind.LinksFor(cell)
 		cell.candidates.Fill(size)
 	}
 
@@ -231,6 +233,7 @@ func (puzzle *Puzzle) IsSolved() bool {
 	rows := make([]Candidates, size)
 	cols := make([]Candidates, size)
 	boxs := make([]Candidates, size)
+	houses := make([]Candidates, len(puzzle.Kind.Houses))
 
 	complete := Candidates{}
 	complete.Fill(size)
@@ -244,21 +247,35 @@ func (puzzle *Puzzle) IsSolved() bool {
 		rows[cell.Row].Set(cell.Value, true)
 		cols[cell.Col].Set(cell.Value, true)
 		boxs[cell.Box].Set(cell.Value, true)
+		for _, house := range cell.Houses {
+			houses[house].Set(cell.Value, true)
+		}
+		for _, link := range cell.Links {
+			if puzzle.Cells[link].Value == cell.Value {
+				return false
+			}
+		}
+	}
+
+	for i := range houses {
+		if !houses[i].Equals(complete) {
+			return false
+		}
 	}
 
 	for i := 0; i < size; i++ {
-		if rows[i].Value != complete.Value {
+		if !rows[i].Equals(complete) {
 			return false
 		}
-		if cols[i].Value != complete.Value {
+		if !cols[i].Equals(complete) {
 			return false
 		}
-		if boxs[i].Value != complete.Value {
+		if !boxs[i].Equals(complete) {
 			return false
 		}
 	}
 
-	return true
+	return puzzle.constraintsValid()
 }
 
 func (puzzle *Puzzle) GetCandidatesFor(cell *Cell) Candidates {
@@ -280,6 +297,7 @@ func (puzzle *Puzzle) IsValid() bool {
 	rows := make([]Candidates, size)
 	cols := make([]Candidates, size)
 	boxs := make([]Candidates, size)
+	houses := make([]Candidates, len(puzzle.Kind.Houses))
 
 	for i := range puzzle.Cells {
 		cell := &puzzle.Cells[i]
@@ -295,6 +313,18 @@ func (puzzle *Puzzle) IsValid() bool {
 				return false
 			}
 
+			for _, house := range cell.Houses {
+				if houses[house].Has(cell.Value) {
+					return false
+				}
+				houses[house].Set(cell.Value, true)
+			}
+			for _, link := range cell.Links {
+				if puzzle.Cells[link].Value == cell.Value {
+					return false
+				}
+			}
+
 			rows[cell.Row].Set(cell.Value, true)
 			cols[cell.Col].Set(cell.Value, true)
 			boxs[cell.Box].Set(cell.Value, true)
@@ -310,14 +340,84 @@ func (puzzle *Puzzle) IsValid() bool {
 			if candidates.Count == 0 {
 				return false
 			}
-			if (candidates.Value & cell.candidates.Value) != cell.candidates.Value {
+			if cell.candidates.Differences(candidates) {
 				return false
 			}
 		}
 	}
+	return puzzle.constraintsValid()
+}
+
+// Returns whether no values of the puzzle break a constraint of its kind.
+func (puzzle *Puzzle) constraintsValid() bool {
+	for _, constraint := range puzzle.Kind.Constraints {
+		if len(constraint.Validate(puzzle)) > 0 {
+			return false
+		}
+	}
 	return true
 }
 
+// A rule of the puzzle which some of its values break.
+type Violation struct {
+	// The constraint which is broken, or nil when a digit repeats in a row, column, box, house, or linked cells.
+	Constraint Constraint
+	// The cells whose values break the rule.
+	Cells []Position
+}
+
+// The rules the values of the puzzle break, which are digits repeated in a row, column, box, house, or linked
+// cells and the constraints of its kind which can't be completed. A puzzle without violations may still have no
+// solution.
+func (puzzle *Puzzle) Violations() []Violation {
+	size := puzzle.Kind.Size()
+	violations := make([]Violation, 0)
+
+	groups := make([][]*Cell, size*3+len(puzzle.Kind.Houses))
+	for i := range puzzle.Cells {
+		cell := &puzzle.Cells[i]
+		if cell.Empty() {
+			continue
+		}
+		groups[cell.Row] = append(groups[cell.Row], cell)
+		groups[size+cell.Col] = append(groups[size+cell.Col], cell)
+		groups[size*2+cell.Box] = append(groups[size*2+cell.Box], cell)
+		for _, house := range cell.Houses {
+			groups[size*3+house] = append(groups[size*3+house], cell)
+		}
+	}
+
+	for _, group := range groups {
+		repeated := make([][]Position, size+1)
+		for _, cell := range group {
+			repeated[cell.Value] = append(repeated[cell.Value], Position{cell.Col, cell.Row})
+		}
+		for _, cells := range repeated {
+			if len(cells) > 1 {
+				violations = append(violations, Violation{Cells: cells})
+			}
+		}
+	}
+
+	for i := range puzzle.Cells {
+		cell := &puzzle.Cells[i]
+		for _, link := range cell.Links {
+			other := &puzzle.Cells[link]
+			if link > cell.Id && cell.HasValue() && other.Value == cell.Value {
+				violations = append(violations, Violation{Cells: []Position{{cell.Col, cell.Row}, {other.Col, other.Row}}})
+			}
+		}
+	}
+
+	for _, constraint := range puzzle.Kind.Constraints {
+		if cells := constraint.Validate(puzzle); len(cells) > 0 {
+			violations = append(violations, Violation{Constraint: constraint, Cells: cells})
+		}
+	}
+
+	return violations
+}
+
 func (puzzle *Puzzle) String() string {
 	return puzzle.ToStateString(false, ".")
 }
@@ -362,11 +462,11 @@ func FromString(input string) *Puzzle {
 		cells = parts[1]
 	}
 	n := len(cells)
-	if n != boxWidth*boxWidth*boxHeight*boxHeight {
-		size := math.Round(math.Sqrt(float64(n)))
-		boxSize := int(math.Ceil(math.Sqrt(size)))
+	if kind := NewKind(boxWidth, boxHeight); n != kind.Area()*kind.DigitsSize() {
+		size := fromStringSize(n)
+		boxSize := int(math.Ceil(math.Sqrt(float64(size))))
 		boxWidth = boxSize
-		boxHeight = int(size) / boxSize
+		boxHeight = size / boxSize
 	}
 	kind := NewKind(boxWidth, boxHeight)
 	puzzle := New(kind)
@@ -381,19 +481,59 @@ func FromString(input string) *Puzzle {
 	return &puzzle
 }
 
+// Returns the number of digits of a puzzle whose cells were written with ToStateString in n characters.
+func fromStringSize(n int) int {
+	for digitsSize := 1; digitsSize <= n; digitsSize++ {
+		size := int(math.Round(math.Sqrt(float64(n / digitsSize))))
+		if size*size*digitsSize == n && NewKind(size, 1).DigitsSize() == digitsSize {
+			return size
+		}
+	}
+	return int(math.Round(math.Sqrt(float64(n))))
+}
+
+// The scale of the box width and height in an encoded string. A box width of 0 means the width and
+// height didn't fit and they are encoded with the large scale instead. A large box width of 0 means
+// the kind has irregular regions, which are encoded before the large box width and height.
+const encodedBoxScale = 32
+const encodedBoxScaleLarge = 1 << 16
+
 func (puzzle *Puzzle) EncodedString() string {
 	i := big.NewInt(0)
 	scale := big.NewInt(int64(puzzle.Kind.Digits() + 1))
-	boxSizeScale := big.NewInt(32)
 	for k := range puzzle.Cells {
 		cell := &puzzle.Cells[k]
 		i.Mul(i, scale)
 		i.Add(i, big.NewInt(int64(cell.Value)))
 	}
-	i.Mul(i, boxSizeScale)
-	i.Add(i, big.NewInt(int64(puzzle.Kind.BoxSize.Height)))
-	i.Mul(i, boxSizeScale)
-	i.Add(i, big.NewInt(int64(puzzle.Kind.BoxSize.Width)))
+	box := puzzle.Kind.BoxSize
+	irregular := puzzle.Kind.Irregular()
+	if irregular {
+		regionScale := big.NewInt(int64(puzzle.Kind.Size()))
+		for _, row := range puzzle.Kind.Regions {
+			for _, region := range row {
+				i.Mul(i, regionScale)
+				i.Add(i, big.NewInt(int64(region)))
+			}
+		}
+	}
+	if !irregular && box.Width < encodedBoxScale && box.Height < encodedBoxScale {
+		boxSizeScale := big.NewInt(encodedBoxScale)
+		i.Mul(i, boxSizeScale)
+		i.Add(i, big.NewInt(int64(box.Height)))
+		i.Mul(i, boxSizeScale)
+		i.Add(i, big.NewInt(int64(box.Width)))
+	} else {
+		boxSizeScale := big.NewInt(encodedBoxScaleLarge)
+		i.Mul(i, boxSizeScale)
+		i.Add(i, big.NewInt(int64(box.Height)))
+		i.Mul(i, boxSizeScale)
+		i.Add(i, big.NewInt(int64(box.Width)))
+		if irregular {
+			i.Mul(i, boxSizeScale)
+		}
+		i.Mul(i, big.NewInt(encodedBoxScale))
+	}
 	return base64.StdEncoding.EncodeToString(i.Bytes())
 }
 
@@ -403,12 +543,39 @@ func FromEncoded(input string) *Puzzle {
 		return nil
 	}
 	i := big.NewInt(0).SetBytes(bytes)
-	boxSizeScale := big.NewInt(32)
+	boxSizeScale := big.NewInt(encodedBoxScale)
 	boxWidth := int(big.NewInt(0).Mod(i, boxSizeScale).Int64())
 	i.Div(i, boxSizeScale)
+	irregular := false
+	if boxWidth == 0 {
+		boxSizeScale = big.NewInt(encodedBoxScaleLarge)
+		boxWidth = int(big.NewInt(0).Mod(i, boxSizeScale).Int64())
+		i.Div(i, boxSizeScale)
+		if boxWidth == 0 {
+			irregular = true
+			boxWidth = int(big.NewInt(0).Mod(i, boxSizeScale).Int64())
+			i.Div(i, boxSizeScale)
+		}
+	}
 	boxHeight := int(big.NewInt(0).Mod(i, boxSizeScale).Int64())
 	i.Div(i, boxSizeScale)
 	kind := NewKind(boxWidth, boxHeight)
+	if irregular {
+		size := kind.Size()
+		regionScale := big.NewInt(int64(size))
+		regions := make([][]int, size)
+		for row := size - 1; row >= 0; row-- {
+			regions[row] = make([]int, size)
+			for col := size - 1; col >= 0; col-- {
+				regions[row][col] = int(big.NewInt(0).Mod(i, regionScale).Int64())
+				i.Div(i, regionScale)
+			}
+		}
+		kind.Regions = regions
+		if !kind.ValidRegions() {
+			return nil
+		}
+	}
 	scale := big.NewInt(int64(kind.Digits() + 1))
 	puzzle := New(kind)
 	cellCount := len(puzzle.Cells) - 1
@@ -432,7 +599,7 @@ func (puzzle *Puzzle) UniqueId() string {
 }
 
 func (puzzle *Puzzle) HasUniqueSolution() bool {
-	return len(puzzle.GetSolutions(SolutionsLimit{MaxSolutions: 2})) == 1
+	return puzzle.CountSolutions(2) == 1
 }
 
 type SolutionsLimit struct {
@@ -472,6 +639,7 @@ func (puzzle *Puzzle) GetSolutions(limit SolutionsLimit) []*Solver {
 
 					newCell := newSolver.Puzzle.Get(minCell.Col, minCell.Row)
 					newSolver.LogStep(StepBruteForce)
+					newSolver.LogPattern([]*Cell{newCell}, candidate)
 					newSolver.LogBefore(newCell)
 					newSolver.SetCell(newCell, candidate)
 					newSolver.LogPlacement(newCell)
@@ -479,7 +647,9 @@ func (puzzle *Puzzle) GetSolutions(limit SolutionsLimit) []*Solver {
 					solvers.Offer(newSolver)
 				}
 			}
-		} else {
+		} else if solution.IsSolved() {
+			// steps which don't know the constraints, like forcing chains, can fill in a wrong guess before
+			// the constraints find it wrong
 			id := solution.UniqueId()
 			if !unique[id] {
 				solutions = append(solutions, solver)
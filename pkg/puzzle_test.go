@@ -171,6 +171,45 @@ func TestEncodedString(t *testing.T) {
 	}
 }
 
+func TestLargeKindStrings(t *testing.T) {
+	for _, kind := range []*Kind{NewKind(9, 9), NewKind(32, 3)} {
+		// A solution where each row is shifted from the one above.
+		w, h, size := kind.BoxSize.Width, kind.BoxSize.Height, kind.Size()
+		puzzle := New(kind)
+		for i := range puzzle.Cells {
+			cell := &puzzle.Cells[i]
+			if i%7 != 0 {
+				puzzle.SetCell(cell, ((cell.Row%h)*w+cell.Row/h+cell.Col)%size+1)
+			}
+		}
+
+		if !puzzle.IsValid() {
+			t.Errorf("Puzzle %dx%d is not valid.", w, h)
+			continue
+		}
+
+		parsed := FromString(puzzle.ToStateString(true, "."))
+		if parsed == nil {
+			t.Errorf("Puzzle %dx%d failed to parse from its state string.", w, h)
+		} else {
+			comparePuzzles(t, parsed, &puzzle)
+		}
+
+		decoded := FromEncoded(puzzle.EncodedString())
+		if decoded == nil {
+			t.Errorf("Puzzle %dx%d failed to parse from its encoded string.", w, h)
+		} else {
+			comparePuzzles(t, decoded, &puzzle)
+		}
+	}
+
+	empty := NewKind(10, 10).Empty()
+	withoutKind := FromString(empty.ToStateString(false, "."))
+	if withoutKind == nil || withoutKind.Kind.Size() != 100 {
+		t.Errorf("Puzzle 10x10 without a kind was not parsed as 100x100.")
+	}
+}
+
 func comparePuzzles(t *testing.T, actual *Puzzle, expected *Puzzle) bool {
 	if actual.Kind.BoxSize.Width != expected.Kind.BoxSize.Width {
 		t.Errorf("Expected puzzle box width is %d but was %d", expected.Kind.BoxSize.Width, actual.Kind.BoxSize.Width)
@@ -189,10 +228,101 @@ func comparePuzzles(t *testing.T, actual *Puzzle, expected *Puzzle) bool {
 			t.Errorf("Expected cell [%d,%d] is %d but was %d.", a.Col, a.Row, e.Value, a.Value)
 			valid = false
 		}
-		if a.candidates.Value != e.candidates.Value {
+		if !a.candidates.Equals(e.candidates) {
 			t.Errorf("Expected candidates [%d,%d] is %v but was %v.", a.Col, a.Row, e.Candidates(), a.Candidates())
 			valid = false
 		}
 	}
 	return valid
 }
+
+var validateSolution = [][]int{
+	{5, 3, 4, 6, 7, 8, 9, 1, 2},
+	{6, 7, 2, 1, 9, 5, 3, 4, 8},
+	{1, 9, 8, 3, 4, 2, 5, 6, 7},
+	{8, 5, 9, 7, 6, 1, 4, 2, 3},
+	{4, 2, 6, 8, 5, 3, 7, 9, 1},
+	{7, 1, 3, 9, 2, 4, 8, 5, 6},
+	{9, 6, 1, 5, 3, 7, 2, 8, 4},
+	{2, 8, 7, 4, 1, 9, 6, 3, 5},
+	{3, 4, 5, 2, 8, 6, 1, 7, 9},
+}
+
+func TestConstraintValidate(t *testing.T) {
+	full := Classic.Create(validateSolution)
+	partial := Classic.Create([][]int{
+		{5, 0, 0, 0, 0, 0, 0, 0, 0},
+	})
+	pair := []Position{{0, 0}, {1, 0}}
+
+	tests := []struct {
+		name       string
+		puzzle     Puzzle
+		constraint Constraint
+		expected   string
+	}{
+		{"cage", full, &ConstraintCage{Sum: 8, Cells: pair}, "[]"},
+		{"cage sum", full, &ConstraintCage{Sum: 9, Cells: pair}, "[{0 0} {1 0}]"},
+		{"cage partial", partial, &ConstraintCage{Sum: 8, Cells: pair}, "[]"},
+		{"cage partial sum", partial, &ConstraintCage{Sum: 5, Cells: pair}, "[{0 0}]"},
+		{"sum", full, &ConstraintSum{Sum: SumConstant(8), Cells: &pair}, "[]"},
+		{"sum wrong", full, &ConstraintSum{Sum: SumConstant(9), Cells: &pair}, "[{0 0} {1 0}]"},
+		{"thermo", full, &ConstraintThermo{Path: []Position{{1, 0}, {2, 0}, {3, 0}}}, "[]"},
+		{"thermo backwards", full, &ConstraintThermo{Path: []Position{{3, 0}, {2, 0}, {1, 0}}}, "[{1 0} {2 0} {3 0}]"},
+		{"kropki", full, &ConstraintKropki{Dots: []EdgeMarker{{Position{1, 0}, Position{2, 0}, MarkerWhiteDot}}}, "[]"},
+		{"kropki wrong", full, &ConstraintKropki{Dots: []EdgeMarker{{Position{0, 0}, Position{1, 0}, MarkerWhiteDot}}}, "[{0 0} {1 0}]"},
+		{"even", full, &ConstraintParity{Cells: []Position{{0, 0}, {2, 0}}}, "[{0 0}]"},
+	}
+
+	for _, test := range tests {
+		before := test.puzzle.Clone()
+		actual := fmt.Sprintf("%v", test.constraint.Validate(&test.puzzle))
+		if actual != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, actual)
+		}
+		for i := range before.Cells {
+			if before.Cells[i].Value != test.puzzle.Cells[i].Value || before.Cells[i].candidates.Count != test.puzzle.Cells[i].candidates.Count {
+				t.Errorf("%s: validating changed r%dc%d", test.name, before.Cells[i].Row+1, before.Cells[i].Col+1)
+				break
+			}
+		}
+	}
+}
+
+func TestViolations(t *testing.T) {
+	kind := Classic.Clone()
+	cage := &ConstraintCage{Sum: 8, Cells: []Position{{0, 0}, {1, 0}}}
+	kind.Constraints = []Constraint{cage}
+
+	puzzle := kind.Create(validateSolution)
+	if !puzzle.IsSolved() || !puzzle.IsValid() || len(puzzle.Violations()) != 0 {
+		t.Fatalf("Expected the solution to be solved without violations, got %v", puzzle.Violations())
+	}
+
+	// A 3 in the top left corner repeats in its row, column, and box and breaks the cage.
+	puzzle.Get(0, 0).Value = 3
+	violations := puzzle.Violations()
+	if puzzle.IsSolved() || puzzle.IsValid() {
+		t.Errorf("Expected the puzzle to not be solved or valid")
+	}
+	if len(violations) != 4 {
+		t.Fatalf("Expected 4 violations, got %v", violations)
+	}
+	for _, violation := range violations[:3] {
+		if violation.Constraint != nil || len(violation.Cells) != 2 {
+			t.Errorf("Expected a repeated 3, got %v", violation)
+		}
+	}
+	if violations[3].Constraint != cage || fmt.Sprintf("%v", violations[3].Cells) != "[{0 0} {1 0}]" {
+		t.Errorf("Expected the cage to be broken, got %v", violations[3])
+	}
+
+	// A cage which sums to 8 with the 5 is still valid when the cells are empty.
+	kind.Constraints = []Constraint{cage, &ConstraintCage{Sum: 4, Cells: []Position{{2, 0}, {3, 0}}}}
+	partial := kind.Create([][]int{
+		{5, 0, 0, 0, 0, 0, 0, 0, 0},
+	})
+	if !partial.IsValid() || len(partial.Violations()) != 0 {
+		t.Errorf("Expected the partial puzzle to be valid, got %v", partial.Violations())
+	}
+}
@@ -172,6 +172,71 @@ func DoSolveFormatSingle(r JsonRequest[None, PuzzleParams, SolveFormatSingleQuer
 	return nil, http.StatusOK
 }
 
+// =====================================================
+// GET /hint/{format}/{id}
+// =====================================================
+
+// The hint when no logical step can be made, like when the puzzle is solved or can only be guessed, which
+// is returned without a technique.
+const noHintText = "No logical step can be made"
+
+type HintFormatSingleJson struct {
+	BoxWidth  int              `json:"boxWidth"`
+	BoxHeight int              `json:"boxHeight"`
+	Puzzle    [][]int          `json:"puzzle"`
+	Level     HintLevel        `json:"level"`
+	Hint      string           `json:"hint"`
+	Technique string           `json:"technique"`
+	Region    string           `json:"region,omitempty"`
+	Deduction *PuzzleDeduction `json:"deduction,omitempty"`
+}
+
+type HintFormatSingleQuery struct {
+	Level HintLevel `json:"level"`
+}
+
+func DoHintFormatSingle(r JsonRequest[None, PuzzleParams, HintFormatSingleQuery]) (any, int) {
+	puzzle, puzzleExists := r.Validate["Puzzle"].(*su.Puzzle)
+
+	if !puzzleExists {
+		return nil, http.StatusNotFound
+	}
+
+	hint := puzzle.Hint(su.SolveLimit{})
+	level := r.Query.Level.toDomain()
+	text := noHintText
+	if hint != nil {
+		text = hint.Text(level)
+	}
+
+	switch r.Params.Format {
+	case FormatJson:
+		rsp := HintFormatSingleJson{}
+		rsp.Puzzle = puzzle.GetAll()
+		rsp.BoxWidth = puzzle.Kind.BoxSize.Width
+		rsp.BoxHeight = puzzle.Kind.BoxSize.Height
+		rsp.Level = r.Query.Level
+		rsp.Hint = text
+		if hint != nil {
+			rsp.Technique = hint.Technique()
+			if level >= su.HintRegion {
+				rsp.Region = hint.Where()
+			}
+			if level >= su.HintDeduction {
+				d := toPuzzleDeduction(&hint.Deduction)
+				rsp.Deduction = &d
+			}
+		}
+
+		return rsp, http.StatusOK
+
+	case FormatText:
+		return r.SendText(text+"\n", http.StatusOK)
+	}
+
+	return nil, http.StatusOK
+}
+
 // =====================================================
 // GET /generate/{format}
 // =====================================================
@@ -585,6 +650,7 @@ func toPuzzleData(puzzle *su.Puzzle, candidates bool) PuzzleData {
 	pd.Puzzle = puzzle
 	pd.BoxWidth = puzzle.Kind.BoxSize.Width
 	pd.BoxHeight = puzzle.Kind.BoxSize.Height
+	pd.Regions = puzzle.Kind.Regions
 	pd.Values = puzzle.GetAll()
 	pd.Encoded = puzzle.EncodedString()
 	pd.State = puzzle.String()
@@ -609,6 +675,7 @@ func toPuzzleSteps(solver *su.Solver, candidates bool) []PuzzleSolveStep {
 func toPuzzleStep(log su.SolverLog, state bool, candidates bool) PuzzleSolveStep {
 	step := PuzzleSolveStep{
 		Technique:         log.Step.Technique,
+		Explanation:       log.Explanation(),
 		Index:             log.Index,
 		Batch:             log.Batch,
 		Cost:              log.Cost,
@@ -629,9 +696,44 @@ func toPuzzleStep(log su.SolverLog, state bool, candidates bool) PuzzleSolveStep
 		step.State = &s
 	}
 
+	if log.Deduction != nil {
+		d := toPuzzleDeduction(log.Deduction)
+		step.Deduction = &d
+	}
+
 	return step
 }
 
+func toPuzzleDeduction(deduction *su.SolverDeduction) PuzzleDeduction {
+	d := PuzzleDeduction{
+		Cells:        make([]PuzzleCell, 0, len(deduction.Cells)),
+		Candidates:   deduction.Candidates,
+		Houses:       make([]PuzzleHouse, 0, len(deduction.Houses)),
+		Eliminations: toPuzzleCandidates(deduction.Eliminations),
+		Placements:   toPuzzleCandidates(deduction.Placements),
+	}
+	if d.Candidates == nil {
+		d.Candidates = []int{}
+	}
+	for _, cell := range deduction.Cells {
+		d.Cells = append(d.Cells, PuzzleCell{Row: cell.Row, Col: cell.Col})
+	}
+	for _, house := range deduction.Houses {
+		d.Houses = append(d.Houses, PuzzleHouse{Group: puzzleHouseGroups[house.Group], Index: house.Index})
+	}
+	return d
+}
+
+var puzzleHouseGroups = map[su.Group]string{su.GroupRow: "row", su.GroupCol: "col", su.GroupBox: "box", su.GroupHouse: "house"}
+
+func toPuzzleCandidates(candidates []su.SolverCandidate) []PuzzleCandidate {
+	pc := make([]PuzzleCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		pc = append(pc, PuzzleCandidate{Row: c.Row, Col: c.Col, Value: c.Value})
+	}
+	return pc
+}
+
 func writeGeneratedKind(sb *strings.Builder, kind GenerateKind, generated []GeneratedPuzzle) {
 	sb.WriteString(fmt.Sprintf("Seed: %d\n", kind.Seed.Value))
 
@@ -112,10 +112,16 @@ func (r *Reflector) consumeInterface(t reflect.Type) ReflectIterator {
 	}
 }
 
+// Elements are consumed through their pointer type like fields. When the pointer type can be consumed the
+// element type has no consume iterator of its own, which is the case for value receivers too.
 func (r *Reflector) consumeSlice(t reflect.Type) ReflectIterator {
-	iters := r.getIterators(t)
+	iters := r.getIterators(reflect.PointerTo(t))
 	if len(iters) == 0 {
-		return nil
+		iters = r.getIterators(t)
+
+		if len(iters) == 0 {
+			return nil
+		}
 	}
 
 	return func(value reflect.Value, consumer ReflectConsumer) {
@@ -93,3 +93,24 @@ func TestReflector(t *testing.T) {
 	fmt.Println(pr1.Child.Child2.Changes)
 	fmt.Println(pr1.ChildPtr.Changes)
 }
+
+type PrintList struct {
+	Values   []PrintChild
+	Pointers []PrintChildPtr
+}
+
+func TestReflectorSlices(t *testing.T) {
+	r := NewReflector(CanPrint)
+
+	p := &Printer{}
+	list := &PrintList{Values: []PrintChild{{}}, Pointers: []PrintChildPtr{{}}}
+	r.Consume(list, p)
+
+	messages := fmt.Sprint(p.Messages)
+	if messages != "[PrintChild PrintChild2 PrintChildPtr]" {
+		t.Errorf("Expected every slice element to be consumed, got %s", messages)
+	}
+	if list.Pointers[0].Changes != 3 {
+		t.Errorf("Expected slice elements to be consumed through a pointer")
+	}
+}
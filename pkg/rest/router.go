@@ -19,6 +19,8 @@ func GetRouter() chi.Router {
 	r.Get("/solve/{format}/{id}", JsonRoute(DoSolveFormatSingle))
 	r.Post("/solve/{format}", JsonRoute(DoSolveFormatComplex))
 
+	r.Get("/hint/{format}/{id}", JsonRoute(DoHintFormatSingle))
+
 	r.Get("/generate/{format}", JsonRoute(DoGenerateFormatSingle))
 	r.Post("/generate/{format}", JsonRoute(DoGenerateFormatMany))
 
@@ -41,6 +41,25 @@ func (d *PuzzleDimension) Validate(v Validator) {
 	initAndValidate(d, PuzzleDimension(0), PuzzleDimension(2), PuzzleDimension(64), v)
 }
 
+// The box of each cell by row and then column for puzzles with irregular (jigsaw) boxes.
+type Regions [][]int
+
+func (r Regions) Validate(v Validator) {
+	if len(r) > 0 && su.NewJigsawKind(r) == nil {
+		v.Add("must be square and have one cell in each box for every digit")
+	}
+}
+
+// The jigsaw kind of the regions when given and valid, otherwise the kind with the box size.
+func (r Regions) toKind(boxWidth int, boxHeight int) *su.Kind {
+	if len(r) > 0 {
+		if kind := su.NewJigsawKind(r); kind != nil {
+			return kind
+		}
+	}
+	return su.NewKind(boxWidth, boxHeight)
+}
+
 type Position struct {
 	Col Index `json:"col"`
 	Row Index `json:"row"`
@@ -202,16 +221,283 @@ func (p ConstraintDivisible) toDomain() su.Constraint {
 	}
 }
 
+type ConstraintArrow struct {
+	Circle Position   `json:"circle"`
+	Arrow  []Position `json:"arrow"`
+}
+
+func (p ConstraintArrow) toDomain() su.Constraint {
+	return &su.ConstraintArrow{
+		Circle: p.Circle.toDomain(),
+		Arrow:  toDomainSlice[su.Position](p.Arrow),
+	}
+}
+
+func (c ConstraintArrow) Validate(v Validator) {
+	if len(c.Arrow) == 0 {
+		v.Add("No arrow cells specified.")
+	}
+}
+
+type Direction struct {
+	Col DirectionInt `json:"col"`
+	Row DirectionInt `json:"row"`
+}
+
+func (p Direction) toDomain() su.Position {
+	return su.Position{Col: int(p.Col), Row: int(p.Row)}
+}
+
+type ConstraintLittleKiller struct {
+	Sum       int       `json:"sum"`
+	Start     Position  `json:"start"`
+	Direction Direction `json:"direction"`
+}
+
+func (p ConstraintLittleKiller) toDomain() su.Constraint {
+	return &su.ConstraintLittleKiller{
+		Sum:       p.Sum,
+		Start:     p.Start.toDomain(),
+		Direction: p.Direction.toDomain(),
+	}
+}
+
+func (c ConstraintLittleKiller) Validate(v Validator) {
+	if c.Sum <= 0 {
+		v.Add("The sum %d is not a valid little killer sum.", c.Sum)
+	}
+	if c.Direction.Col == 0 || c.Direction.Row == 0 {
+		v.Add("The direction must be diagonal.")
+	}
+}
+
+type ConstraintSandwich struct {
+	Sum    int        `json:"sum"`
+	Column Trim[bool] `json:"column"`
+	Index  Index      `json:"index"`
+}
+
+func (p ConstraintSandwich) toDomain() su.Constraint {
+	group := su.GroupRow
+	if p.Column.Value {
+		group = su.GroupCol
+	}
+	return &su.ConstraintSandwich{
+		Sum:   p.Sum,
+		Group: group,
+		Index: int(p.Index),
+	}
+}
+
+func (c ConstraintSandwich) Validate(v Validator) {
+	if c.Sum < 0 {
+		v.Add("The sum %d is not a valid sandwich sum.", c.Sum)
+	}
+}
+
+type ConstraintThermo struct {
+	Path []Position `json:"path"`
+	Slow Trim[bool] `json:"slow"`
+}
+
+func (p ConstraintThermo) toDomain() su.Constraint {
+	return &su.ConstraintThermo{
+		Path: toDomainSlice[su.Position](p.Path),
+		Slow: p.Slow.Value,
+	}
+}
+
+func (c ConstraintThermo) Validate(v Validator) {
+	validateLinePath(c.Path, 2, v)
+}
+
+type ConstraintRenban struct {
+	Path []Position `json:"path"`
+}
+
+func (p ConstraintRenban) toDomain() su.Constraint {
+	return &su.ConstraintRenban{
+		Path: toDomainSlice[su.Position](p.Path),
+	}
+}
+
+func (c ConstraintRenban) Validate(v Validator) {
+	validateLinePath(c.Path, 2, v)
+}
+
+type ConstraintWhispers struct {
+	Path       []Position `json:"path"`
+	Difference int        `json:"difference"`
+}
+
+func (p ConstraintWhispers) toDomain() su.Constraint {
+	return &su.ConstraintWhispers{
+		Path:       toDomainSlice[su.Position](p.Path),
+		Difference: p.Difference,
+	}
+}
+
+func (c ConstraintWhispers) Validate(v Validator) {
+	validateLinePath(c.Path, 2, v)
+	if c.Difference <= 0 {
+		v.Add("The difference %d is not a valid whispers difference.", c.Difference)
+	}
+}
+
+type ConstraintPalindrome struct {
+	Path []Position `json:"path"`
+}
+
+func (p ConstraintPalindrome) toDomain() su.Constraint {
+	return &su.ConstraintPalindrome{
+		Path: toDomainSlice[su.Position](p.Path),
+	}
+}
+
+func (c ConstraintPalindrome) Validate(v Validator) {
+	validateLinePath(c.Path, 2, v)
+}
+
+type ConstraintBetween struct {
+	Path []Position `json:"path"`
+}
+
+func (p ConstraintBetween) toDomain() su.Constraint {
+	return &su.ConstraintBetween{
+		Path: toDomainSlice[su.Position](p.Path),
+	}
+}
+
+func (c ConstraintBetween) Validate(v Validator) {
+	validateLinePath(c.Path, 3, v)
+}
+
+type ConstraintRegionSum struct {
+	Path []Position `json:"path"`
+}
+
+func (p ConstraintRegionSum) toDomain() su.Constraint {
+	return &su.ConstraintRegionSum{
+		Path: toDomainSlice[su.Position](p.Path),
+	}
+}
+
+func (c ConstraintRegionSum) Validate(v Validator) {
+	validateLinePath(c.Path, 2, v)
+}
+
+func validateLinePath(path []Position, min int, v Validator) {
+	if len(path) < min {
+		v.Add("A line needs at least %d cells, got %d.", min, len(path))
+	}
+}
+
+type EdgeMarker struct {
+	First  Position `json:"first"`
+	Second Position `json:"second"`
+}
+
+func (m EdgeMarker) toDomain(markerType su.MarkerType) su.EdgeMarker {
+	return su.EdgeMarker{
+		First:  m.First.toDomain(),
+		Second: m.Second.toDomain(),
+		Type:   markerType,
+	}
+}
+
+func (m EdgeMarker) Validate(v Validator) {
+	cols := su.AbsInt(int(m.First.Col) - int(m.Second.Col))
+	rows := su.AbsInt(int(m.First.Row) - int(m.Second.Row))
+	if cols+rows != 1 {
+		v.Add("A marker must be between orthogonally adjacent cells.")
+	}
+}
+
+func toDomainMarkers(markers []EdgeMarker, markerType su.MarkerType, out []su.EdgeMarker) []su.EdgeMarker {
+	for _, marker := range markers {
+		out = append(out, marker.toDomain(markerType))
+	}
+	return out
+}
+
+type ConstraintKropki struct {
+	White    []EdgeMarker `json:"white"`
+	Black    []EdgeMarker `json:"black"`
+	Negative Trim[bool]   `json:"negative"`
+}
+
+func (p ConstraintKropki) toDomain() su.Constraint {
+	dots := toDomainMarkers(p.White, su.MarkerWhiteDot, nil)
+	dots = toDomainMarkers(p.Black, su.MarkerBlackDot, dots)
+	return &su.ConstraintKropki{
+		Dots:     dots,
+		Negative: p.Negative.Value,
+	}
+}
+
+type ConstraintXV struct {
+	X        []EdgeMarker `json:"x"`
+	V        []EdgeMarker `json:"v"`
+	Negative Trim[bool]   `json:"negative"`
+}
+
+func (p ConstraintXV) toDomain() su.Constraint {
+	markers := toDomainMarkers(p.X, su.MarkerX, nil)
+	markers = toDomainMarkers(p.V, su.MarkerV, markers)
+	return &su.ConstraintXV{
+		Markers:  markers,
+		Negative: p.Negative.Value,
+	}
+}
+
 type Constraints struct {
-	SumValues   []ConstraintSumValue   `json:"sumValues"`
-	SumCell     []ConstraintSumCell    `json:"sumCell"`
-	SumCells    []ConstraintSumCells   `json:"sumCells"`
-	Uniques     []ConstraintUnique     `json:"uniques"`
-	Orders      []ConstraintOrder      `json:"orders"`
-	Magics      []ConstraintMagic      `json:"magics"`
-	ScalePairs  []ConstraintScalePair  `json:"scalePairs"`
-	Differences []ConstraintDifference `json:"differences"`
-	Divisibles  []ConstraintDivisible  `json:"divisibles"`
+	SumValues     []ConstraintSumValue     `json:"sumValues"`
+	SumCell       []ConstraintSumCell      `json:"sumCell"`
+	SumCells      []ConstraintSumCells     `json:"sumCells"`
+	Uniques       []ConstraintUnique       `json:"uniques"`
+	Orders        []ConstraintOrder        `json:"orders"`
+	Magics        []ConstraintMagic        `json:"magics"`
+	ScalePairs    []ConstraintScalePair    `json:"scalePairs"`
+	Differences   []ConstraintDifference   `json:"differences"`
+	Divisibles    []ConstraintDivisible    `json:"divisibles"`
+	Arrows        []ConstraintArrow        `json:"arrows"`
+	LittleKillers []ConstraintLittleKiller `json:"littleKillers"`
+	Sandwiches    []ConstraintSandwich     `json:"sandwiches"`
+	Thermos       []ConstraintThermo       `json:"thermos"`
+	Renbans       []ConstraintRenban       `json:"renbans"`
+	Whispers      []ConstraintWhispers     `json:"whispers"`
+	Palindromes   []ConstraintPalindrome   `json:"palindromes"`
+	Betweens      []ConstraintBetween      `json:"betweens"`
+	RegionSums    []ConstraintRegionSum    `json:"regionSums"`
+	Kropki        []ConstraintKropki       `json:"kropki"`
+	XV            []ConstraintXV           `json:"xv"`
+	// The names of rules without cells, like antiKnight or disjointGroups.
+	Rules []string   `json:"rules"`
+	Even  []Position `json:"even"`
+	Odd   []Position `json:"odd"`
+}
+
+func (c Constraints) Validate(v Validator) {
+	for _, name := range c.Rules {
+		if su.RuleNamed(name) == nil {
+			v.Add("%q is not one of antiKnight, antiKing, nonConsecutive, or disjointGroups.", name)
+		}
+	}
+}
+
+// Returns the kind with the constraints and rules added.
+func (c Constraints) toKind(kind *su.Kind) *su.Kind {
+	kind.Constraints = c.toDomain()
+	rules := make([]*su.Rule, 0, len(c.Rules)+1)
+	for _, name := range c.Rules {
+		if rule := su.RuleNamed(name); rule != nil {
+			rules = append(rules, rule)
+		}
+	}
+	if len(c.Even) > 0 || len(c.Odd) > 0 {
+		rules = append(rules, su.RuleEvenOdd(toDomainSlice[su.Position](c.Even), toDomainSlice[su.Position](c.Odd)))
+	}
+	return kind.WithRules(rules...)
 }
 
 func (c Constraints) toDomain() []su.Constraint {
@@ -225,6 +511,17 @@ func (c Constraints) toDomain() []su.Constraint {
 	d = append(d, toDomainSlice[su.Constraint](c.ScalePairs)...)
 	d = append(d, toDomainSlice[su.Constraint](c.Differences)...)
 	d = append(d, toDomainSlice[su.Constraint](c.Divisibles)...)
+	d = append(d, toDomainSlice[su.Constraint](c.Arrows)...)
+	d = append(d, toDomainSlice[su.Constraint](c.LittleKillers)...)
+	d = append(d, toDomainSlice[su.Constraint](c.Sandwiches)...)
+	d = append(d, toDomainSlice[su.Constraint](c.Thermos)...)
+	d = append(d, toDomainSlice[su.Constraint](c.Renbans)...)
+	d = append(d, toDomainSlice[su.Constraint](c.Whispers)...)
+	d = append(d, toDomainSlice[su.Constraint](c.Palindromes)...)
+	d = append(d, toDomainSlice[su.Constraint](c.Betweens)...)
+	d = append(d, toDomainSlice[su.Constraint](c.RegionSums)...)
+	d = append(d, toDomainSlice[su.Constraint](c.Kropki)...)
+	d = append(d, toDomainSlice[su.Constraint](c.XV)...)
 	return d
 }
 
@@ -265,27 +562,32 @@ var DifficultyMap = map[string]su.ClearLimit{
 }
 
 type GenerateKind struct {
-	Count          Trim[GenerateCount]   `json:"count"`
-	Seed           Trim[GenerateSeed]    `json:"seed"`
-	Difficulty     string                `json:"difficulty"`
-	MinCost        Trim[int]             `json:"minCost"`
-	MaxCost        Trim[int]             `json:"maxCost"`
-	MaxPlacements  Trim[int]             `json:"maxPlacements"`
-	MaxSteps       Trim[int]             `json:"maxSteps"`
-	MaxBatches     Trim[int]             `json:"maxBatches"`
-	Symmetric      Trim[bool]            `json:"symmetric"`
-	BoxWidth       Trim[PuzzleDimension] `json:"boxWidth"`
-	BoxHeight      Trim[PuzzleDimension] `json:"boxHeight"`
-	Techniques     map[string]Trim[int]  `json:"techniques"`
-	Constraints    Constraints           `json:"constraints"`
-	Candidates     Trim[bool]            `json:"candidates"`
-	State          Trim[bool]            `json:"state"`
-	Solutions      Trim[bool]            `json:"solutions"`
-	SolutionSteps  Trim[bool]            `json:"solutionSteps"`
-	SolutionStates Trim[bool]            `json:"solutionStates"`
-	TryCount       Trim[int]             `json:"tryCount"`
-	TryAttempts    Trim[int]             `json:"tryAttempts"`
-	TryClears      Trim[int]             `json:"tryClears"`
+	Count            Trim[GenerateCount]   `json:"count"`
+	Seed             Trim[GenerateSeed]    `json:"seed"`
+	Difficulty       string                `json:"difficulty"`
+	MinCost          Trim[int]             `json:"minCost"`
+	MaxCost          Trim[int]             `json:"maxCost"`
+	MaxPlacements    Trim[int]             `json:"maxPlacements"`
+	MaxSteps         Trim[int]             `json:"maxSteps"`
+	MaxBatches       Trim[int]             `json:"maxBatches"`
+	MaxChainLength   Trim[int]             `json:"maxChainLength"`
+	MaxForcingDepth  Trim[int]             `json:"maxForcingDepth"`
+	MaxForcingNodes  Trim[int]             `json:"maxForcingNodes"`
+	MaxTemplateNodes Trim[int]             `json:"maxTemplateNodes"`
+	Symmetric        Trim[bool]            `json:"symmetric"`
+	BoxWidth         Trim[PuzzleDimension] `json:"boxWidth"`
+	BoxHeight        Trim[PuzzleDimension] `json:"boxHeight"`
+	Regions          Regions               `json:"regions"`
+	Techniques       map[string]Trim[int]  `json:"techniques"`
+	Constraints      Constraints           `json:"constraints"`
+	Candidates       Trim[bool]            `json:"candidates"`
+	State            Trim[bool]            `json:"state"`
+	Solutions        Trim[bool]            `json:"solutions"`
+	SolutionSteps    Trim[bool]            `json:"solutionSteps"`
+	SolutionStates   Trim[bool]            `json:"solutionStates"`
+	TryCount         Trim[int]             `json:"tryCount"`
+	TryAttempts      Trim[int]             `json:"tryAttempts"`
+	TryClears        Trim[int]             `json:"tryClears"`
 }
 
 func (r *GenerateKind) Validate(v Validator) {
@@ -295,14 +597,13 @@ func (r *GenerateKind) Validate(v Validator) {
 	initAndValidate(&r.TryAttempts.Value, 256, 1, 2048, v.Field("tryAttempts"))
 	initAndValidate(&r.TryClears.Value, 256, 1, 2048, v.Field("tryClears"))
 
-	v.Context["Kind"] = su.NewKind(int(r.BoxWidth.Value), int(r.BoxHeight.Value))
+	v.Context["Kind"] = r.Regions.toKind(int(r.BoxWidth.Value), int(r.BoxHeight.Value))
 }
 
 func (r GenerateKind) toDomain() (*su.Kind, su.ClearLimit) {
 	boxWidth := su.Max(1, int(r.BoxWidth.Value))
 	boxHeight := su.Max(1, int(r.BoxHeight.Value))
-	kind := su.NewKind(boxWidth, boxHeight)
-	kind.Constraints = r.Constraints.toDomain()
+	kind := r.Constraints.toKind(r.Regions.toKind(boxWidth, boxHeight))
 	limitScale := float32(kind.Area()) / 81.0
 
 	clear := su.ClearLimit{}
@@ -336,6 +637,19 @@ func (r GenerateKind) toDomain() (*su.Kind, su.ClearLimit) {
 	applyValue(r.MaxSteps.Value, &clear.SolveLimit.MaxLogs)
 	applyValue(r.MaxPlacements.Value, &clear.SolveLimit.MaxPlacements)
 
+	if r.MaxChainLength.Value > 0 {
+		clear.SolveLimit.MaxChainLength = r.MaxChainLength.Value
+	}
+	if r.MaxForcingDepth.Value > 0 {
+		clear.SolveLimit.MaxForcingDepth = r.MaxForcingDepth.Value
+	}
+	if r.MaxForcingNodes.Value > 0 {
+		clear.SolveLimit.MaxForcingNodes = r.MaxForcingNodes.Value
+	}
+	if r.MaxTemplateNodes.Value > 0 {
+		clear.SolveLimit.MaxTemplateNodes = r.MaxTemplateNodes.Value
+	}
+
 	return kind, clear
 }
 
@@ -372,6 +686,36 @@ func (f *FormatParam) Validate(v Validator) {
 	}
 }
 
+type HintLevel string
+
+var (
+	HintLevelTechnique HintLevel = "technique"
+	HintLevelRegion    HintLevel = "region"
+	HintLevelDeduction HintLevel = "deduction"
+)
+
+func (l *HintLevel) Validate(v Validator) {
+	level := strings.ToLower(string(*l))
+	switch level {
+	case "":
+		*l = HintLevelTechnique
+	case "technique", "region", "deduction":
+		*l = HintLevel(level)
+	default:
+		v.Add("Invalid hint level: %s; Only technique, region, or deduction are supported.", level)
+	}
+}
+
+func (l HintLevel) toDomain() su.HintLevel {
+	switch l {
+	case HintLevelRegion:
+		return su.HintRegion
+	case HintLevelDeduction:
+		return su.HintDeduction
+	}
+	return su.HintTechnique
+}
+
 type IDParam struct {
 	ID string `json:"id"`
 }
@@ -401,6 +745,7 @@ type GeneratedPuzzle struct {
 type PuzzleData struct {
 	BoxWidth   int       `json:"boxWidth,omitempty"`
 	BoxHeight  int       `json:"boxHeight,omitempty"`
+	Regions    [][]int   `json:"regions,omitempty"`
 	Values     [][]int   `json:"values"`
 	Candidates [][][]int `json:"candidates,omitempty"`
 	Encoded    string    `json:"encoded,omitempty"`
@@ -410,24 +755,51 @@ type PuzzleData struct {
 }
 
 type PuzzleSolveStep struct {
-	Technique         string      `json:"technique"`
-	Index             int         `json:"index"`
-	Batch             int         `json:"batch"`
-	Cost              int         `json:"cost"`
-	Placement         bool        `json:"placement"`
-	Row               int         `json:"row"`
-	Col               int         `json:"col"`
-	Before            int         `json:"before"`
-	BeforeCandidates  []int       `json:"beforeCandidates"`
-	After             int         `json:"after"`
-	AfterCandidates   []int       `json:"afterCandidates"`
-	RunningCost       int         `json:"runningCost"`
-	RunningPlacements int         `json:"runningPlacements"`
-	State             *PuzzleData `json:"state,omitempty"`
+	Technique         string           `json:"technique"`
+	Explanation       string           `json:"explanation"`
+	Index             int              `json:"index"`
+	Batch             int              `json:"batch"`
+	Cost              int              `json:"cost"`
+	Placement         bool             `json:"placement"`
+	Row               int              `json:"row"`
+	Col               int              `json:"col"`
+	Before            int              `json:"before"`
+	BeforeCandidates  []int            `json:"beforeCandidates"`
+	After             int              `json:"after"`
+	AfterCandidates   []int            `json:"afterCandidates"`
+	RunningCost       int              `json:"runningCost"`
+	RunningPlacements int              `json:"runningPlacements"`
+	State             *PuzzleData      `json:"state,omitempty"`
+	Deduction         *PuzzleDeduction `json:"deduction,omitempty"`
 
 	Log su.SolverLog `json:"-"`
 }
 
+type PuzzleDeduction struct {
+	Cells        []PuzzleCell      `json:"cells"`
+	Candidates   []int             `json:"candidates"`
+	Houses       []PuzzleHouse     `json:"houses"`
+	Eliminations []PuzzleCandidate `json:"eliminations"`
+	Placements   []PuzzleCandidate `json:"placements"`
+}
+
+// A house of a deduction: a row, column, or box, or an extra house of the puzzle's kind by index.
+type PuzzleHouse struct {
+	Group string `json:"group"`
+	Index int    `json:"index"`
+}
+
+type PuzzleCell struct {
+	Row int `json:"row"`
+	Col int `json:"col"`
+}
+
+type PuzzleCandidate struct {
+	Row   int `json:"row"`
+	Col   int `json:"col"`
+	Value int `json:"value"`
+}
+
 type OptionsPDF struct {
 	PuzzlesWide Trim[int] `json:"puzzlesWide"`
 	PuzzlesHigh Trim[int] `json:"puzzlesHigh"`
@@ -439,33 +811,37 @@ func (o *OptionsPDF) Validate(v Validator) {
 }
 
 type SolveKind struct {
-	BoxWidth       PuzzleDimension `json:"boxWidth"`
-	BoxHeight      PuzzleDimension `json:"boxHeight"`
-	Puzzle         [][]int         `json:"puzzle"`
-	MinCost        int             `json:"minCost"`
-	MaxCost        int             `json:"maxCost"`
-	MaxPlacements  int             `json:"maxPlacements"`
-	MaxSteps       int             `json:"maxSteps"`
-	MaxBatches     int             `json:"maxBatches"`
-	Techniques     map[string]int  `json:"techniques"`
-	Constraints    Constraints     `json:"constraints"`
-	Candidates     bool            `json:"candidates"`
-	SolutionSteps  bool            `json:"solutionSteps"`
-	SolutionStates bool            `json:"solutionStates"`
+	BoxWidth         PuzzleDimension `json:"boxWidth"`
+	BoxHeight        PuzzleDimension `json:"boxHeight"`
+	Regions          Regions         `json:"regions"`
+	Puzzle           [][]int         `json:"puzzle"`
+	MinCost          int             `json:"minCost"`
+	MaxCost          int             `json:"maxCost"`
+	MaxPlacements    int             `json:"maxPlacements"`
+	MaxSteps         int             `json:"maxSteps"`
+	MaxBatches       int             `json:"maxBatches"`
+	MaxChainLength   int             `json:"maxChainLength"`
+	MaxForcingDepth  int             `json:"maxForcingDepth"`
+	MaxForcingNodes  int             `json:"maxForcingNodes"`
+	MaxTemplateNodes int             `json:"maxTemplateNodes"`
+	Techniques       map[string]int  `json:"techniques"`
+	Constraints      Constraints     `json:"constraints"`
+	Candidates       bool            `json:"candidates"`
+	SolutionSteps    bool            `json:"solutionSteps"`
+	SolutionStates   bool            `json:"solutionStates"`
 }
 
 func (r *SolveKind) Validate(v Validator) {
 	initAndValidate(&r.BoxWidth, 3, 1, 32, v.Field("boxWidth"))
 	initAndValidate(&r.BoxHeight, 3, 1, 32, v.Field("boxHeight"))
 
-	v.Context["Kind"] = su.NewKind(int(r.BoxWidth), int(r.BoxHeight))
+	v.Context["Kind"] = r.Regions.toKind(int(r.BoxWidth), int(r.BoxHeight))
 }
 
 func (r SolveKind) toDomain() (*su.Puzzle, su.SolveLimit) {
 	boxWidth := su.Max(1, int(r.BoxWidth))
 	boxHeight := su.Max(1, int(r.BoxHeight))
-	kind := su.NewKind(boxWidth, boxHeight)
-	kind.Constraints = r.Constraints.toDomain()
+	kind := r.Constraints.toKind(r.Regions.toKind(boxWidth, boxHeight))
 
 	limit := su.SolveLimit{}
 
@@ -486,6 +862,10 @@ func (r SolveKind) toDomain() (*su.Puzzle, su.SolveLimit) {
 	applyValue(r.MinCost, &limit.MinCost)
 	applyValue(r.MaxSteps, &limit.MaxLogs)
 	applyValue(r.MaxPlacements, &limit.MaxPlacements)
+	applyValue(r.MaxChainLength, &limit.MaxChainLength)
+	applyValue(r.MaxForcingDepth, &limit.MaxForcingDepth)
+	applyValue(r.MaxForcingNodes, &limit.MaxForcingNodes)
+	applyValue(r.MaxTemplateNodes, &limit.MaxTemplateNodes)
 
 	puzzle := kind.Empty()
 	puzzle.SetAll(r.Puzzle)
@@ -2,7 +2,11 @@ package rest
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
+
+	su "github.com/ClickerMonkey/sudogo/pkg"
 )
 
 func TestOptional(t *testing.T) {
@@ -79,3 +83,61 @@ func TestOptional(t *testing.T) {
 		// }
 	}
 }
+
+func TestConstraintsValidate(t *testing.T) {
+	tests := []struct {
+		json     string
+		expected []string
+	}{
+		{
+			json:     `{"arrows": [{"circle": {"col": 0, "row": 0}, "arrow": []}]}`,
+			expected: []string{"arrows.0"},
+		},
+		{
+			json:     `{"littleKillers": [{"sum": 10, "start": {"col": 0, "row": 0}, "direction": {"col": 1, "row": 0}}]}`,
+			expected: []string{"littleKillers.0"},
+		},
+		{
+			json:     `{"thermos": [{"path": [{"col": 0, "row": 0}]}], "renbans": [{"path": [{"col": 0, "row": 0}, {"col": 1, "row": 0}]}]}`,
+			expected: []string{"thermos.0"},
+		},
+		{
+			json:     `{"thermos": [{"path": [{"col": 0, "row": 0}, {"col": 9, "row": 0}]}]}`,
+			expected: []string{"thermos.0.path.1.col"},
+		},
+	}
+
+	for _, test := range tests {
+		constraints := Constraints{}
+		if err := json.Unmarshal([]byte(test.json), &constraints); err != nil {
+			t.Fatalf("Error parsing JSON: %v", err)
+		}
+
+		v := NewValidator()
+		v.Context["Kind"] = su.Classic
+		v.Validate(&constraints)
+
+		paths := []string{}
+		for _, validation := range *v.Validations {
+			paths = append(paths, validation.Path)
+		}
+		if fmt.Sprint(paths) != fmt.Sprint(test.expected) {
+			t.Errorf("%s: expected validations at %v, got %v", test.json, test.expected, *v.Validations)
+		}
+	}
+}
+
+func TestPuzzleDeductionHouses(t *testing.T) {
+	deduction := su.SolverDeduction{
+		Houses: []su.SolverHouse{{Group: su.GroupRow, Index: 1}, {Group: su.GroupBox, Index: 4}, {Group: su.GroupHouse, Index: 0}},
+	}
+
+	encoded, err := json.Marshal(toPuzzleDeduction(&deduction))
+	if err != nil {
+		t.Fatal(err)
+	}
+	houses := `"houses":[{"group":"row","index":1},{"group":"box","index":4},{"group":"house","index":0}]`
+	if !strings.Contains(string(encoded), houses) {
+		t.Errorf("Expected %s in %s", houses, encoded)
+	}
+}
@@ -0,0 +1,183 @@
+package sudogo
+
+import (
+	"strings"
+	"unicode"
+)
+
+// A named rule which can be added to any kind with WithRules, like Anti-Knight or Disjoint Groups. A rule adds
+// houses, links, or constraints to the kind so the solver's techniques take it into account.
+type Rule struct {
+	Name  string
+	Apply func(kind *Kind)
+}
+
+// The moves of a knight in chess relative to a cell.
+var KnightLinks = []Position{{1, 2}, {2, 1}, {2, -1}, {1, -2}, {-1, -2}, {-2, -1}, {-2, 1}, {-1, 2}}
+
+// The diagonal moves of a king in chess relative to a cell, the others are in the same row or column.
+var KingLinks = []Position{{1, 1}, {1, -1}, {-1, -1}, {-1, 1}}
+
+// The orthogonally adjacent cells relative to a cell.
+var OrthogonalLinks = []Position{{1, 0}, {0, 1}, {-1, 0}, {0, -1}}
+
+// Cells a knight's move apart can't have the same digit.
+var RuleAntiKnight = &Rule{
+	Name: "Anti-Knight",
+	Apply: func(kind *Kind) {
+		kind.Links = append(kind.Links, KnightLinks...)
+	},
+}
+
+// Cells a king's move apart can't have the same digit.
+var RuleAntiKing = &Rule{
+	Name: "Anti-King",
+	Apply: func(kind *Kind) {
+		kind.Links = append(kind.Links, KingLinks...)
+	},
+}
+
+// Orthogonally adjacent cells can't have consecutive digits.
+var RuleNonConsecutive = &Rule{
+	Name: "Non-Consecutive",
+	Apply: func(kind *Kind) {
+		kind.Constraints = append(kind.Constraints, NewConstraintNonConsecutive())
+	},
+}
+
+// The cells in the same position of every box have every digit once. Kinds with irregular boxes are unchanged.
+var RuleDisjointGroups = &Rule{
+	Name: "Disjoint Groups",
+	Apply: func(kind *Kind) {
+		if !kind.Irregular() {
+			kind.Houses = append(kind.Houses, DisjointHouses(kind.BoxSize.Width, kind.BoxSize.Height)...)
+		}
+	},
+}
+
+// The rules which don't need any cells, in the order they're listed.
+var Rules = []*Rule{
+	RuleAntiKnight,
+	RuleAntiKing,
+	RuleNonConsecutive,
+	RuleDisjointGroups,
+}
+
+// Shaded cells where the even cells have even digits and the odd cells have odd digits.
+func RuleEvenOdd(even []Position, odd []Position) *Rule {
+	return &Rule{
+		Name: "Even/Odd",
+		Apply: func(kind *Kind) {
+			if len(even) > 0 {
+				kind.Constraints = append(kind.Constraints, &ConstraintParity{Cells: even})
+			}
+			if len(odd) > 0 {
+				kind.Constraints = append(kind.Constraints, &ConstraintParity{Cells: odd, Odd: true})
+			}
+		},
+	}
+}
+
+// The rule of Rules with the name, ignoring case and anything besides letters (so "anti-knight" and "antiKnight"
+// are Anti-Knight). Returns nil if there is no rule with the name.
+func RuleNamed(name string) *Rule {
+	key := ruleKey(name)
+	for _, rule := range Rules {
+		if ruleKey(rule.Name) == key {
+			return rule
+		}
+	}
+	return nil
+}
+
+func ruleKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if !unicode.IsLetter(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, name)
+}
+
+// Returns a clone of the kind with the rules added.
+func (kind *Kind) WithRules(rules ...*Rule) *Kind {
+	clone := kind.Clone()
+	for _, rule := range rules {
+		rule.Apply(clone)
+	}
+	return clone
+}
+
+// ==================================================
+// Constraint: Non-Consecutive
+// ==================================================
+
+// Orthogonally adjacent cells can't have consecutive digits, which is a difference of at least 2 between every
+// cell and its neighbors.
+type ConstraintNonConsecutive struct {
+	ConstraintDifference
+}
+
+func NewConstraintNonConsecutive() *ConstraintNonConsecutive {
+	return &ConstraintNonConsecutive{
+		ConstraintDifference: ConstraintDifference{
+			Min:      2,
+			Relative: &OrthogonalLinks,
+		},
+	}
+}
+
+func (c *ConstraintNonConsecutive) Technique() *SolveStep {
+	return StepNonConsecutive
+}
+
+// ==================================================
+// Step: Non-Consecutive
+// ==================================================
+var StepNonConsecutive = CreateStepConstraint("Non-Consecutive", 200, 100)
+
+// ==================================================
+// Constraint: Parity
+// ==================================================
+
+// Shaded cells which have even digits, or odd digits when Odd.
+type ConstraintParity struct {
+	Cells []Position
+	Odd   bool
+}
+
+// The parity constraints of the kind's constraints.
+func (kind *Kind) Parities() []*ConstraintParity {
+	parities := make([]*ConstraintParity, 0)
+	for _, constraint := range kind.Constraints {
+		if parity, ok := constraint.(*ConstraintParity); ok {
+			parities = append(parities, parity)
+		}
+	}
+	return parities
+}
+
+func (c *ConstraintParity) Technique() *SolveStep {
+	return StepEvenOdd
+}
+
+func (c *ConstraintParity) Affects(cell *Cell) bool {
+	return cellExists(cell, c.Cells)
+}
+
+func (c *ConstraintParity) RemoveCandidates(cell *Cell, puzzle *Puzzle, remove *Candidates) {
+	for _, candidate := range remove.ToSlice() {
+		if (candidate%2 == 1) != c.Odd {
+			remove.Set(candidate, false)
+		}
+	}
+}
+
+func (c *ConstraintParity) Validate(puzzle *Puzzle) []Position {
+	return validateCandidates(c, puzzle)
+}
+
+// ==================================================
+// Step: Even/Odd
+// ==================================================
+var StepEvenOdd = CreateStepConstraint("Even/Odd", 100, 50)
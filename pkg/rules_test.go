@@ -0,0 +1,152 @@
+package sudogo
+
+import (
+	"testing"
+)
+
+func TestRuleNamed(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected *Rule
+	}{
+		{"Anti-Knight", RuleAntiKnight},
+		{"antiKing", RuleAntiKing},
+		{"non consecutive", RuleNonConsecutive},
+		{"DISJOINT_GROUPS", RuleDisjointGroups},
+		{"anti-queen", nil},
+	}
+
+	for _, test := range tests {
+		actual := RuleNamed(test.name)
+		if actual != test.expected {
+			t.Errorf("RuleNamed(%q) returned %v, expected %v", test.name, actual, test.expected)
+		}
+	}
+}
+
+func TestAntiKnightLinks(t *testing.T) {
+	kind := Classic.WithRules(RuleAntiKnight)
+	if len(Classic.Links) != 0 {
+		t.Fatalf("Adding rules changed the original kind")
+	}
+
+	puzzle := kind.Empty()
+	corner := puzzle.Get(0, 0)
+	if len(corner.Links) != 2 {
+		t.Errorf("Expected the corner to have 2 links, got %v", corner.Links)
+	}
+	if !corner.InGroup(puzzle.Get(1, 2)) || !corner.InGroup(puzzle.Get(2, 1)) {
+		t.Errorf("Expected the corner to see the cells a knight's move away")
+	}
+	if corner.InGroup(puzzle.Get(3, 3)) {
+		t.Errorf("Expected the corner to not see r4c4")
+	}
+
+	solver := puzzle.Solver()
+	solver.Set(0, 0, 5)
+	linked := solver.Puzzle.Get(2, 1).candidates
+	if linked.Has(5) {
+		t.Errorf("Expected 5 to be removed from r2c3, got %v", linked.ToSlice())
+	}
+
+	puzzle.Set(4, 4, 1)
+	puzzle.Get(6, 5).Value = 1
+	if puzzle.IsValid() {
+		t.Errorf("Expected the same digit a knight's move apart to be invalid")
+	}
+}
+
+func TestDisjointHouses(t *testing.T) {
+	houses := DisjointHouses(2, 2)
+	expected := [][]Position{
+		{{0, 0}, {2, 0}, {0, 2}, {2, 2}},
+		{{1, 0}, {3, 0}, {1, 2}, {3, 2}},
+		{{0, 1}, {2, 1}, {0, 3}, {2, 3}},
+		{{1, 1}, {3, 1}, {1, 3}, {3, 3}},
+	}
+	if len(houses) != len(expected) {
+		t.Fatalf("Expected %d houses, got %v", len(expected), houses)
+	}
+	for i, house := range houses {
+		for k := range house {
+			if house[k] != expected[i][k] {
+				t.Errorf("Expected house %d to be %v, got %v", i, expected[i], house)
+				break
+			}
+		}
+	}
+
+	jigsaw := Classic.Clone()
+	jigsaw.Regions = [][]int{{0}}
+	if len(jigsaw.WithRules(RuleDisjointGroups).Houses) != 0 {
+		t.Errorf("Expected irregular kinds to not have disjoint groups")
+	}
+}
+
+func TestRuleConstraints(t *testing.T) {
+	puzzle := Classic.Create([][]int{
+		{5, 0, 0, 0, 0, 0, 0, 0, 0},
+	})
+	testConstraintCandidates(t, "non-consecutive", puzzle, NewConstraintNonConsecutive(),
+		[]Position{{1, 0}, {0, 1}},
+		[]string{"[1 2 3 7 8 9]", "[1 2 3 7 8 9]"})
+
+	testConstraintCandidates(t, "even", Classic.Empty(), &ConstraintParity{Cells: []Position{{0, 0}}},
+		[]Position{{0, 0}},
+		[]string{"[2 4 6 8]"})
+
+	testConstraintCandidates(t, "odd", Classic.Empty(), &ConstraintParity{Cells: []Position{{0, 0}}, Odd: true},
+		[]Position{{0, 0}},
+		[]string{"[1 3 5 7 9]"})
+}
+
+func TestRulesGenerate(t *testing.T) {
+	even := []Position{{0, 0}, {4, 4}}
+	odd := []Position{{8, 8}}
+	tests := []struct {
+		name  string
+		kind  *Kind
+		clear bool
+	}{
+		{"anti-knight", Classic.WithRules(RuleAntiKnight), true},
+		{"anti-king", Classic.WithRules(RuleAntiKing), true},
+		// Clearing checks uniqueness with GetSolutions when there are constraints, which is slow for non-consecutive.
+		{"non-consecutive", Classic.WithRules(RuleNonConsecutive), false},
+		{"disjoint groups and even/odd", Classic.WithRules(RuleDisjointGroups, RuleEvenOdd(even, odd)), true},
+	}
+
+	for _, test := range tests {
+		gen := NewSeededGenerator(test.kind, 1)
+		solution, _ := gen.Generate()
+		if solution == nil {
+			t.Fatalf("%s: failed to generate a puzzle", test.name)
+		}
+		if !solution.IsSolved() {
+			t.Fatalf("%s: generated puzzle is not solved:\n%s", test.name, solution.ToConsoleString())
+		}
+		if solution.CountSolutions(2) != 1 {
+			t.Errorf("%s: generated solution breaks the rules:\n%s", test.name, solution.ToConsoleString())
+		}
+		if test.name == "non-consecutive" {
+			for _, cell := range solution.Cells {
+				for _, offset := range OrthogonalLinks {
+					other := getRelativeCell(solution, offset, &cell)
+					if other != nil && (cell.Value-other.Value == 1 || other.Value-cell.Value == 1) {
+						t.Errorf("%s: r%dc%d and r%dc%d are consecutive:\n%s", test.name, cell.Row+1, cell.Col+1, other.Row+1, other.Col+1, solution.ToConsoleString())
+					}
+				}
+			}
+		}
+		if !test.clear {
+			continue
+		}
+
+		puzzle, _ := gen.ClearCells(solution, ClearLimit{SolveLimit: SolveLimit{MaxPlacements: 60}})
+		if puzzle == nil {
+			t.Fatalf("%s: failed to clear the puzzle", test.name)
+		}
+		if puzzle.CountSolutions(2) != 1 {
+			t.Errorf("%s: cleared puzzle doesn't have a unique solution: %s", test.name, puzzle.String())
+		}
+	}
+}
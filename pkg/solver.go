@@ -9,6 +9,15 @@ type Solver struct {
 	Boxs     [][]*Cell
 	Rows     [][]*Cell
 	Cols     [][]*Cell
+	// The unsolved cells of each extra house of the puzzle's kind.
+	Houses [][]*Cell
+
+	// Whether the puzzle is known to have a single solution, which is required by the uniqueness steps.
+	AssumeUnique bool
+	// Whether each cell (by id) had a value before solving.
+	givens []bool
+	// The almost locked sets last found, shared by the almost locked set steps.
+	alsCache *alsCache
 
 	LogEnabled    bool
 	LogState      bool
@@ -28,6 +37,82 @@ type SolverLog struct {
 	State             *Puzzle
 	RunningCost       int
 	RunningPlacements int
+	// The deduction of the batch, shared by every log in the batch.
+	Deduction *SolverDeduction
+}
+
+// A deduction made by a step: the pattern it found (the cells and candidates which triggered the technique)
+// and the candidates it removed and values it placed.
+type SolverDeduction struct {
+	Cells      []Position
+	Candidates []int
+	// The houses the pattern was found in, if the step searches houses.
+	Houses       []SolverHouse
+	Eliminations []SolverCandidate
+	Placements   []SolverCandidate
+	// The implications of a chain from its start to its end, or of a forcing step from each assumption to
+	// what's placed or eliminated, or to the contradiction the assumption led to.
+	Implications [][]SolverImplication
+	// The candidates of each color of a coloring step, each color followed by its opposite. The candidates
+	// of a color are all true or all false, and a color and its opposite aren't both.
+	Colors [][]SolverCandidate
+	// The cells of a forcing step's contradictions, like a cell left without candidates or a house left
+	// without a place for a candidate.
+	Contradiction []Position
+}
+
+// A candidate or value in the cell at the position.
+type SolverCandidate struct {
+	Position
+	Value int
+}
+
+// A candidate decided true or false by an implication of a forcing step.
+type SolverImplication struct {
+	SolverCandidate
+	On bool
+}
+
+// A row, column, box, or extra house by its index.
+type SolverHouse struct {
+	Group Group
+	Index int
+}
+
+// Returns whether the cell is in the house.
+func (house SolverHouse) Contains(cell *Cell) bool {
+	if house.Group == GroupHouse {
+		return cell.HasHouse(house.Index)
+	}
+	return cell.GetGroup(house.Group) == house.Index
+}
+
+func (deduction *SolverDeduction) addCell(cell *Cell) {
+	position := Position{Col: cell.Col, Row: cell.Row}
+	if sliceIndex(deduction.Cells, func(p Position) bool { return p == position }) == -1 {
+		deduction.Cells = append(deduction.Cells, position)
+	}
+}
+
+func (deduction *SolverDeduction) addHouse(house SolverHouse) {
+	if sliceIndex(deduction.Houses, func(h SolverHouse) bool { return h == house }) == -1 {
+		deduction.Houses = append(deduction.Houses, house)
+	}
+}
+
+func (deduction *SolverDeduction) addCandidate(candidate int) {
+	if sliceIndex(deduction.Candidates, func(c int) bool { return c == candidate }) == -1 {
+		deduction.Candidates = append(deduction.Candidates, candidate)
+	}
+}
+
+// Records the candidates removed from the cell.
+func (deduction *SolverDeduction) addChange(before *Cell, after *Cell) {
+	removed := before.candidates
+	removed.Remove(after.candidates)
+	for _, candidate := range removed.ToSlice() {
+		deduction.Eliminations = append(deduction.Eliminations, SolverCandidate{Position{Col: after.Col, Row: after.Row}, candidate})
+	}
 }
 
 func (log SolverLog) String() string {
@@ -39,12 +124,16 @@ func (log SolverLog) String() string {
 }
 
 type SolveLimit struct {
-	MinCost       int
-	MaxCost       int
-	MaxPlacements int
-	MaxLogs       int
-	MaxBatches    int
-	Techniques    map[string]int
+	MinCost          int
+	MaxCost          int
+	MaxPlacements    int
+	MaxLogs          int
+	MaxBatches       int
+	MaxChainLength   int
+	MaxForcingDepth  int
+	MaxForcingNodes  int
+	MaxTemplateNodes int
+	Techniques       map[string]int
 }
 
 type SolveStepLogic func(solver *Solver, limits SolveLimit, step *SolveStep) (placements int, restart bool)
@@ -62,18 +151,93 @@ var StandardSolveSteps = []*SolveStep{
 	StepPointingCandidates,
 	StepClaimingCandidates,
 	StepConstraints,
+	StepKillerCombination,
+	StepArrowSum,
+	StepLittleKillerSum,
+	StepSandwichSum,
+	StepThermoBound,
+	StepRenbanSet,
+	StepWhispersDifference,
+	StepPalindromeMirror,
+	StepBetweenBound,
+	StepRegionSum,
+	StepNonConsecutive,
+	StepEvenOdd,
+	StepKropkiPair,
+	StepXVPair,
+	StepInniesOuties,
 	StepSkyscraper,
 	Step2StringKite,
 	StepNakedSubsets2,
 	StepHiddenSubsets2,
+	StepBUG1,
+	StepUniqueRectangle1,
+	StepUniqueRectangle2,
+	StepUniqueRectangle4,
+	StepAvoidableRectangle,
+	StepUniqueRectangle3,
+	StepUniqueRectangle5,
+	StepUniqueRectangle6,
+	StepHiddenRectangle,
 	StepEmptyRectangle,
 	StepXWing,
+	StepFinnedXWing,
+	StepSashimiXWing,
+	StepWWing,
+	StepXYWing,
+	StepXYZWing,
+	StepSimpleColoring,
+	StepMultiColoring,
 	StepNakedSubsets3,
 	StepHiddenSubsets3,
 	StepSwordfish,
+	StepFinnedSwordfish,
+	StepSashimiSwordfish,
 	StepNakedSubsets4,
 	StepHiddenSubsets4,
+	StepWXYZWing,
+	Step3DMedusa,
+	StepXChain,
+	StepXYChain,
+	StepSueDeCoq,
 	StepJellyfish,
+	StepFinnedJellyfish,
+	StepSashimiJellyfish,
+	StepSiameseXWing,
+	StepSiameseSwordfish,
+	StepSiameseJellyfish,
+	StepALSXZ,
+	StepALSXYWing,
+	StepALSChain,
+	StepDeathBlossom,
+	StepContinuousNiceLoop,
+	StepDiscontinuousNiceLoop,
+	StepTemplates,
+	StepForcingChainContradiction,
+	StepCellForcingChain,
+	StepRegionForcingChain,
+	StepDigitForcingChain,
+	StepForcingNetContradiction,
+	StepCellForcingNet,
+	StepRegionForcingNet,
+	StepDigitForcingNet,
+}
+
+// Steps which are too expensive to always try, they are only added to a solve when they are
+// requested by technique in SolveLimit.Techniques.
+var OptionalSolveSteps = []*SolveStep{
+	StepFrankenXWing,
+	StepFinnedFrankenXWing,
+	StepFrankenSwordfish,
+	StepFinnedFrankenSwordfish,
+	StepFrankenJellyfish,
+	StepFinnedFrankenJellyfish,
+	StepMutantXWing,
+	StepFinnedMutantXWing,
+	StepMutantSwordfish,
+	StepFinnedMutantSwordfish,
+	StepMutantJellyfish,
+	StepFinnedMutantJellyfish,
 }
 
 var GenerateSolveSteps = []*SolveStep{
@@ -82,6 +246,20 @@ var GenerateSolveSteps = []*SolveStep{
 	StepPointingCandidates,
 	StepClaimingCandidates,
 	StepConstraints,
+	StepKillerCombination,
+	StepArrowSum,
+	StepLittleKillerSum,
+	StepSandwichSum,
+	StepThermoBound,
+	StepRenbanSet,
+	StepWhispersDifference,
+	StepPalindromeMirror,
+	StepBetweenBound,
+	StepRegionSum,
+	StepNonConsecutive,
+	StepEvenOdd,
+	StepKropkiPair,
+	StepXVPair,
 	StepNakedSubsets2,
 	StepNakedSubsets3,
 }
@@ -93,12 +271,17 @@ func NewSolver(starting Puzzle) Solver {
 	rows := make([][]*Cell, groupCapacity)
 	cols := make([][]*Cell, groupCapacity)
 	boxs := make([][]*Cell, groupCapacity)
+	houses := make([][]*Cell, len(puzzle.Kind.Houses))
+	givens := make([]bool, len(puzzle.Cells))
 
 	for i := 0; i < groupCapacity; i++ {
 		rows[i] = make([]*Cell, 0, groupCapacity)
 		cols[i] = make([]*Cell, 0, groupCapacity)
 		boxs[i] = make([]*Cell, 0, groupCapacity)
 	}
+	for i := range houses {
+		houses[i] = make([]*Cell, 0, groupCapacity)
+	}
 
 	for i := range puzzle.Cells {
 		cell := &puzzle.Cells[i]
@@ -108,6 +291,11 @@ func NewSolver(starting Puzzle) Solver {
 			rows[cell.Row] = append(rows[cell.Row], cell)
 			cols[cell.Col] = append(cols[cell.Col], cell)
 			boxs[cell.Box] = append(boxs[cell.Box], cell)
+			for _, house := range cell.Houses {
+				houses[house] = append(houses[house], cell)
+			}
+		} else {
+			givens[i] = true
 		}
 	}
 
@@ -118,6 +306,8 @@ func NewSolver(starting Puzzle) Solver {
 		Rows:          rows,
 		Cols:          cols,
 		Boxs:          boxs,
+		Houses:        houses,
+		givens:        givens,
 		LogEnabled:    false,
 		LogState:      false,
 		LogTechniques: map[string]int{},
@@ -126,6 +316,11 @@ func NewSolver(starting Puzzle) Solver {
 	}
 }
 
+// Returns whether the cell had a value before solving.
+func (solver *Solver) IsGiven(cell *Cell) bool {
+	return solver.givens[cell.Id]
+}
+
 func (solver *Solver) Row(row int) []*Cell {
 	return solver.Rows[row]
 }
@@ -138,6 +333,8 @@ func (solver *Solver) Box(box int) []*Cell {
 	return solver.Boxs[box]
 }
 
+// The unsolved cells in the cell's column, row, or box. Extra houses are found with GroupAt since a cell
+// can be in more than one.
 func (solver *Solver) Group(groupIndex Group, cell *Cell) []*Cell {
 	if groupIndex == GroupCol {
 		return solver.Cols[cell.Col]
@@ -148,6 +345,28 @@ func (solver *Solver) Group(groupIndex Group, cell *Cell) []*Cell {
 	}
 }
 
+// The unsolved cells of the cell's row, column, box, and extra houses.
+func (solver *Solver) cellHouses(cell *Cell) [][]*Cell {
+	houses := make([][]*Cell, 0, 3+len(cell.Houses))
+	houses = append(houses, solver.Rows[cell.Row], solver.Cols[cell.Col], solver.Boxs[cell.Box])
+	for _, house := range cell.Houses {
+		houses = append(houses, solver.Houses[house])
+	}
+	return houses
+}
+
+func (solver *Solver) GroupAt(groupIndex Group, index int) []*Cell {
+	if groupIndex == GroupCol {
+		return solver.Cols[index]
+	} else if groupIndex == GroupRow {
+		return solver.Rows[index]
+	} else if groupIndex == GroupHouse {
+		return solver.Houses[index]
+	} else {
+		return solver.Boxs[index]
+	}
+}
+
 func (solver *Solver) Set(col int, row int, value int) bool {
 	return solver.SetCell(solver.Puzzle.Get(col, row), value)
 }
@@ -171,11 +390,22 @@ func (solver *Solver) SetCell(cell *Cell, value int) bool {
 		for _, other := range boxs {
 			other.RemoveCandidate(value)
 		}
+		for _, house := range cell.Houses {
+			for _, other := range solver.Houses[house] {
+				other.RemoveCandidate(value)
+			}
+		}
+		for _, link := range cell.Links {
+			solver.Puzzle.Cells[link].RemoveCandidate(value)
+		}
 
 		solver.Unsolved = removeValue(solver.Unsolved, cell)
 		solver.Rows[cell.Row] = removeValue(rows, cell)
 		solver.Cols[cell.Col] = removeValue(cols, cell)
 		solver.Boxs[cell.Box] = removeValue(boxs, cell)
+		for _, house := range cell.Houses {
+			solver.Houses[house] = removeValue(solver.Houses[house], cell)
+		}
 	}
 
 	return set
@@ -209,7 +439,12 @@ func (solver *Solver) GetLastLog() *SolverLog {
 }
 
 func (solver *Solver) CanContinue(limits SolveLimit, cost int) bool {
-	lastLog := solver.GetLastLog()
+	return canContinue(limits, solver.GetLastLog(), solver.LogTechniques, cost)
+}
+
+// Returns whether solving can continue with a step of the given cost after the last log and the number of
+// times each technique was used.
+func canContinue(limits SolveLimit, lastLog *SolverLog, techniques map[string]int, cost int) bool {
 	if limits.MaxLogs > 0 && lastLog.Index >= limits.MaxLogs {
 		return false
 	}
@@ -227,7 +462,7 @@ func (solver *Solver) CanContinue(limits SolveLimit, cost int) bool {
 	}
 	if limits.Techniques != nil && len(limits.Techniques) > 0 {
 		for technique, count := range limits.Techniques {
-			if solver.LogTechniques[technique] < count {
+			if techniques[technique] < count {
 				return true
 			}
 		}
@@ -250,12 +485,68 @@ func (solver *Solver) GetCost(step *SolveStep) int {
 }
 
 func (solver *Solver) LogStep(step *SolveStep) {
-	cost := solver.GetCost(step)
+	solver.LogStepCost(step, solver.GetCost(step))
+}
+
+// Starts a new batch for the step with a cost which may differ from the step's costs.
+func (solver *Solver) LogStepCost(step *SolveStep, cost int) {
 	solver.LogTechniques[step.Technique]++
 	solver.logTemplate.Batch++
 	solver.logTemplate.Step = step
 	solver.logTemplate.Cost = cost
 	solver.logTemplate.RunningCost += cost
+	solver.logTemplate.Deduction = nil
+	if solver.LogEnabled {
+		solver.logTemplate.Deduction = &SolverDeduction{}
+	}
+}
+
+// Adds the cells and candidates to the pattern of the current batch's deduction.
+func (solver *Solver) LogPattern(cells []*Cell, candidates ...int) {
+	deduction := solver.logTemplate.Deduction
+	if deduction == nil {
+		return
+	}
+	for _, cell := range cells {
+		deduction.addCell(cell)
+	}
+	for _, candidate := range candidates {
+		deduction.addCandidate(candidate)
+	}
+}
+
+// Adds the house to the current batch's deduction.
+func (solver *Solver) LogHouse(group Group, index int) {
+	if solver.logTemplate.Deduction != nil {
+		solver.logTemplate.Deduction.addHouse(SolverHouse{group, index})
+	}
+}
+
+// Adds the implications to the current batch's deduction.
+func (solver *Solver) LogImplications(paths ...[]SolverImplication) {
+	if solver.logTemplate.Deduction != nil {
+		solver.logTemplate.Deduction.Implications = append(solver.logTemplate.Deduction.Implications, paths...)
+	}
+}
+
+// Adds the colors to the current batch's deduction.
+func (solver *Solver) LogColors(colors ...[]SolverCandidate) {
+	if solver.logTemplate.Deduction != nil {
+		solver.logTemplate.Deduction.Colors = append(solver.logTemplate.Deduction.Colors, colors...)
+	}
+}
+
+// Adds the cells of a contradiction to the current batch's deduction.
+func (solver *Solver) LogContradiction(cells []Position) {
+	deduction := solver.logTemplate.Deduction
+	if deduction == nil {
+		return
+	}
+	for _, cell := range cells {
+		if sliceIndex(deduction.Contradiction, func(p Position) bool { return p == cell }) == -1 {
+			deduction.Contradiction = append(deduction.Contradiction, cell)
+		}
+	}
 }
 
 func (solver *Solver) LogBefore(before *Cell) {
@@ -270,6 +561,9 @@ func (solver *Solver) LogBefore(before *Cell) {
 func (solver *Solver) LogAfter(after *Cell) {
 	last := solver.GetLastLog()
 	last.After = *after
+	if last.Deduction != nil {
+		last.Deduction.addChange(&last.Before, after)
+	}
 	if solver.LogState {
 		state := solver.Puzzle.Clone()
 		last.State = &state
@@ -280,6 +574,13 @@ func (solver *Solver) LogPlacement(after *Cell) {
 	last := solver.GetLastLog()
 	last.After = *after
 	last.Placement = true
+	if last.Deduction != nil {
+		if after.Value > 0 {
+			last.Deduction.Placements = append(last.Deduction.Placements, SolverCandidate{Position{Col: after.Col, Row: after.Row}, after.Value})
+		} else {
+			last.Deduction.addChange(&last.Before, after)
+		}
+	}
 	last.RunningPlacements = last.RunningPlacements + 1
 	solver.logTemplate.RunningPlacements = last.RunningPlacements
 	if solver.LogState {
@@ -292,8 +593,22 @@ func (solver *Solver) Solved() bool {
 	return len(solver.Unsolved) == 0
 }
 
-func (solver *Solver) Solve(limits SolveLimit) (*Puzzle, bool) {
+// The steps to solve with, which includes any optional steps requested in the limits.
+func (solver *Solver) GetSteps(limits SolveLimit) []*SolveStep {
 	steps := solver.Steps
+	for _, step := range OptionalSolveSteps {
+		if _, requested := limits.Techniques[step.Technique]; !requested {
+			continue
+		}
+		if sliceIndex(steps, func(existing *SolveStep) bool { return existing == step }) == -1 {
+			steps = append(sliceClone(steps), step)
+		}
+	}
+	return steps
+}
+
+func (solver *Solver) Solve(limits SolveLimit) (*Puzzle, bool) {
+	steps := solver.GetSteps(limits)
 	placing := true
 	for placing {
 		placing = false
@@ -342,6 +657,7 @@ var StepNakedSingle = &SolveStep{
 			cell, cellValue := getNakedSingle(solver)
 			if cell != nil {
 				solver.LogStep(step)
+				solver.LogPattern([]*Cell{cell}, cellValue)
 				solver.LogBefore(cell)
 				solver.SetCell(cell, cellValue)
 				solver.LogPlacement(cell)
@@ -376,9 +692,11 @@ var StepHiddenSingle = &SolveStep{
 	Logic: func(solver *Solver, limits SolveLimit, step *SolveStep) (int, bool) {
 		placements := 0
 		for solver.CanContinueStep(limits, step) {
-			cell, cellValue := getHiddenSingle(solver)
+			cell, cellValue, house := getHiddenSingle(solver)
 			if cell != nil {
 				solver.LogStep(step)
+				solver.LogPattern([]*Cell{cell}, cellValue)
+				solver.LogHouse(house.Group, house.Index)
 				solver.LogBefore(cell)
 				solver.SetCell(cell, cellValue)
 				solver.LogPlacement(cell)
@@ -392,23 +710,29 @@ var StepHiddenSingle = &SolveStep{
 	},
 }
 
-// A cell which has a candidate that is unique to the row, cell, or box
-func getHiddenSingle(solver *Solver) (*Cell, int) {
+// A cell which has a candidate that is unique to the row, cell, box, or extra house, and that house
+func getHiddenSingle(solver *Solver) (*Cell, int, SolverHouse) {
 	for _, cell := range solver.Unsolved {
 		box := getHiddenSingleFromGroup(cell, solver.Box(cell.Box))
 		if box != 0 {
-			return cell, box
+			return cell, box, SolverHouse{GroupBox, cell.Box}
 		}
 		row := getHiddenSingleFromGroup(cell, solver.Row(cell.Row))
 		if row != 0 {
-			return cell, row
+			return cell, row, SolverHouse{GroupRow, cell.Row}
 		}
 		col := getHiddenSingleFromGroup(cell, solver.Col(cell.Col))
 		if col != 0 {
-			return cell, col
+			return cell, col, SolverHouse{GroupCol, cell.Col}
+		}
+		for _, house := range cell.Houses {
+			value := getHiddenSingleFromGroup(cell, solver.Houses[house])
+			if value != 0 {
+				return cell, value, SolverHouse{GroupHouse, house}
+			}
 		}
 	}
-	return nil, 0
+	return nil, 0, SolverHouse{}
 }
 
 // Get the candidate hidden single found in the given group, or 0 if none found.
@@ -448,11 +772,14 @@ var StepConstraints = &SolveStep{
 			candidates := cell.candidates
 
 			for _, constraint := range cell.Constraints {
-				constraint.RemoveCandidates(cell, &solver.Puzzle, &candidates)
+				if !solver.hasConstraintStep(constraint) {
+					constraint.RemoveCandidates(cell, &solver.Puzzle, &candidates)
+				}
 			}
 
-			if candidates.Value != cell.candidates.Value {
+			if !candidates.Equals(cell.candidates) {
 				solver.LogStep(step)
+				solver.LogPattern([]*Cell{cell})
 				solver.LogBefore(cell)
 				removed += cell.candidates.Count - candidates.Count
 				cell.candidates = candidates
@@ -468,6 +795,94 @@ var StepConstraints = &SolveStep{
 	},
 }
 
+// Returns whether the constraint's deductions are made by one of the solver's steps instead of StepConstraints.
+func (solver *Solver) hasConstraintStep(constraint Constraint) bool {
+	technique, ok := constraint.(ConstraintTechnique)
+	if !ok {
+		return false
+	}
+	step := technique.Technique()
+	return sliceIndex(solver.Steps, func(s *SolveStep) bool { return s == step }) != -1
+}
+
+// ==================================================
+// Step: Constraint Techniques
+// ==================================================
+
+// Creates a step which makes the deductions of the constraints whose technique is the step. The candidates a
+// constraint removes from its cells, and from other cells when it's a ConstraintEliminator, are one deduction.
+func CreateStepConstraint(technique string, firstCost int, subsequentCost int) *SolveStep {
+	return &SolveStep{
+		Technique:      technique,
+		FirstCost:      firstCost,
+		SubsequentCost: subsequentCost,
+		Logic: func(solver *Solver, limits SolveLimit, step *SolveStep) (int, bool) {
+			removed := 0
+
+			for _, constraint := range solver.Puzzle.Kind.Constraints {
+				if !solver.CanContinueStep(limits, step) {
+					break
+				}
+				if technique, ok := constraint.(ConstraintTechnique); ok && technique.Technique() == step {
+					removed += doRemoveConstraintCandidates(solver, step, constraint)
+				}
+			}
+
+			return 0, removed > 0
+		},
+	}
+}
+
+// Removes the candidates the constraint can't have from its cells and the cells it eliminates from. Returns
+// the number of candidates removed.
+func doRemoveConstraintCandidates(solver *Solver, step *SolveStep, constraint Constraint) int {
+	pattern := make([]*Cell, 0)
+	cells := make([]*Cell, 0)
+	changes := make([]Candidates, 0)
+
+	for _, cell := range solver.Unsolved {
+		if !constraint.Affects(cell) {
+			continue
+		}
+		pattern = append(pattern, cell)
+		candidates := cell.candidates
+		constraint.RemoveCandidates(cell, &solver.Puzzle, &candidates)
+		if !candidates.Equals(cell.candidates) {
+			cells = append(cells, cell)
+			changes = append(changes, candidates)
+		}
+	}
+
+	if eliminator, ok := constraint.(ConstraintEliminator); ok {
+		for _, elimination := range eliminator.Eliminations(&solver.Puzzle) {
+			cell := solver.Puzzle.Get(elimination.Col, elimination.Row)
+			i := sliceIndex(cells, func(c *Cell) bool { return c.Id == cell.Id })
+			if i == -1 {
+				if !cell.candidates.Has(elimination.Value) {
+					continue
+				}
+				i = len(cells)
+				cells = append(cells, cell)
+				changes = append(changes, cell.candidates)
+			}
+			changes[i].Set(elimination.Value, false)
+		}
+	}
+
+	removed := 0
+	if len(cells) > 0 {
+		solver.LogStep(step)
+		solver.LogPattern(pattern)
+		for i, cell := range cells {
+			solver.LogBefore(cell)
+			removed += cell.candidates.Count - changes[i].Count
+			cell.candidates = changes[i]
+			solver.LogAfter(cell)
+		}
+	}
+	return removed
+}
+
 // ==================================================
 // Step: Pointing Candidates
 //		http://hodoku.sourceforge.net/en/tech_intersections.php
@@ -486,17 +901,30 @@ var StepPointingCandidates = &SolveStep{
 }
 
 // If in a box all candidates of a certain digit are confined to a row or column, that digit cannot appear outside of that box in that row or column.
+// The same goes for the extra houses a box intersects.
 func doRemovePointingCandidates(solver *Solver, limits SolveLimit, step *SolveStep) int {
 	removed := 0
 
 	for _, group := range solver.Unsolved {
 		removed += doRemovePointingCandidatesGroup(solver, limits, step, group, GroupCol)
 		if !solver.CanContinueStep(limits, step) {
-			break
+			return removed
 		}
 		removed += doRemovePointingCandidatesGroup(solver, limits, step, group, GroupRow)
 		if !solver.CanContinueStep(limits, step) {
-			break
+			return removed
+		}
+	}
+
+	for house := range solver.Houses {
+		extra := SolverHouse{GroupHouse, house}
+		for _, other := range solver.getHouseIntersections(house) {
+			if other.Group == GroupBox {
+				removed += doRemoveLockedCandidates(solver, limits, step, other, extra)
+				if !solver.CanContinueStep(limits, step) {
+					return removed
+				}
+			}
 		}
 	}
 
@@ -542,6 +970,8 @@ func doRemovePointingCandidatesGroup(solver *Solver, limits SolveLimit, step *So
 		}
 		if hasOverlap {
 			solver.LogStep(step)
+			solver.LogPattern(getCellsWithCandidates(solver.Box(cell.Box), cand), cand.ToSlice()...)
+			solver.LogHouse(GroupBox, cell.Box)
 			for _, other := range solver.Group(groupIndex, cell) {
 				if other.Id == cell.Id {
 					continue
@@ -575,13 +1005,28 @@ var StepClaimingCandidates = &SolveStep{
 	},
 }
 
-// If in a row or column a candidate only appears in a single box then that candidate can be removed from other cells in that box
+// If in a row or column a candidate only appears in a single box then that candidate can be removed from other cells in that box.
+// The same goes for candidates of an extra house confined to another house, and of a row or column confined to an extra house.
 func doRemoveClaimingCandidates(solver *Solver, limits SolveLimit, step *SolveStep) int {
 	removed := 0
 	removed += doRemoveClaimingCandidatesGroups(solver, limits, step, GroupCol)
 	if solver.CanContinueStep(limits, step) {
 		removed += doRemoveClaimingCandidatesGroups(solver, limits, step, GroupRow)
 	}
+
+	for house := range solver.Houses {
+		extra := SolverHouse{GroupHouse, house}
+		for _, other := range solver.getHouseIntersections(house) {
+			if !solver.CanContinueStep(limits, step) {
+				return removed
+			}
+			removed += doRemoveLockedCandidates(solver, limits, step, extra, other)
+			if (other.Group == GroupRow || other.Group == GroupCol) && solver.CanContinueStep(limits, step) {
+				removed += doRemoveLockedCandidates(solver, limits, step, other, extra)
+			}
+		}
+	}
+
 	return removed
 }
 
@@ -606,6 +1051,8 @@ func doRemoveClaimingCandidatesGroups(solver *Solver, limits SolveLimit, step *S
 		// what is remaining are the candidates unique to the row outside this box
 		if cand.Count > 0 {
 			solver.LogStep(step)
+			solver.LogPattern(getCellsWithCandidates(solver.Group(groupIndex, cell), cand), cand.ToSlice()...)
+			solver.LogHouse(groupIndex, cell.GetGroup(groupIndex))
 			for _, other := range solver.Box(cell.Box) {
 				if other.Id == cell.Id {
 					continue
@@ -625,6 +1072,67 @@ func doRemoveClaimingCandidatesGroups(solver *Solver, limits SolveLimit, step *S
 	return removed
 }
 
+// The rows, columns, boxes, and other extra houses which share unsolved cells with the extra house.
+func (solver *Solver) getHouseIntersections(house int) []SolverHouse {
+	intersections := make([]SolverHouse, 0)
+	add := func(other SolverHouse) {
+		if sliceIndex(intersections, func(h SolverHouse) bool { return h == other }) == -1 {
+			intersections = append(intersections, other)
+		}
+	}
+	for _, cell := range solver.Houses[house] {
+		add(SolverHouse{GroupRow, cell.Row})
+		add(SolverHouse{GroupCol, cell.Col})
+		add(SolverHouse{GroupBox, cell.Box})
+		for _, other := range cell.Houses {
+			if other != house {
+				add(SolverHouse{GroupHouse, other})
+			}
+		}
+	}
+	return intersections
+}
+
+// If the candidates of a house are confined to the cells it shares with the target house then they can be removed
+// from the rest of the target house.
+func doRemoveLockedCandidates(solver *Solver, limits SolveLimit, step *SolveStep, house SolverHouse, target SolverHouse) int {
+	removed := 0
+	cells := solver.GroupAt(house.Group, house.Index)
+
+	// the candidates inside the target house which don't exist outside of it
+	cand := Candidates{}
+	for _, cell := range cells {
+		if target.Contains(cell) {
+			cand.Or(cell.candidates)
+		}
+	}
+	for _, cell := range cells {
+		if !target.Contains(cell) {
+			cand.Remove(cell.candidates)
+		}
+	}
+
+	if cand.Count == 0 {
+		return 0
+	}
+
+	others := sliceWhere(solver.GroupAt(target.Group, target.Index), func(other *Cell) bool {
+		return !house.Contains(other) && other.candidates.Overlaps(cand)
+	})
+	if len(others) > 0 {
+		solver.LogStep(step)
+		solver.LogPattern(getCellsWithCandidates(sliceWhere(cells, target.Contains), cand), cand.ToSlice()...)
+		solver.LogHouse(house.Group, house.Index)
+		for _, other := range others {
+			solver.LogBefore(other)
+			removed += other.candidates.Remove(cand)
+			solver.LogAfter(other)
+		}
+	}
+
+	return removed
+}
+
 // ==================================================
 // Step: Remove Naked Subset Candidates
 //		http://hodoku.sourceforge.net/en/tech_naked.php
@@ -669,6 +1177,12 @@ func doRemoveNakedSubsetCandidates(solver *Solver, subsetSize int, limits SolveL
 		if !solver.CanContinueStep(limits, step) {
 			break
 		}
+		for _, house := range cell.Houses {
+			removed += removeNakedSubsetCandidatesFromGroup(cell, subsetSize, solver, limits, step, solver.Houses[house])
+			if !solver.CanContinueStep(limits, step) {
+				return removed
+			}
+		}
 	}
 
 	return removed
@@ -682,52 +1196,64 @@ func removeNakedSubsetCandidatesFromGroup(cell *Cell, subsetSize int, solver *So
 	sameBox := true
 	sameRow := true
 	sameCol := true
+	sameHouses := cell.Houses
 
 	for _, other := range group {
 		if other.Id == cell.Id {
 			continue
 		}
-		if other.candidates.Value == candidates.Value {
+		if other.candidates.Equals(candidates) {
 			matches++
 			sameBox = sameBox && other.Box == cell.Box
 			sameRow = sameRow && other.Row == cell.Row
 			sameCol = sameCol && other.Col == cell.Col
+			if len(sameHouses) > 0 {
+				sameHouses = sliceWhere(sameHouses, other.HasHouse)
+			}
 		}
 	}
 
 	if matches == subsetSize {
 		if sameBox {
-			removed += removeCandidatesFromDifferent(cell, solver.Box(cell.Box), candidates, solver, limits, step)
+			removed += removeCandidatesFromDifferent(cell, SolverHouse{GroupBox, cell.Box}, candidates, solver, limits, step)
 		}
 		if sameRow && solver.CanContinueStep(limits, step) {
-			removed += removeCandidatesFromDifferent(cell, solver.Row(cell.Row), candidates, solver, limits, step)
+			removed += removeCandidatesFromDifferent(cell, SolverHouse{GroupRow, cell.Row}, candidates, solver, limits, step)
 		}
 		if sameCol && solver.CanContinueStep(limits, step) {
-			removed += removeCandidatesFromDifferent(cell, solver.Col(cell.Col), candidates, solver, limits, step)
+			removed += removeCandidatesFromDifferent(cell, SolverHouse{GroupCol, cell.Col}, candidates, solver, limits, step)
+		}
+		for _, house := range sameHouses {
+			if solver.CanContinueStep(limits, step) {
+				removed += removeCandidatesFromDifferent(cell, SolverHouse{GroupHouse, house}, candidates, solver, limits, step)
+			}
 		}
 	}
 	return removed
 }
 
-func removeCandidatesFromDifferent(cell *Cell, group []*Cell, candidates Candidates, solver *Solver, limits SolveLimit, step *SolveStep) int {
+func removeCandidatesFromDifferent(cell *Cell, house SolverHouse, candidates Candidates, solver *Solver, limits SolveLimit, step *SolveStep) int {
 	removed := 0
+	group := solver.GroupAt(house.Group, house.Index)
 	hasOverlap := false
 	for _, other := range group {
 		if other.Id == cell.Id {
 			continue
 		}
-		if other.candidates.Value != candidates.Value && other.candidates.Overlaps(candidates) {
+		if !other.candidates.Equals(candidates) && other.candidates.Overlaps(candidates) {
 			hasOverlap = true
 			break
 		}
 	}
 	if hasOverlap {
 		solver.LogStep(step)
+		solver.LogPattern(sliceWhere(group, func(other *Cell) bool { return other.candidates.Equals(candidates) }), candidates.ToSlice()...)
+		solver.LogHouse(house.Group, house.Index)
 		for _, other := range group {
 			if other.Id == cell.Id {
 				continue
 			}
-			if other.candidates.Value != candidates.Value && other.candidates.Overlaps(candidates) {
+			if !other.candidates.Equals(candidates) && other.candidates.Overlaps(candidates) {
 				solver.LogBefore(other)
 				removed += other.candidates.Remove(candidates)
 				solver.LogAfter(other)
@@ -737,6 +1263,11 @@ func removeCandidatesFromDifferent(cell *Cell, group []*Cell, candidates Candida
 	return removed
 }
 
+// The cells which have any of the candidates.
+func getCellsWithCandidates(cells []*Cell, candidates Candidates) []*Cell {
+	return sliceWhere(cells, func(cell *Cell) bool { return cell.candidates.Overlaps(candidates) })
+}
+
 type candidateCells struct {
 	candidate int
 	cells     []*Cell
@@ -822,6 +1353,7 @@ var StepHiddenSubsets4 = CreateStepHiddenSubsets(4, "Hidden Quad", 7000, 5000)
 func doRemoveHiddenSubsetCandidates(solver *Solver, subsetSize int, limits SolveLimit, step *SolveStep) int {
 	dist := newDistribution(solver.Puzzle.Kind.Size())
 	tested := [3]Bitset{}
+	testedHouses := Bitset{}
 	removed := 0
 
 	for _, cell := range solver.Unsolved {
@@ -834,7 +1366,21 @@ func doRemoveHiddenSubsetCandidates(solver *Solver, subsetSize int, limits Solve
 
 				dist.reset(solver.Group(g, cell))
 
-				removed += doRemoveHiddenSubset(&dist, subsetSize, solver, limits, step)
+				removed += doRemoveHiddenSubset(&dist, SolverHouse{g, cellGroup}, subsetSize, solver, limits, step)
+
+				if !solver.CanContinueStep(limits, step) {
+					return removed
+				}
+			}
+		}
+
+		for _, house := range cell.Houses {
+			if !testedHouses.Has(house) {
+				testedHouses.Set(house, true)
+
+				dist.reset(solver.Houses[house])
+
+				removed += doRemoveHiddenSubset(&dist, SolverHouse{GroupHouse, house}, subsetSize, solver, limits, step)
 
 				if !solver.CanContinueStep(limits, step) {
 					return removed
@@ -846,7 +1392,7 @@ func doRemoveHiddenSubsetCandidates(solver *Solver, subsetSize int, limits Solve
 	return removed
 }
 
-func doRemoveHiddenSubset(dist *candidateDistribution, subsetSize int, solver *Solver, limits SolveLimit, step *SolveStep) int {
+func doRemoveHiddenSubset(dist *candidateDistribution, house SolverHouse, subsetSize int, solver *Solver, limits SolveLimit, step *SolveStep) int {
 	removed := 0
 	n := len(dist.candidates)
 
@@ -875,6 +1421,8 @@ func doRemoveHiddenSubset(dist *candidateDistribution, subsetSize int, solver *S
 				}
 				if hasOverlap {
 					solver.LogStep(step)
+					solver.LogPattern(list.cells[:list.size], matchCandidates.ToSlice()...)
+					solver.LogHouse(house.Group, house.Index)
 					for i := 0; i < list.size; i++ {
 						other := list.cells[i]
 						if other.candidates.Differences(matchCandidates) {
@@ -941,13 +1489,13 @@ func doSkyscraperRemoveGroup(solver *Solver, limits SolveLimit, step *SolveStep,
 					b1 := groupB[candidate].cells[1]
 
 					if a0.GetGroup(oppositeGroup) == b0.GetGroup(oppositeGroup) {
-						removed += removeCandidateInGroups(solver, limits, step, candidate+1, a1, b1)
+						removed += removeCandidateInGroups(solver, step, candidate+1, a1, b1, []*Cell{a0, a1, b0, b1})
 					} else if a0.GetGroup(oppositeGroup) == b1.GetGroup(oppositeGroup) {
-						removed += removeCandidateInGroups(solver, limits, step, candidate+1, a1, b0)
+						removed += removeCandidateInGroups(solver, step, candidate+1, a1, b0, []*Cell{a0, a1, b0, b1})
 					} else if a1.GetGroup(oppositeGroup) == b0.GetGroup(oppositeGroup) {
-						removed += removeCandidateInGroups(solver, limits, step, candidate+1, a0, b1)
+						removed += removeCandidateInGroups(solver, step, candidate+1, a0, b1, []*Cell{a0, a1, b0, b1})
 					} else if a1.GetGroup(oppositeGroup) == b1.GetGroup(oppositeGroup) {
-						removed += removeCandidateInGroups(solver, limits, step, candidate+1, a0, b0)
+						removed += removeCandidateInGroups(solver, step, candidate+1, a0, b0, []*Cell{a0, a1, b0, b1})
 					}
 					if !solver.CanContinueStep(limits, step) {
 						return removed
@@ -960,24 +1508,106 @@ func doSkyscraperRemoveGroup(solver *Solver, limits SolveLimit, step *SolveStep,
 	return removed
 }
 
-func removeCandidateInGroups(solver *Solver, limits SolveLimit, step *SolveStep, candidate int, a *Cell, b *Cell) int {
-	removed := 0
+func removeCandidateInGroups(solver *Solver, step *SolveStep, candidate int, a *Cell, b *Cell, pattern []*Cell) int {
+	eliminations := make([]*Cell, 0)
 
 	for _, cell := range solver.Unsolved {
 		if cell.HasCandidate(candidate) && cell.InGroup(a) && cell.InGroup(b) {
+			eliminations = append(eliminations, cell)
+		}
+	}
+
+	return removeCandidateFromCells(solver, step, candidate, eliminations, pattern)
+}
+
+// Removes the candidate from the cells as one step of the pattern found in the houses.
+func removeCandidateFromCells(solver *Solver, step *SolveStep, candidate int, cells []*Cell, pattern []*Cell, houses ...SolverHouse) int {
+	removed := 0
+	for _, cell := range cells {
+		if !cell.HasCandidate(candidate) {
+			continue
+		}
+		if removed == 0 {
 			solver.LogStep(step)
-			solver.LogBefore(cell)
-			cell.RemoveCandidate(candidate)
-			removed++
-			solver.LogAfter(cell)
+			solver.LogPattern(pattern, candidate)
+			for _, house := range houses {
+				solver.LogHouse(house.Group, house.Index)
+			}
+		}
+		solver.LogBefore(cell)
+		cell.RemoveCandidate(candidate)
+		solver.LogAfter(cell)
+		removed++
+	}
+	return removed
+}
 
-			if !solver.CanContinueStep(limits, step) {
-				return removed
+// Removes the candidate from every cell which sees all of the given cells as one step of the pattern.
+func removeCandidateSeenByAll(solver *Solver, step *SolveStep, candidate int, seen []*Cell, pattern []candidateNode) int {
+	return removeCandidateNodes(solver, step, getCandidateSeenByAll(solver, candidate, seen, nil), pattern)
+}
+
+// Appends the candidate in every cell which sees all of the given cells to the eliminations.
+func getCandidateSeenByAll(solver *Solver, candidate int, seen []*Cell, eliminations []candidateNode) []candidateNode {
+	cells := solver.Unsolved
+	if len(seen) > 0 {
+		// A cell which sees all of the cells must see the first one, through a house or a link.
+		first := seen[0]
+		// whether the cell is in the box, row, column, or the first extra houses of the first cell
+		inHouses := func(cell *Cell, extra int) bool {
+			if cell.Box == first.Box || cell.Row == first.Row || cell.Col == first.Col {
+				return true
+			}
+			for _, house := range first.Houses[:extra] {
+				if sliceIndex(cell.Houses, func(other int) bool { return other == house }) != -1 {
+					return true
+				}
+			}
+			return false
+		}
+		cells = make([]*Cell, 0, len(solver.Boxs[first.Box])*3+len(first.Links))
+		cells = append(cells, solver.Boxs[first.Box]...)
+		for _, cell := range solver.Rows[first.Row] {
+			if cell.Box != first.Box {
+				cells = append(cells, cell)
+			}
+		}
+		for _, cell := range solver.Cols[first.Col] {
+			if cell.Box != first.Box {
+				cells = append(cells, cell)
+			}
+		}
+		for i, house := range first.Houses {
+			for _, cell := range solver.Houses[house] {
+				if !inHouses(cell, i) {
+					cells = append(cells, cell)
+				}
+			}
+		}
+		for _, link := range first.Links {
+			if cell := &solver.Puzzle.Cells[link]; cell.Empty() && !inHouses(cell, len(first.Houses)) {
+				cells = append(cells, cell)
 			}
 		}
 	}
 
-	return removed
+	for _, cell := range cells {
+		if !cell.HasCandidate(candidate) {
+			continue
+		}
+		seesAll := true
+		for _, other := range seen {
+			if !cell.InGroup(other) {
+				seesAll = false
+				break
+			}
+		}
+		if seesAll {
+			eliminations = append(eliminations, candidateNode{cell, candidate})
+		}
+	}
+
+	return eliminations
 }
 
 func getGroupCandidateDistributions(solver *Solver, groupIndex Group) []*candidateDistribution {
@@ -1036,13 +1666,13 @@ func do2StringKite(solver *Solver, limits SolveLimit, step *SolveStep) int {
 					c1 := colCands.cells[1]
 
 					if r0.InGroup(c0) {
-						removed += removeCandidateInGroups(solver, limits, step, candidate+1, r1, c1)
+						removed += removeCandidateInGroups(solver, step, candidate+1, r1, c1, []*Cell{r0, r1, c0, c1})
 					} else if r0.InGroup(c1) {
-						removed += removeCandidateInGroups(solver, limits, step, candidate+1, r1, c0)
+						removed += removeCandidateInGroups(solver, step, candidate+1, r1, c0, []*Cell{r0, r1, c0, c1})
 					} else if r1.InGroup(c0) {
-						removed += removeCandidateInGroups(solver, limits, step, candidate+1, r0, c1)
+						removed += removeCandidateInGroups(solver, step, candidate+1, r0, c1, []*Cell{r0, r1, c0, c1})
 					} else if r1.InGroup(c1) {
-						removed += removeCandidateInGroups(solver, limits, step, candidate+1, r0, c0)
+						removed += removeCandidateInGroups(solver, step, candidate+1, r0, c0, []*Cell{r0, r1, c0, c1})
 					}
 					if !solver.CanContinueStep(limits, step) {
 						return removed
@@ -1084,49 +1714,34 @@ func doEmptyRectangle(solver *Solver, limits SolveLimit, step *SolveStep) int {
 			boxTested.Set(cell.Box, true)
 
 			getEmptyRectangles(solver.Box(cell.Box), func(candidate, col, row int) bool {
+				boxCandidate := Candidates{}
+				boxCandidate.Set(candidate, true)
+				boxCells := getCellsWithCandidates(solver.Box(cell.Box), boxCandidate)
+
 				can := false
 				can = findPerpendicularPair(solver, candidate, row, GroupRow, cell.Box, func(groupFound, otherGroup *Cell) bool {
-					dual := countCandidateInGroup(solver, candidate, otherGroup.Row, GroupRow) == 2
-					check := removeCandidate(solver, limits, step, col, otherGroup.Row, candidate)
-					if check {
-						removed++
-						if !solver.CanContinueStep(limits, step) {
-							return false
-						}
-						if dual {
-							check = removeCandidate(solver, limits, step, groupFound.Col, groupFound.Row, candidate)
-							if check {
-								removed++
-								if !solver.CanContinueStep(limits, step) {
-									return false
-								}
-							}
-						}
+					// An irregular box can leave the corner of the rectangle outside of it.
+					if groupFound.Col == col {
+						return true
 					}
-					return true
+					pattern := append(sliceClone(boxCells), groupFound, otherGroup)
+					dual := countCandidateInGroup(solver, candidate, otherGroup.Row, GroupRow) == 2
+					count := removeEmptyRectangle(solver, step, candidate, cell.Box, solver.Puzzle.Get(col, otherGroup.Row), groupFound, dual, pattern)
+					removed += count
+					return count == 0 || solver.CanContinueStep(limits, step)
 				})
 				if !can {
 					return false
 				}
 				can = findPerpendicularPair(solver, candidate, col, GroupCol, cell.Box, func(groupFound, otherGroup *Cell) bool {
-					dual := countCandidateInGroup(solver, candidate, otherGroup.Col, GroupCol) == 2
-					check := removeCandidate(solver, limits, step, otherGroup.Col, row, candidate)
-					if check {
-						removed++
-						if !solver.CanContinueStep(limits, step) {
-							return false
-						}
-						if dual {
-							check = removeCandidate(solver, limits, step, groupFound.Col, groupFound.Row, candidate)
-							if check {
-								removed++
-								if !solver.CanContinueStep(limits, step) {
-									return false
-								}
-							}
-						}
+					if groupFound.Row == row {
+						return true
 					}
-					return true
+					pattern := append(sliceClone(boxCells), groupFound, otherGroup)
+					dual := countCandidateInGroup(solver, candidate, otherGroup.Col, GroupCol) == 2
+					count := removeEmptyRectangle(solver, step, candidate, cell.Box, solver.Puzzle.Get(otherGroup.Col, row), groupFound, dual, pattern)
+					removed += count
+					return count == 0 || solver.CanContinueStep(limits, step)
 				})
 				return can
 			})
@@ -1136,6 +1751,20 @@ func doEmptyRectangle(solver *Solver, limits SolveLimit, step *SolveStep) int {
 	return removed
 }
 
+// Removes the candidate from the intersection of an empty rectangle, and from the end of the pair as well when
+// it's dual, as one step.
+func removeEmptyRectangle(solver *Solver, step *SolveStep, candidate int, box int, inter *Cell, groupFound *Cell, dual bool, pattern []*Cell) int {
+	if !canRemoveOutsideBox(inter, candidate, box) {
+		return 0
+	}
+	eliminations := []*Cell{inter}
+	found := solver.Puzzle.Get(groupFound.Col, groupFound.Row)
+	if dual && canRemoveOutsideBox(found, candidate, box) {
+		eliminations = append(eliminations, found)
+	}
+	return removeCandidateFromCells(solver, step, candidate, eliminations, pattern, SolverHouse{GroupBox, box})
+}
+
 func getEmptyRectangles(box []*Cell, onEmptyRectangle func(candidate int, col int, row int) bool) {
 	remaining := Candidates{}
 	minRow := box[0].Row
@@ -1215,16 +1844,9 @@ func findPerpendicularPair(solver *Solver, candidate int, groupSearch int, group
 	return true
 }
 
-func removeCandidate(solver *Solver, limit SolveLimit, step *SolveStep, col int, row int, candidate int) bool {
-	inter := solver.Puzzle.Get(col, row)
-	canRemove := inter.Empty() && inter.HasCandidate(candidate)
-	if canRemove {
-		solver.LogStep(step)
-		solver.LogBefore(inter)
-		inter.RemoveCandidate(candidate)
-		solver.LogAfter(inter)
-	}
-	return canRemove
+// Returns whether the candidate can be removed from the cell outside of the box.
+func canRemoveOutsideBox(cell *Cell, candidate int, box int) bool {
+	return cell.Empty() && cell.HasCandidate(candidate) && cell.Box != box
 }
 
 func countCandidateInGroup(solver *Solver, candidate int, groupSearch int, groupType Group) int {
@@ -1310,30 +1932,35 @@ func doBasicFishGroups(solver *Solver, limits SolveLimit, step *SolveStep, setSi
 			for combs.next(set) {
 				rowsHit := Bitset{}
 				colsHit := Bitset{}
+				pattern := make([]*Cell, 0, setSize*setSize)
 				for _, candCells := range set {
 					for k := 0; k < candCells.size; k++ {
 						c := candCells.cells[k]
 						rowsHit.Set(c.Row, true)
 						colsHit.Set(c.Col, true)
+						pattern = append(pattern, c)
 					}
 				}
 				if rowsHit.Count == setSize && colsHit.Count == setSize {
+					eliminations := make([]*Cell, 0)
 					for cellIndex := range solver.Puzzle.Cells {
 						cell := &solver.Puzzle.Cells[cellIndex]
 						inColumn := colsHit.Has(cell.Col)
 						inRow := rowsHit.Has(cell.Row)
 						if cell.HasCandidate(candidate) && ((inRow && !inColumn) || (!inRow && inColumn)) {
-							solver.LogStep(step)
-							solver.LogBefore(cell)
-							cell.RemoveCandidate(candidate)
-							removed++
-							solver.LogAfter(cell)
-
-							if !solver.CanContinueStep(limits, step) {
-								return removed
-							}
+							eliminations = append(eliminations, cell)
 						}
 					}
+					houses := make([]SolverHouse, 0, setSize)
+					for _, candCells := range set {
+						houses = append(houses, SolverHouse{groupType, candCells.cells[0].GetGroup(groupType)})
+					}
+					count := removeCandidateFromCells(solver, step, candidate, eliminations, pattern, houses...)
+					removed += count
+
+					if count > 0 && !solver.CanContinueStep(limits, step) {
+						return removed
+					}
 					break
 				}
 			}
@@ -1342,3 +1969,648 @@ func doBasicFishGroups(solver *Solver, limits SolveLimit, step *SolveStep, setSi
 
 	return removed
 }
+
+// ==================================================
+// Step: Finned & Sashimi Fish
+//		http://hodoku.sourceforge.net/en/tech_fishfs.php
+// ==================================================
+func CreateStepFinnedFish(setSize int, sashimi bool, technique string, firstCost int, subsequentCost int) *SolveStep {
+	return createStepFish(setSize, fishRowsAndCols, true, technique, firstCost, subsequentCost, func(f *fish) bool {
+		return f.shape() == fishShapeBasic && len(f.fins) > 0 && f.isSashimi() == sashimi
+	})
+}
+
+var StepFinnedXWing = CreateStepFinnedFish(2, false, "Finned X-Wing", 3200, 2000)
+var StepSashimiXWing = CreateStepFinnedFish(2, true, "Sashimi X-Wing", 3400, 2200)
+var StepFinnedSwordfish = CreateStepFinnedFish(3, false, "Finned Swordfish", 8400, 6400)
+var StepSashimiSwordfish = CreateStepFinnedFish(3, true, "Sashimi Swordfish", 8600, 6600)
+var StepFinnedJellyfish = CreateStepFinnedFish(4, false, "Finned Jellyfish", 10500, 8500)
+var StepSashimiJellyfish = CreateStepFinnedFish(4, true, "Sashimi Jellyfish", 10700, 8700)
+
+// ==================================================
+// Step: Franken & Mutant Fish
+//		http://hodoku.sourceforge.net/en/tech_fishc.php
+// ==================================================
+func CreateStepFrankenFish(setSize int, finned bool, technique string, firstCost int, subsequentCost int) *SolveStep {
+	return createStepFish(setSize, fishFranken, finned, technique, firstCost, subsequentCost, func(f *fish) bool {
+		return f.shape() == fishShapeFranken && (len(f.fins) > 0) == finned
+	})
+}
+
+func CreateStepMutantFish(setSize int, finned bool, technique string, firstCost int, subsequentCost int) *SolveStep {
+	return createStepFish(setSize, fishMutant, finned, technique, firstCost, subsequentCost, func(f *fish) bool {
+		return f.shape() == fishShapeMutant && (len(f.fins) > 0) == finned
+	})
+}
+
+var StepFrankenXWing = CreateStepFrankenFish(2, false, "Franken X-Wing", 3600, 2400)
+var StepFinnedFrankenXWing = CreateStepFrankenFish(2, true, "Finned Franken X-Wing", 3800, 2600)
+var StepFrankenSwordfish = CreateStepFrankenFish(3, false, "Franken Swordfish", 8800, 6800)
+var StepFinnedFrankenSwordfish = CreateStepFrankenFish(3, true, "Finned Franken Swordfish", 9000, 7000)
+var StepFrankenJellyfish = CreateStepFrankenFish(4, false, "Franken Jellyfish", 11000, 9000)
+var StepFinnedFrankenJellyfish = CreateStepFrankenFish(4, true, "Finned Franken Jellyfish", 11200, 9200)
+var StepMutantXWing = CreateStepMutantFish(2, false, "Mutant X-Wing", 4000, 2800)
+var StepFinnedMutantXWing = CreateStepMutantFish(2, true, "Finned Mutant X-Wing", 4200, 3000)
+var StepMutantSwordfish = CreateStepMutantFish(3, false, "Mutant Swordfish", 9400, 7400)
+var StepFinnedMutantSwordfish = CreateStepMutantFish(3, true, "Finned Mutant Swordfish", 9600, 7600)
+var StepMutantJellyfish = CreateStepMutantFish(4, false, "Mutant Jellyfish", 11600, 9600)
+var StepFinnedMutantJellyfish = CreateStepMutantFish(4, true, "Finned Mutant Jellyfish", 11800, 9800)
+
+// The groups the base and cover houses of a fish can be chosen from.
+type fishGroups struct {
+	base  []Group
+	cover []Group
+}
+
+var fishRowsAndCols = []fishGroups{
+	{base: []Group{GroupRow}, cover: []Group{GroupCol}},
+	{base: []Group{GroupCol}, cover: []Group{GroupRow}},
+}
+var fishFranken = []fishGroups{
+	{base: []Group{GroupRow, GroupBox}, cover: []Group{GroupCol, GroupBox}},
+	{base: []Group{GroupCol, GroupBox}, cover: []Group{GroupRow, GroupBox}},
+}
+var fishMutant = []fishGroups{
+	{base: []Group{GroupRow, GroupCol, GroupBox}, cover: []Group{GroupRow, GroupCol, GroupBox}},
+}
+
+func createStepFish(setSize int, groups []fishGroups, finned bool, technique string, firstCost int, subsequentCost int, matches func(f *fish) bool) *SolveStep {
+	return &SolveStep{
+		Technique:      technique,
+		FirstCost:      firstCost,
+		SubsequentCost: subsequentCost,
+		Logic: func(solver *Solver, limits SolveLimit, step *SolveStep) (int, bool) {
+			removed := false
+			if solver.CanContinueStep(limits, step) {
+				maxFins := 0
+				if finned {
+					maxFins = solver.Puzzle.Kind.Size()
+				}
+				removed = doFish(solver, limits, step, setSize, groups, maxFins, matches) > 0
+			}
+			return 0, removed
+		},
+	}
+}
+
+// A fish where the candidates in the base houses are covered by the same number of cover houses
+// except for a few fins that all share a box. Only candidates in the cover houses that see every
+// fin can be eliminated. The fish is sashimi when a base house would have one or no candidates
+// left without its fins. Franken fish can use boxes for base or cover houses and mutant fish can
+// use any mix of rows, columns, and boxes.
+func doFish(solver *Solver, limits SolveLimit, step *SolveStep, setSize int, groups []fishGroups, maxFins int, matches func(f *fish) bool) int {
+	size := solver.Puzzle.Kind.Size()
+	removed := 0
+
+	for _, group := range groups {
+		for candidate := 1; candidate <= size; candidate++ {
+			completed := findFish(solver, candidate, setSize, group.base, group.cover, maxFins, func(f *fish) bool {
+				if matches(f) {
+					removed += doFishEliminations(solver, step, f)
+				}
+				return solver.CanContinueStep(limits, step)
+			})
+			if !completed {
+				return removed
+			}
+		}
+	}
+
+	return removed
+}
+
+// ==================================================
+// Step: Siamese Fish
+//		http://hodoku.sourceforge.net/en/tech_fishc.php
+// ==================================================
+func CreateStepSiameseFish(setSize int, technique string, firstCost int, subsequentCost int) *SolveStep {
+	return &SolveStep{
+		Technique:      technique,
+		FirstCost:      firstCost,
+		SubsequentCost: subsequentCost,
+		Logic: func(solver *Solver, limits SolveLimit, step *SolveStep) (int, bool) {
+			removed := false
+			if solver.CanContinueStep(limits, step) {
+				removed = doSiameseFish(solver, limits, step, setSize) > 0
+			}
+			return 0, removed
+		},
+	}
+}
+
+var StepSiameseXWing = CreateStepSiameseFish(2, "Siamese X-Wing", 3500, 2300)
+var StepSiameseSwordfish = CreateStepSiameseFish(3, "Siamese Swordfish", 8700, 6700)
+var StepSiameseJellyfish = CreateStepSiameseFish(4, "Siamese Jellyfish", 10800, 8800)
+
+// A finned fish found during a search, kept around so it can be paired with another.
+type siameseFish struct {
+	base         []fishHouse
+	cover        []fishHouse
+	fins         []*Cell
+	cells        []*Cell
+	eliminations []*Cell
+}
+
+// Two finned (or sashimi) fish with the same base houses but different cover houses and fins
+// are both true, so their eliminations are made together. Only pairs which eliminate more than
+// either fish would alone are counted.
+func doSiameseFish(solver *Solver, limits SolveLimit, step *SolveStep, setSize int) int {
+	size := solver.Puzzle.Kind.Size()
+	removed := 0
+
+	for _, group := range fishRowsAndCols {
+		for candidate := 1; candidate <= size; candidate++ {
+			found := make([]siameseFish, 0)
+			findFish(solver, candidate, setSize, group.base, group.cover, size, func(f *fish) bool {
+				if len(f.fins) > 0 {
+					eliminations := f.eliminations()
+					if len(eliminations) > 0 {
+						found = append(found, siameseFish{
+							base:         sliceClone(f.base),
+							cover:        sliceClone(f.cover),
+							fins:         sliceClone(f.fins),
+							cells:        sliceClone(f.cells),
+							eliminations: eliminations,
+						})
+					}
+				}
+				return true
+			})
+
+			for i := 0; i < len(found); i++ {
+				for j := i + 1; j < len(found); j++ {
+					a := found[i]
+					b := found[j]
+					if !sameFishHouses(a.base, b.base) || sameFishHouses(a.cover, b.cover) || sameCells(a.fins, b.fins) {
+						continue
+					}
+					eliminations := make([]*Cell, 0, len(a.eliminations)+len(b.eliminations))
+					for _, cell := range append(sliceClone(a.eliminations), b.eliminations...) {
+						if cell.HasCandidate(candidate) && sliceIndex(eliminations, func(other *Cell) bool { return other == cell }) == -1 {
+							eliminations = append(eliminations, cell)
+						}
+					}
+					if len(eliminations) <= len(a.eliminations) || len(eliminations) <= len(b.eliminations) {
+						continue
+					}
+					solver.LogStep(step)
+					solver.LogPattern(a.cells, candidate)
+					logFishHouses(solver, a.base)
+					for _, cell := range eliminations {
+						solver.LogBefore(cell)
+						cell.RemoveCandidate(candidate)
+						solver.LogAfter(cell)
+					}
+					removed += len(eliminations)
+
+					if !solver.CanContinueStep(limits, step) {
+						return removed
+					}
+				}
+			}
+		}
+	}
+
+	return removed
+}
+
+func sameFishHouses(a []fishHouse, b []fishHouse) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, house := range a {
+		if sliceIndex(b, func(other fishHouse) bool { return other.group == house.group && other.index == house.index }) == -1 {
+			return false
+		}
+	}
+	return true
+}
+
+func sameCells(a []*Cell, b []*Cell) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, cell := range a {
+		if sliceIndex(b, func(other *Cell) bool { return other == cell }) == -1 {
+			return false
+		}
+	}
+	return true
+}
+
+// A house (row, column, or box) that is part of a fish.
+type fishHouse struct {
+	group Group
+	index int
+	// The unsolved cells in the house which have the fish candidate.
+	cells []*Cell
+}
+
+// A set of base houses for a candidate where all candidates in the base houses are in the cover houses
+// except for the fins.
+type fish struct {
+	candidate int
+	base      []fishHouse
+	cover     []fishHouse
+	fins      []*Cell
+	// The candidates in the base houses.
+	cells []*Cell
+	// How many base houses & cover houses each cell (by id) is in.
+	inBase  []int
+	inCover []int
+}
+
+// The kind of fish based on the groups of its base and cover houses.
+type fishShape int
+
+const (
+	fishShapeBasic fishShape = iota
+	fishShapeFranken
+	fishShapeMutant
+)
+
+// Basic fish only use rows for base houses & columns for cover houses (or the reverse), franken fish
+// may also use boxes on either side, and every other fish is a mutant.
+func (f *fish) shape() fishShape {
+	base := [GroupBox + 1]bool{}
+	cover := [GroupBox + 1]bool{}
+	for _, house := range f.base {
+		base[house.group] = true
+	}
+	for _, house := range f.cover {
+		cover[house.group] = true
+	}
+
+	lines := (!base[GroupCol] && !cover[GroupRow]) || (!base[GroupRow] && !cover[GroupCol])
+	if lines && !base[GroupBox] && !cover[GroupBox] {
+		return fishShapeBasic
+	}
+	if lines {
+		return fishShapeFranken
+	}
+	return fishShapeMutant
+}
+
+func (f *fish) hasHouse(group Group, index int) bool {
+	for _, house := range f.base {
+		if house.group == group && house.index == index {
+			return true
+		}
+	}
+	for _, house := range f.cover {
+		if house.group == group && house.index == index {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns whether a base house has one or no candidates that aren't fins.
+func (f *fish) isSashimi() bool {
+	for _, house := range f.base {
+		covered := 0
+		for _, cell := range house.cells {
+			if f.inCover[cell.Id] > 0 {
+				covered++
+			}
+		}
+		if covered <= 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// The cells in the cover houses which see every fin and can have the candidate removed. These are
+// outside the base houses or in more than one cover house (cannibalistic).
+func (f *fish) eliminations() []*Cell {
+	eliminations := make([]*Cell, 0)
+	for _, house := range f.cover {
+		for _, cell := range house.cells {
+			if !cell.HasCandidate(f.candidate) || (f.inBase[cell.Id] > 0 && f.inCover[cell.Id] < 2) || sliceIndex(eliminations, func(other *Cell) bool { return other == cell }) != -1 {
+				continue
+			}
+			seesFins := true
+			for _, fin := range f.fins {
+				if !cell.InGroup(fin) {
+					seesFins = false
+					break
+				}
+			}
+			if seesFins {
+				eliminations = append(eliminations, cell)
+			}
+		}
+	}
+	return eliminations
+}
+
+// Adds the base houses of a fish to the current batch's deduction.
+func logFishHouses(solver *Solver, base []fishHouse) {
+	for _, house := range base {
+		solver.LogHouse(house.group, house.index)
+	}
+}
+
+func doFishEliminations(solver *Solver, step *SolveStep, f *fish) int {
+	eliminations := f.eliminations()
+	if len(eliminations) > 0 {
+		solver.LogStep(step)
+		solver.LogPattern(f.cells, f.candidate)
+		logFishHouses(solver, f.base)
+		for _, cell := range eliminations {
+			solver.LogBefore(cell)
+			cell.RemoveCandidate(f.candidate)
+			solver.LogAfter(cell)
+		}
+	}
+	return len(eliminations)
+}
+
+// The most cells a house in one group can share with a different house in another group.
+func getGroupOverlap(kind *Kind, a Group, b Group) int {
+	if a == b {
+		return 0
+	}
+	if a == GroupBox {
+		return kind.BoxOverlap(b)
+	}
+	if b == GroupBox {
+		return kind.BoxOverlap(a)
+	}
+	return 1
+}
+
+// Returns every house in the group with the unsolved cells that have the candidate, the cells of all the
+// houses share one slice since a search asks for them many times.
+func getFishHouses(solver *Solver, candidate int, group Group) []fishHouse {
+	size := solver.Puzzle.Kind.Size()
+	houses := make([]fishHouse, size)
+	cells := make([]*Cell, 0, size*size)
+	for index := range houses {
+		start := len(cells)
+		for _, cell := range solver.GroupAt(group, index) {
+			if cell.HasCandidate(candidate) {
+				cells = append(cells, cell)
+			}
+		}
+		houses[index] = fishHouse{group: group, index: index, cells: cells[start:len(cells):len(cells)]}
+	}
+	return houses
+}
+
+// Finds every fish of the given size for a candidate where the base houses are in baseGroups and the cover
+// houses are in coverGroups. The base houses don't share any candidates and each base house has at least one
+// covered candidate. Up to maxFins base candidates can be left uncovered as long as they are in the same box, and
+// when maxFins is above zero only fish with fins that could eliminate something are found.
+// The search stops when onFish returns false, and false is returned.
+func findFish(solver *Solver, candidate int, setSize int, baseGroups []Group, coverGroups []Group, maxFins int, onFish func(f *fish) bool) bool {
+	kind := solver.Puzzle.Kind
+	size := kind.Size()
+	groupHouses := make([][]fishHouse, GroupBox+1)
+	getHouses := func(group Group) []fishHouse {
+		if groupHouses[group] == nil {
+			groupHouses[group] = getFishHouses(solver, candidate, group)
+		}
+		return groupHouses[group]
+	}
+
+	houses := make([]fishHouse, 0, size*len(baseGroups))
+	for _, group := range baseGroups {
+		// a base house can't have more candidates than the cover houses and a fin box could share with it
+		maxCells := 0
+		for _, coverGroup := range coverGroups {
+			maxCells = Max(maxCells, getGroupOverlap(kind, group, coverGroup)*setSize)
+		}
+		if maxFins > 0 {
+			maxCells += getGroupOverlap(kind, group, GroupBox)
+		}
+		for _, house := range getHouses(group) {
+			if len(house.cells) > 0 && len(house.cells) <= maxCells {
+				houses = append(houses, house)
+			}
+		}
+	}
+	if len(houses) < setSize {
+		return true
+	}
+
+	covers := make([][]fishHouse, GroupBox+1)
+	for _, group := range coverGroups {
+		covers[group] = getHouses(group)
+	}
+
+	// with one cover group no base cell is covered twice, so a finned fish can only eliminate candidates
+	// in the fin box that aren't in the base houses
+	boxCandidates := make([]int, size)
+	if maxFins > 0 && len(coverGroups) == 1 {
+		for box, house := range getHouses(GroupBox) {
+			boxCandidates[box] = len(house.cells)
+		}
+	}
+
+	cellCount := len(solver.Puzzle.Cells)
+	f := fish{
+		candidate: candidate,
+		base:      make([]fishHouse, 0, setSize),
+		cover:     make([]fishHouse, 0, setSize),
+		fins:      make([]*Cell, 0, maxFins),
+		cells:     make([]*Cell, 0, size*setSize),
+		inBase:    make([]int, cellCount),
+		inCover:   make([]int, cellCount),
+	}
+
+	// the most base cells a single cover house can cover
+	maxCovered := 0
+	// the box any fins must be in (-1 for none) and the base cells with the ones in the fin box last
+	finBox := -1
+	order := make([]*Cell, 0, size*setSize)
+
+	onCovered := func() bool {
+		if len(f.cover) != setSize || (finBox != -1 && len(f.fins) == 0) {
+			return true
+		}
+		for _, fin := range f.fins {
+			if f.inCover[fin.Id] > 0 {
+				return true
+			}
+		}
+		for _, house := range f.base {
+			if sliceIndex(house.cells, func(cell *Cell) bool { return f.inCover[cell.Id] > 0 }) == -1 {
+				return true
+			}
+		}
+		return onFish(&f)
+	}
+
+	var chooseCover func(next int) bool
+	chooseCover = func(next int) bool {
+		for next < len(order) && f.inCover[order[next].Id] > 0 {
+			next++
+		}
+		if next == len(order) {
+			return onCovered()
+		}
+
+		cell := order[next]
+
+		if cell.Box != finBox {
+			// the remaining cover houses must be able to hold the uncovered cells outside the fin box
+			uncovered := 0
+			for _, other := range order[next:] {
+				if other.Box != finBox && f.inCover[other.Id] == 0 {
+					uncovered++
+				}
+			}
+			if uncovered > (setSize-len(f.cover))*maxCovered {
+				return true
+			}
+		}
+
+		if len(f.cover) < setSize {
+			for _, group := range coverGroups {
+				index := cell.GetGroup(group)
+				if f.hasHouse(group, index) {
+					continue
+				}
+				house := covers[group][index]
+				for _, covered := range house.cells {
+					f.inCover[covered.Id]++
+				}
+				f.cover = append(f.cover, house)
+
+				completed := chooseCover(next + 1)
+
+				f.cover = sliceRemoveLast(f.cover)
+				for _, covered := range house.cells {
+					f.inCover[covered.Id]--
+				}
+				if !completed {
+					return false
+				}
+			}
+		}
+
+		if cell.Box == finBox && len(f.fins) < maxFins {
+			f.fins = append(f.fins, cell)
+			completed := chooseCover(next + 1)
+			f.fins = sliceRemoveLast(f.fins)
+			if !completed {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	// how many base cells each cover house has, whether a house is a base house, and the base cells in each box
+	coverCounts := make([][]int, GroupBox+1)
+	isBase := make([][]bool, GroupBox+1)
+	for group := range coverCounts {
+		coverCounts[group] = make([]int, size)
+		isBase[group] = make([]bool, size)
+	}
+	boxCounts := make([]int, size)
+	topCovered := make([]int, setSize)
+
+	chooseCovers := func() bool {
+		// the most base cells the best cover houses could cover together
+		for i := range topCovered {
+			topCovered[i] = 0
+		}
+		maxCovered = 0
+		for _, group := range coverGroups {
+			for index, count := range coverCounts[group] {
+				if isBase[group][index] || count <= topCovered[setSize-1] {
+					continue
+				}
+				maxCovered = Max(maxCovered, count)
+				i := setSize - 1
+				for i > 0 && topCovered[i-1] < count {
+					topCovered[i] = topCovered[i-1]
+					i--
+				}
+				topCovered[i] = count
+			}
+		}
+		canCover := 0
+		for _, count := range topCovered {
+			canCover += count
+		}
+
+		for box := -1; box < size; box++ {
+			finned := 0
+			if box == -1 {
+				if maxFins > 0 {
+					continue
+				}
+			} else {
+				finned = boxCounts[box]
+				if finned == 0 || maxFins == 0 || boxCandidates[box] == finned {
+					continue
+				}
+			}
+			if len(f.cells)-finned > canCover {
+				continue
+			}
+			finBox = box
+			order = order[:0]
+			for _, cell := range f.cells {
+				if cell.Box != finBox {
+					order = append(order, cell)
+				}
+			}
+			for _, cell := range f.cells {
+				if cell.Box == finBox {
+					order = append(order, cell)
+				}
+			}
+			if !chooseCover(0) {
+				return false
+			}
+		}
+		return true
+	}
+
+	var chooseBase func(start int) bool
+	chooseBase = func(start int) bool {
+		if len(f.base) == setSize {
+			return chooseCovers()
+		}
+		for i := start; i <= len(houses)-(setSize-len(f.base)); i++ {
+			house := houses[i]
+			if sliceIndex(house.cells, func(cell *Cell) bool { return f.inBase[cell.Id] > 0 }) != -1 {
+				continue
+			}
+			for _, cell := range house.cells {
+				f.inBase[cell.Id]++
+				boxCounts[cell.Box]++
+				for _, group := range coverGroups {
+					coverCounts[group][cell.GetGroup(group)]++
+				}
+			}
+			isBase[house.group][house.index] = true
+			f.base = append(f.base, house)
+			f.cells = append(f.cells, house.cells...)
+
+			completed := chooseBase(i + 1)
+
+			f.cells = f.cells[:len(f.cells)-len(house.cells)]
+			f.base = sliceRemoveLast(f.base)
+			isBase[house.group][house.index] = false
+			for _, cell := range house.cells {
+				f.inBase[cell.Id]--
+				boxCounts[cell.Box]--
+				for _, group := range coverGroups {
+					coverCounts[group][cell.GetGroup(group)]--
+				}
+			}
+			if !completed {
+				return false
+			}
+		}
+		return true
+	}
+
+	return chooseBase(0)
+}
@@ -74,6 +74,7 @@ func TestCandidateRemoveSteps(t *testing.T) {
 		tests      []CandidateTest
 		solve      bool
 		solveSteps int
+		unique     bool
 	}{
 		{
 			puzzle: Classic.Create([][]int{
@@ -525,11 +526,11 @@ func TestCandidateRemoveSteps(t *testing.T) {
 					column: 2,
 					row:    5,
 					before: "[2 4 6]",
-					after:  "[4 6]",
+					after:  "[6]",
 				},
 			},
 			solve:      true,
-			solveSteps: 2,
+			solveSteps: 4,
 		},
 		{
 			puzzle: Classic.Create([][]int{
@@ -595,67 +596,954 @@ func TestCandidateRemoveSteps(t *testing.T) {
 					after:  "[2 4]",
 				},
 				{
-					column: 2,
-					row:    4,
-					before: "[1 4 6]",
-					after:  "[4 6]",
+					column: 2,
+					row:    4,
+					before: "[1 4 6]",
+					after:  "[4 6]",
+				},
+				{
+					column: 3,
+					row:    4,
+					before: "[1 2 6 7 8]",
+					after:  "[2 6 7 8]",
+				},
+				{
+					column: 6,
+					row:    4,
+					before: "[1 2 3 6 8]",
+					after:  "[2 3 6 8]",
+				},
+				{
+					column: 7,
+					row:    4,
+					before: "[1 3 4 6 7 8]",
+					after:  "[3 4 6 7 8]",
+				},
+				{
+					column: 8,
+					row:    4,
+					before: "[1 2 4]",
+					after:  "[2 4]",
+				},
+			},
+			solveSteps: 9,
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{1, 6, 0, 5, 4, 3, 0, 7, 0},
+				{0, 7, 8, 6, 0, 1, 4, 3, 5},
+				{4, 3, 5, 8, 0, 7, 6, 0, 1},
+				{7, 2, 0, 4, 5, 8, 0, 6, 9},
+				{6, 0, 0, 9, 1, 2, 0, 5, 7},
+				{0, 0, 0, 3, 7, 6, 0, 0, 4},
+				{0, 1, 6, 0, 3, 0, 0, 4, 0},
+				{3, 0, 0, 0, 8, 0, 0, 1, 6},
+				{0, 0, 7, 1, 6, 4, 5, 0, 3},
+			}),
+			step: StepSwordfish,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 7,
+					row:    5,
+					before: "[2 8]",
+					after:  "[8]",
+				},
+				{
+					column: 0,
+					row:    6,
+					before: "[2 5 8 9]",
+					after:  "[5 8 9]",
+				},
+			},
+			solveSteps: 5,
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{0, 0, 0, 1, 0, 0, 0, 2, 4},
+				{0, 0, 4, 0, 0, 6, 0, 9, 1},
+				{0, 7, 0, 9, 2, 4, 0, 6, 8},
+				{0, 1, 3, 5, 0, 0, 9, 7, 0},
+				{5, 0, 0, 0, 9, 0, 1, 3, 0},
+				{0, 9, 0, 0, 0, 0, 8, 4, 5},
+				{0, 0, 0, 0, 0, 9, 6, 0, 7},
+				{0, 8, 0, 0, 0, 0, 2, 0, 3},
+				{6, 5, 0, 2, 3, 0, 4, 0, 9},
+			}),
+			step: StepFinnedXWing,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 4,
+					row:    6,
+					before: "[1 4 5 8]",
+					after:  "[1 5 8]",
+				},
+			},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{1, 9, 6, 0, 3, 7, 0, 0, 2},
+				{4, 0, 0, 5, 9, 2, 0, 1, 6},
+				{2, 5, 0, 0, 1, 6, 9, 0, 0},
+				{5, 0, 1, 7, 6, 9, 0, 2, 0},
+				{9, 4, 0, 3, 2, 5, 6, 0, 1},
+				{6, 0, 2, 1, 4, 8, 0, 0, 9},
+				{8, 1, 9, 6, 5, 3, 2, 4, 7},
+				{7, 2, 5, 9, 8, 4, 1, 6, 3},
+				{3, 6, 4, 2, 7, 1, 8, 9, 5},
+			}),
+			step: StepSashimiSwordfish,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 1,
+					row:    1,
+					before: "[3 7 8]",
+					after:  "[7 8]",
+				},
+				{
+					column: 2,
+					row:    2,
+					before: "[3 7 8]",
+					after:  "[3 7]",
+				},
+			},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{6, 7, 1, 0, 0, 9, 3, 4, 8},
+				{4, 5, 9, 7, 8, 3, 2, 1, 6},
+				{3, 2, 8, 4, 0, 0, 7, 5, 9},
+				{2, 1, 0, 0, 0, 4, 0, 9, 3},
+				{0, 8, 0, 6, 0, 2, 1, 7, 0},
+				{0, 0, 0, 0, 0, 0, 0, 2, 0},
+				{8, 9, 0, 0, 4, 0, 5, 3, 7},
+				{7, 4, 0, 0, 0, 0, 9, 6, 1},
+				{1, 0, 0, 9, 0, 0, 4, 8, 2},
+			}),
+			step: StepSiameseSwordfish,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 3,
+					row:    7,
+					before: "[2 3 5 8]",
+					after:  "[2 3 8]",
+				},
+				{
+					column: 4,
+					row:    7,
+					before: "[2 3 5]",
+					after:  "[2 3]",
+				},
+				{
+					column: 4,
+					row:    8,
+					before: "[3 5 6 7]",
+					after:  "[3 6 7]",
+				},
+			},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{8, 6, 2, 0, 5, 0, 0, 0, 0},
+				{0, 0, 0, 6, 0, 0, 0, 8, 2},
+				{1, 0, 0, 2, 4, 8, 6, 0, 0},
+				{0, 0, 3, 4, 0, 0, 0, 0, 7},
+				{0, 0, 1, 5, 9, 0, 0, 4, 0},
+				{4, 5, 0, 0, 0, 2, 9, 0, 0},
+				{0, 0, 0, 0, 0, 5, 8, 0, 0},
+				{0, 0, 0, 0, 0, 3, 0, 0, 0},
+				{0, 2, 0, 0, 0, 4, 1, 0, 9},
+			}),
+			step: StepFrankenSwordfish,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 3,
+					row:    0,
+					before: "[1 3 7 9]",
+					after:  "[3 7 9]",
+				},
+				{
+					column: 5,
+					row:    0,
+					before: "[1 7 9]",
+					after:  "[7 9]",
+				},
+			},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{2, 4, 8, 1, 5, 3, 7, 9, 6},
+				{1, 0, 0, 9, 6, 4, 2, 5, 8},
+				{6, 9, 5, 2, 0, 0, 4, 1, 3},
+				{4, 5, 0, 6, 3, 0, 9, 8, 1},
+				{9, 0, 1, 0, 0, 5, 3, 6, 4},
+				{3, 8, 6, 4, 9, 1, 5, 7, 2},
+				{7, 0, 4, 5, 0, 6, 8, 0, 9},
+				{8, 0, 0, 0, 0, 9, 6, 4, 5},
+				{5, 6, 9, 0, 4, 0, 1, 0, 7},
+			}),
+			step: StepWWing,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 4,
+					row:    4,
+					before: "[2 7 8]",
+					after:  "[2 7]",
+				},
+			},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{0, 0, 5, 1, 4, 0, 0, 9, 0},
+				{0, 0, 8, 3, 0, 0, 0, 0, 0},
+				{0, 0, 1, 2, 8, 5, 0, 6, 7},
+				{0, 1, 3, 0, 5, 0, 2, 8, 4},
+				{4, 2, 0, 0, 3, 8, 0, 0, 0},
+				{8, 5, 6, 4, 1, 2, 9, 7, 3},
+				{6, 3, 0, 5, 0, 1, 0, 4, 0},
+				{1, 0, 4, 8, 0, 3, 0, 2, 5},
+				{5, 8, 0, 0, 0, 4, 1, 3, 0},
+			}),
+			step: StepXYWing,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 2,
+					row:    8,
+					before: "[2 7 9]",
+					after:  "[2 7]",
+				},
+			},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{0, 1, 0, 8, 6, 0, 0, 0, 2},
+				{0, 5, 0, 0, 7, 0, 8, 0, 6},
+				{8, 0, 0, 9, 4, 0, 0, 7, 0},
+				{0, 3, 0, 5, 2, 4, 6, 0, 8},
+				{4, 0, 0, 0, 8, 6, 0, 0, 0},
+				{0, 8, 0, 7, 0, 9, 4, 0, 0},
+				{0, 0, 8, 0, 0, 0, 0, 6, 0},
+				{0, 7, 0, 6, 0, 8, 0, 0, 4},
+				{0, 0, 2, 0, 5, 7, 0, 8, 1},
+			}),
+			step: StepXYZWing,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 6,
+					row:    2,
+					before: "[1 3 5]",
+					after:  "[1 5]",
+				},
+			},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{0, 0, 5, 1, 4, 0, 0, 9, 0},
+				{0, 0, 8, 3, 0, 0, 0, 0, 0},
+				{0, 0, 1, 2, 8, 5, 0, 6, 7},
+				{0, 1, 3, 0, 5, 0, 2, 8, 4},
+				{4, 2, 0, 0, 3, 8, 0, 0, 0},
+				{8, 5, 6, 4, 1, 2, 9, 7, 3},
+				{6, 3, 0, 5, 0, 1, 0, 4, 0},
+				{1, 0, 4, 8, 0, 3, 0, 2, 5},
+				{5, 8, 0, 0, 0, 4, 1, 3, 0},
+			}),
+			step: StepWXYZWing,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 0,
+					row:    0,
+					before: "[2 3 7]",
+					after:  "[2 3]",
+				},
+			},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{0, 1, 0, 0, 0, 9, 2, 0, 4},
+				{9, 0, 2, 0, 1, 0, 0, 6, 8},
+				{0, 0, 7, 2, 0, 0, 9, 1, 5},
+				{0, 2, 0, 0, 0, 5, 6, 0, 9},
+				{0, 8, 6, 1, 9, 2, 0, 4, 7},
+				{4, 0, 0, 0, 0, 0, 1, 0, 2},
+				{0, 0, 4, 0, 5, 1, 0, 0, 6},
+				{0, 0, 0, 0, 2, 0, 4, 0, 3},
+				{0, 0, 0, 0, 6, 4, 0, 0, 1},
+			}),
+			step: StepSimpleColoring,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 0,
+					row:    0,
+					before: "[3 5 6 8]",
+					after:  "[5 6 8]",
+				},
+			},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{0, 0, 0, 0, 0, 5, 9, 2, 0},
+				{1, 0, 0, 0, 9, 0, 8, 4, 0},
+				{9, 0, 0, 0, 8, 0, 7, 1, 0},
+				{0, 0, 3, 2, 5, 0, 1, 0, 9},
+				{0, 0, 0, 0, 0, 9, 5, 0, 2},
+				{2, 9, 5, 0, 0, 6, 3, 0, 4},
+				{0, 0, 6, 0, 0, 0, 0, 9, 7},
+				{7, 0, 0, 9, 6, 0, 0, 5, 8},
+				{0, 8, 9, 0, 2, 0, 6, 3, 1},
+			}),
+			step: StepMultiColoring,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 4,
+					row:    4,
+					before: "[1 3 4 7]",
+					after:  "[3 4 7]",
+				},
+				{
+					column: 4,
+					row:    6,
+					before: "[1 3 4]",
+					after:  "[3 4]",
+				},
+			},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{0, 0, 0, 0, 0, 0, 0, 8, 9},
+				{0, 0, 0, 1, 0, 0, 7, 0, 4},
+				{0, 5, 0, 8, 0, 0, 3, 0, 2},
+				{6, 3, 2, 4, 5, 8, 9, 7, 1},
+				{0, 0, 0, 0, 0, 0, 0, 0, 5},
+				{0, 7, 0, 0, 0, 9, 0, 0, 8},
+				{0, 4, 0, 9, 6, 1, 8, 2, 3},
+				{2, 9, 8, 7, 3, 4, 0, 0, 6},
+				{1, 6, 3, 5, 8, 2, 4, 9, 7},
+			}),
+			step: Step3DMedusa,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 6,
+					row:    0,
+					before: "[1 5 6]",
+					after:  "[1 5]",
+				},
+				{
+					column: 7,
+					row:    4,
+					before: "[3 4 6]",
+					after:  "[3 4]",
+				},
+				{
+					column: 7,
+					row:    5,
+					before: "[3 4 6]",
+					after:  "[3 4]",
+				},
+			},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{4, 9, 2, 7, 8, 1, 0, 6, 0},
+				{3, 8, 6, 2, 4, 5, 7, 9, 1},
+				{7, 5, 1, 0, 0, 3, 2, 4, 8},
+				{9, 0, 3, 0, 0, 8, 0, 5, 7},
+				{0, 0, 0, 0, 7, 0, 0, 0, 0},
+				{0, 6, 7, 4, 0, 0, 0, 0, 0},
+				{2, 3, 9, 0, 0, 0, 0, 0, 6},
+				{0, 0, 5, 0, 0, 0, 0, 0, 0},
+				{0, 7, 0, 0, 0, 0, 9, 3, 0},
+			}),
+			step: StepXChain,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 5,
+					row:    8,
+					before: "[2 4 6]",
+					after:  "[2 6]",
+				},
+			},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{4, 2, 0, 8, 5, 0, 0, 0, 0},
+				{0, 3, 1, 0, 7, 0, 8, 0, 5},
+				{8, 0, 0, 0, 0, 3, 0, 2, 4},
+				{7, 4, 2, 6, 0, 0, 5, 0, 3},
+				{5, 1, 6, 3, 0, 0, 0, 0, 0},
+				{3, 9, 8, 5, 2, 7, 6, 4, 1},
+				{2, 0, 0, 0, 0, 0, 4, 1, 8},
+				{0, 8, 0, 0, 0, 0, 0, 5, 0},
+				{0, 0, 0, 0, 8, 5, 9, 0, 0},
+			}),
+			step: StepXYChain,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 5,
+					row:    1,
+					before: "[2 4 6 9]",
+					after:  "[2 4]",
+				},
+				{
+					column: 3,
+					row:    1,
+					before: "[2 4 9]",
+					after:  "[2 4]",
+				},
+			},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{0, 1, 5, 9, 0, 4, 2, 7, 6},
+				{0, 4, 0, 2, 0, 7, 3, 1, 5},
+				{0, 0, 0, 5, 0, 1, 8, 4, 9},
+				{0, 0, 0, 7, 1, 6, 9, 3, 4},
+				{4, 9, 7, 8, 5, 3, 6, 2, 1},
+				{6, 3, 1, 4, 2, 9, 0, 0, 0},
+				{0, 2, 0, 1, 0, 5, 0, 6, 0},
+				{0, 0, 0, 3, 0, 8, 0, 9, 2},
+				{0, 0, 0, 6, 9, 2, 0, 0, 0},
+			}),
+			step: StepContinuousNiceLoop,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 2,
+					row:    6,
+					before: "[3 4 8 9]",
+					after:  "[3 8 9]",
+				},
+				{
+					column: 0,
+					row:    6,
+					before: "[3 7 8 9]",
+					after:  "[3 8 9]",
+				},
+				{
+					column: 8,
+					row:    6,
+					before: "[3 7 8]",
+					after:  "[3 8]",
+				},
+			},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{0, 1, 5, 9, 0, 4, 2, 7, 6},
+				{0, 4, 0, 2, 0, 7, 3, 1, 5},
+				{0, 0, 0, 5, 0, 1, 8, 4, 9},
+				{0, 0, 0, 7, 1, 6, 9, 3, 4},
+				{4, 9, 7, 8, 5, 3, 6, 2, 1},
+				{6, 3, 1, 4, 2, 9, 0, 0, 0},
+				{0, 2, 0, 1, 0, 5, 0, 6, 0},
+				{0, 0, 0, 3, 0, 8, 0, 9, 2},
+				{0, 0, 0, 6, 9, 2, 0, 0, 0},
+			}),
+			step: StepDiscontinuousNiceLoop,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 0,
+					row:    6,
+					before: "[3 7 8 9]",
+					after:  "[3 8 9]",
+				},
+				{
+					column: 2,
+					row:    6,
+					before: "[3 4 8 9]",
+					after:  "[3 8 9]",
+				},
+			},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{3, 0, 9, 7, 0, 5, 2, 6, 4},
+				{5, 7, 6, 4, 0, 0, 1, 8, 3},
+				{4, 0, 2, 0, 3, 0, 5, 9, 7},
+				{7, 5, 8, 3, 0, 0, 6, 1, 9},
+				{9, 3, 1, 0, 0, 7, 4, 2, 0},
+				{6, 2, 4, 0, 0, 0, 3, 7, 0},
+				{8, 9, 3, 2, 5, 1, 7, 4, 6},
+				{2, 0, 7, 9, 0, 3, 8, 5, 1},
+				{1, 0, 5, 8, 7, 0, 9, 3, 2},
+			}),
+			step: StepBUG1,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 4,
+					row:    5,
+					before: "[1 8 9]",
+					after:  "[]",
+					value:  8,
+				},
+			},
+			unique: true,
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{0, 2, 0, 0, 0, 5, 0, 9, 0},
+				{0, 6, 7, 0, 9, 2, 0, 5, 8},
+				{0, 5, 9, 0, 0, 0, 2, 0, 0},
+				{0, 8, 5, 0, 7, 9, 0, 0, 2},
+				{2, 9, 6, 5, 4, 3, 7, 8, 1},
+				{7, 4, 0, 2, 0, 8, 9, 0, 5},
+				{6, 7, 4, 9, 8, 1, 5, 2, 3},
+				{5, 3, 8, 0, 2, 0, 6, 1, 9},
+				{9, 1, 2, 3, 5, 6, 8, 0, 0},
+			}),
+			step: StepUniqueRectangle1,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 3,
+					row:    2,
+					before: "[1 4 6 7 8]",
+					after:  "[1 6 8]",
+				},
+			},
+			unique: true,
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{6, 1, 4, 7, 5, 3, 0, 9, 0},
+				{0, 2, 0, 1, 6, 8, 4, 7, 3},
+				{3, 7, 8, 0, 0, 0, 1, 0, 0},
+				{8, 4, 2, 6, 0, 5, 7, 0, 0},
+				{0, 3, 6, 0, 0, 1, 5, 2, 4},
+				{0, 5, 0, 0, 0, 4, 0, 0, 0},
+				{4, 0, 3, 0, 0, 0, 0, 0, 7},
+				{2, 8, 7, 0, 0, 6, 9, 4, 0},
+				{0, 0, 0, 0, 0, 7, 3, 8, 0},
+			}),
+			step: StepUniqueRectangle2,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 4,
+					row:    8,
+					before: "[1 2 4 9]",
+					after:  "[2 4 9]",
+				},
+				{
+					column: 8,
+					row:    8,
+					before: "[1 2 5 6]",
+					after:  "[2 5 6]",
+				},
+			},
+			unique: true,
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{6, 0, 8, 9, 0, 0, 0, 5, 2},
+				{5, 0, 1, 0, 6, 0, 0, 8, 9},
+				{0, 9, 2, 8, 0, 5, 1, 0, 0},
+				{3, 0, 9, 0, 8, 0, 6, 0, 5},
+				{0, 8, 5, 0, 2, 6, 0, 9, 1},
+				{1, 0, 6, 0, 5, 9, 8, 0, 0},
+				{2, 1, 4, 6, 9, 8, 5, 3, 7},
+				{9, 6, 7, 5, 4, 3, 2, 1, 8},
+				{8, 5, 3, 0, 0, 0, 9, 0, 0},
+			}),
+			step: StepUniqueRectangle3,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 0,
+					row:    2,
+					before: "[4 7]",
+					after:  "[4]",
+				},
+			},
+			unique: true,
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{6, 0, 8, 9, 0, 0, 0, 5, 2},
+				{5, 0, 1, 0, 6, 0, 0, 8, 9},
+				{0, 9, 2, 8, 0, 5, 1, 0, 0},
+				{3, 0, 9, 0, 8, 0, 6, 0, 5},
+				{0, 8, 5, 0, 2, 6, 0, 9, 1},
+				{1, 0, 6, 0, 5, 9, 8, 0, 0},
+				{2, 1, 4, 6, 9, 8, 5, 3, 7},
+				{9, 6, 7, 5, 4, 3, 2, 1, 8},
+				{8, 5, 3, 0, 0, 0, 9, 0, 0},
+			}),
+			step: StepUniqueRectangle4,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 7,
+					row:    2,
+					before: "[4 6 7]",
+					after:  "[6 7]",
+				},
+				{
+					column: 8,
+					row:    2,
+					before: "[3 4 6]",
+					after:  "[3 6]",
+				},
+			},
+			unique: true,
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{4, 0, 6, 1, 2, 0, 0, 5, 0},
+				{9, 0, 3, 0, 5, 6, 0, 1, 0},
+				{0, 0, 0, 7, 9, 0, 0, 6, 0},
+				{0, 0, 0, 6, 7, 9, 8, 3, 2},
+				{2, 3, 7, 0, 0, 5, 1, 9, 6},
+				{8, 6, 9, 3, 1, 2, 5, 7, 4},
+				{6, 0, 2, 9, 3, 1, 7, 0, 5},
+				{3, 9, 0, 5, 0, 7, 6, 2, 1},
+				{7, 1, 5, 2, 6, 0, 0, 0, 0},
+			}),
+			step: StepUniqueRectangle6,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 8,
+					row:    0,
+					before: "[3 7 8 9]",
+					after:  "[3 8 9]",
+				},
+				{
+					column: 1,
+					row:    1,
+					before: "[2 7 8]",
+					after:  "[2 8]",
+				},
+			},
+			unique: true,
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{2, 4, 8, 1, 5, 3, 7, 9, 6},
+				{1, 0, 0, 9, 6, 4, 2, 5, 8},
+				{6, 9, 5, 2, 0, 0, 4, 1, 3},
+				{4, 5, 0, 6, 3, 0, 9, 8, 1},
+				{9, 0, 1, 0, 0, 5, 3, 6, 4},
+				{3, 8, 6, 4, 9, 1, 5, 7, 2},
+				{7, 0, 4, 5, 0, 6, 8, 0, 9},
+				{8, 0, 0, 0, 0, 9, 6, 4, 5},
+				{5, 6, 9, 0, 4, 0, 1, 0, 7},
+			}),
+			step: StepHiddenRectangle,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 1,
+					row:    7,
+					before: "[1 2 3]",
+					after:  "[1 3]",
+				},
+			},
+			unique: true,
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{2, 0, 1, 7, 6, 5, 0, 0, 0},
+				{5, 3, 0, 4, 2, 0, 0, 0, 1},
+				{6, 4, 7, 1, 0, 0, 0, 2, 5},
+				{1, 0, 0, 6, 0, 7, 0, 5, 0},
+				{0, 0, 0, 0, 0, 2, 1, 0, 6},
+				{9, 6, 0, 3, 5, 1, 0, 0, 7},
+				{8, 0, 0, 2, 0, 0, 0, 9, 4},
+				{0, 0, 6, 5, 0, 0, 3, 0, 0},
+				{0, 0, 0, 0, 0, 0, 5, 0, 0},
+			}),
+			step: StepSueDeCoq,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 4,
+					row:    8,
+					before: "[1 3 4 7 8 9]",
+					after:  "[1 3 4 7]",
+				},
+				{
+					column: 5,
+					row:    8,
+					before: "[3 4 6 8 9]",
+					after:  "[3 4 6]",
+				},
+				{
+					column: 7,
+					row:    8,
+					before: "[1 6 7 8]",
+					after:  "[1 6 7]",
+				},
+			},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{0, 0, 0, 1, 0, 0, 0, 2, 4},
+				{0, 0, 4, 0, 0, 6, 0, 9, 1},
+				{0, 7, 0, 9, 2, 4, 0, 6, 8},
+				{0, 1, 3, 5, 0, 0, 9, 7, 0},
+				{5, 0, 0, 0, 9, 0, 1, 3, 0},
+				{0, 9, 0, 0, 0, 0, 8, 4, 5},
+				{0, 0, 0, 0, 0, 9, 6, 0, 7},
+				{0, 8, 0, 0, 0, 0, 2, 0, 3},
+				{6, 5, 0, 2, 3, 0, 4, 0, 9},
+			}),
+			step: StepALSXZ,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 4,
+					row:    7,
+					before: "[1 4 5 6 7]",
+					after:  "[4 5 6 7]",
+				},
+			},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{0, 0, 0, 1, 0, 0, 0, 2, 4},
+				{0, 0, 4, 0, 0, 6, 0, 9, 1},
+				{0, 7, 0, 9, 2, 4, 0, 6, 8},
+				{0, 1, 3, 5, 0, 0, 9, 7, 0},
+				{5, 0, 0, 0, 9, 0, 1, 3, 0},
+				{0, 9, 0, 0, 0, 0, 8, 4, 5},
+				{0, 0, 0, 0, 0, 9, 6, 0, 7},
+				{0, 8, 0, 0, 0, 0, 2, 0, 3},
+				{6, 5, 0, 2, 3, 0, 4, 0, 9},
+			}),
+			step: StepALSXYWing,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 0,
+					row:    7,
+					before: "[1 4 7 9]",
+					after:  "[4 7 9]",
+				},
+			},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{0, 0, 0, 0, 1, 0, 0, 8, 2},
+				{1, 0, 4, 0, 0, 0, 0, 7, 3},
+				{0, 0, 2, 0, 0, 7, 9, 0, 1},
+				{4, 1, 0, 2, 9, 0, 7, 0, 8},
+				{0, 2, 8, 0, 0, 5, 1, 0, 9},
+				{9, 0, 0, 0, 0, 1, 0, 2, 4},
+				{2, 6, 9, 1, 7, 0, 0, 0, 5},
+				{8, 4, 3, 6, 5, 9, 2, 1, 7},
+				{5, 7, 1, 0, 0, 0, 0, 9, 6},
+			}),
+			step: StepALSChain,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 3,
+					row:    5,
+					before: "[3 7 8]",
+					after:  "[7 8]",
+				},
+				{
+					column: 4,
+					row:    5,
+					before: "[3 6 8]",
+					after:  "[6 8]",
+				},
+			},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{8, 1, 0, 2, 0, 0, 0, 0, 0},
+				{2, 0, 6, 4, 0, 1, 3, 8, 0},
+				{9, 0, 0, 6, 8, 5, 2, 0, 1},
+				{0, 0, 8, 0, 0, 0, 1, 0, 0},
+				{7, 2, 0, 0, 1, 6, 0, 0, 0},
+				{3, 0, 1, 0, 5, 0, 6, 0, 0},
+				{0, 0, 0, 1, 0, 0, 7, 0, 0},
+				{4, 0, 0, 5, 0, 0, 0, 1, 3},
+				{1, 0, 7, 0, 4, 8, 0, 2, 0},
+			}),
+			step: StepDeathBlossom,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 2,
+					row:    0,
+					before: "[3 4 5]",
+					after:  "[4 5]",
+				},
+			},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{0, 0, 0, 0, 1, 0, 0, 8, 2},
+				{1, 0, 4, 0, 0, 0, 0, 7, 3},
+				{0, 0, 2, 0, 0, 7, 9, 0, 1},
+				{4, 1, 0, 2, 9, 0, 7, 0, 8},
+				{0, 2, 8, 0, 0, 5, 1, 0, 9},
+				{9, 0, 0, 0, 0, 1, 0, 2, 4},
+				{2, 6, 9, 1, 7, 0, 0, 0, 5},
+				{8, 4, 3, 6, 5, 9, 2, 1, 7},
+				{5, 7, 1, 0, 0, 0, 0, 9, 6},
+			}),
+			step: StepForcingChainContradiction,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 3,
+					row:    5,
+					before: "[3 7 8]",
+					after:  "[7 8]",
+				},
+			},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{0, 0, 0, 0, 1, 0, 0, 8, 2},
+				{1, 0, 4, 0, 0, 0, 0, 7, 3},
+				{0, 0, 2, 0, 0, 7, 9, 0, 1},
+				{4, 1, 0, 2, 9, 0, 7, 0, 8},
+				{0, 2, 8, 0, 0, 5, 1, 0, 9},
+				{9, 0, 0, 0, 0, 1, 0, 2, 4},
+				{2, 6, 9, 1, 7, 0, 0, 0, 5},
+				{8, 4, 3, 6, 5, 9, 2, 1, 7},
+				{5, 7, 1, 0, 0, 0, 0, 9, 6},
+			}),
+			step: StepCellForcingChain,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 3,
+					row:    5,
+					before: "[3 7 8]",
+					after:  "[7 8]",
 				},
 				{
-					column: 3,
-					row:    4,
-					before: "[1 2 6 7 8]",
-					after:  "[2 6 7 8]",
+					column: 4,
+					row:    5,
+					before: "[3 6 8]",
+					after:  "[6 8]",
 				},
+			},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{8, 1, 0, 2, 0, 0, 0, 0, 0},
+				{2, 0, 6, 4, 0, 1, 3, 8, 0},
+				{9, 0, 0, 6, 8, 5, 2, 0, 1},
+				{0, 0, 8, 0, 0, 0, 1, 0, 0},
+				{7, 2, 0, 0, 1, 6, 0, 0, 0},
+				{3, 0, 1, 0, 5, 0, 6, 0, 0},
+				{0, 0, 0, 1, 0, 0, 7, 0, 0},
+				{4, 0, 0, 5, 0, 0, 0, 1, 3},
+				{1, 0, 7, 0, 4, 8, 0, 2, 0},
+			}),
+			step: StepRegionForcingChain,
+			max:  1,
+			tests: []CandidateTest{
 				{
-					column: 6,
-					row:    4,
-					before: "[1 2 3 6 8]",
-					after:  "[2 3 6 8]",
+					column: 2,
+					row:    0,
+					before: "[3 4 5]",
+					after:  "[4 5]",
 				},
 				{
 					column: 7,
-					row:    4,
-					before: "[1 3 4 6 7 8]",
-					after:  "[3 4 6 7 8]",
+					row:    0,
+					before: "[4 5 6 7 9]",
+					after:  "[4 6 9]",
+				},
+			},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{0, 7, 0, 4, 1, 2, 0, 0, 3},
+				{3, 0, 0, 9, 5, 6, 0, 0, 7},
+				{9, 0, 0, 7, 3, 8, 1, 0, 5},
+				{0, 0, 8, 0, 0, 0, 0, 3, 4},
+				{4, 0, 0, 8, 0, 0, 0, 0, 0},
+				{0, 0, 9, 3, 4, 0, 0, 8, 1},
+				{0, 4, 0, 0, 8, 0, 0, 5, 0},
+				{0, 9, 0, 0, 0, 4, 3, 1, 8},
+				{0, 0, 0, 1, 9, 0, 4, 0, 2},
+			}),
+			step: StepDigitForcingChain,
+			max:  1,
+			tests: []CandidateTest{
+				{
+					column: 5,
+					row:    3,
+					before: "[1 5 7 9]",
+					after:  "[1 9]",
 				},
 				{
-					column: 8,
+					column: 5,
 					row:    4,
-					before: "[1 2 4]",
-					after:  "[2 4]",
+					before: "[1 5 7 9]",
+					after:  "[1 9]",
 				},
 			},
-			solveSteps: 9,
 		},
 		{
 			puzzle: Classic.Create([][]int{
-				{1, 6, 0, 5, 4, 3, 0, 7, 0},
-				{0, 7, 8, 6, 0, 1, 4, 3, 5},
-				{4, 3, 5, 8, 0, 7, 6, 0, 1},
-				{7, 2, 0, 4, 5, 8, 0, 6, 9},
-				{6, 0, 0, 9, 1, 2, 0, 5, 7},
-				{0, 0, 0, 3, 7, 6, 0, 0, 4},
-				{0, 1, 6, 0, 3, 0, 0, 4, 0},
-				{3, 0, 0, 0, 8, 0, 0, 1, 6},
-				{0, 0, 7, 1, 6, 4, 5, 0, 3},
+				{0, 7, 0, 4, 1, 2, 0, 0, 3},
+				{3, 0, 0, 9, 5, 6, 0, 0, 7},
+				{9, 0, 0, 7, 3, 8, 1, 0, 5},
+				{0, 0, 8, 0, 0, 0, 0, 3, 4},
+				{4, 0, 0, 8, 0, 0, 0, 0, 0},
+				{0, 0, 9, 3, 4, 0, 0, 8, 1},
+				{0, 4, 0, 0, 8, 0, 0, 5, 0},
+				{0, 9, 0, 0, 0, 4, 3, 1, 8},
+				{0, 0, 0, 1, 9, 0, 4, 0, 2},
 			}),
-			step: StepSwordfish,
+			step: StepForcingNetContradiction,
 			max:  1,
 			tests: []CandidateTest{
 				{
-					column: 7,
-					row:    5,
-					before: "[2 8]",
-					after:  "[8]",
+					column: 0,
+					row:    0,
+					before: "[5 6 8]",
+					after:  "[6 8]",
 				},
+			},
+		},
+		{
+			puzzle: Classic.Create([][]int{
+				{0, 7, 0, 4, 1, 2, 0, 0, 3},
+				{3, 0, 0, 9, 5, 6, 0, 0, 7},
+				{9, 0, 0, 7, 3, 8, 1, 0, 5},
+				{0, 0, 8, 0, 0, 0, 0, 3, 4},
+				{4, 0, 0, 8, 0, 0, 0, 0, 0},
+				{0, 0, 9, 3, 4, 0, 0, 8, 1},
+				{0, 4, 0, 0, 8, 0, 0, 5, 0},
+				{0, 9, 0, 0, 0, 4, 3, 1, 8},
+				{0, 0, 0, 1, 9, 0, 4, 0, 2},
+			}),
+			step: StepTemplates,
+			max:  1,
+			tests: []CandidateTest{
 				{
 					column: 0,
-					row:    6,
-					before: "[2 5 8 9]",
-					after:  "[5 8 9]",
+					row:    8,
+					before: "[5 6 7 8]",
+					after:  "[5 6 8]",
 				},
 			},
-			solveSteps: 2,
 		},
 	}
 
@@ -664,6 +1552,7 @@ func TestCandidateRemoveSteps(t *testing.T) {
 		puzzle := &solver.Puzzle
 
 		solver.LogEnabled = true
+		solver.AssumeUnique = test.unique
 
 		for _, cellTest := range test.tests {
 			testCell := puzzle.Get(cellTest.column, cellTest.row)
@@ -692,6 +1581,12 @@ func TestCandidateRemoveSteps(t *testing.T) {
 			}
 		}
 
+		for _, log := range solver.Logs {
+			if log.Deduction == nil || len(log.Deduction.Cells) == 0 || len(log.Deduction.Eliminations)+len(log.Deduction.Placements) == 0 {
+				t.Errorf("#%d (%s): Log %d is missing its deduction: %+v", testIndex, test.step.Technique, log.Index, log.Deduction)
+			}
+		}
+
 		if test.solveSteps > 0 && test.solveSteps != len(solver.Logs) {
 			t.Errorf("#%d (%s): Unexpected solve steps %d, expected: %d.", testIndex, test.step.Technique, len(solver.Logs), test.solveSteps)
 		}
@@ -942,6 +1837,492 @@ func TestLogs(t *testing.T) {
 	printSolveLogs(&solver, false)
 }
 
+func TestDeduction(t *testing.T) {
+	puzzle := Classic.Create([][]int{
+		{0, 4, 1, 7, 2, 9, 0, 3, 0},
+		{7, 6, 9, 0, 0, 3, 4, 0, 2},
+		{0, 3, 2, 6, 4, 0, 7, 1, 9},
+		{4, 0, 3, 9, 0, 0, 1, 7, 0},
+		{6, 0, 7, 0, 0, 4, 9, 0, 3},
+		{1, 9, 5, 3, 7, 0, 0, 2, 4},
+		{2, 1, 4, 5, 6, 7, 3, 9, 8},
+		{3, 7, 6, 0, 9, 0, 5, 4, 1},
+		{9, 5, 8, 4, 3, 1, 2, 6, 7},
+	})
+
+	solver := puzzle.Solver()
+	solver.LogEnabled = true
+	StepXWing.Logic(&solver, SolveLimit{MaxBatches: 1}, StepXWing)
+
+	if len(solver.Logs) != 1 {
+		t.Fatalf("Expected one X-Wing elimination, got %d", len(solver.Logs))
+	}
+	deduction := solver.Logs[0].Deduction
+	cells := fmt.Sprint(deduction.Cells)
+	if cells != "[{4 4} {7 4} {4 1} {7 1}]" {
+		t.Errorf("Unexpected pattern cells %s", cells)
+	}
+	if fmt.Sprint(deduction.Candidates) != "[5]" {
+		t.Errorf("Unexpected pattern candidates %v", deduction.Candidates)
+	}
+	if len(deduction.Eliminations) != 1 || deduction.Eliminations[0] != (SolverCandidate{Position{Col: 4, Row: 3}, 5}) {
+		t.Errorf("Unexpected eliminations %v", deduction.Eliminations)
+	}
+	if len(deduction.Placements) != 0 {
+		t.Errorf("Unexpected placements %v", deduction.Placements)
+	}
+}
+
+func TestDeductionEliminations(t *testing.T) {
+	tests := []struct {
+		step     *SolveStep
+		puzzle   Puzzle
+		expected string
+	}{
+		{
+			step: StepSwordfish,
+			puzzle: Classic.Create([][]int{
+				{1, 6, 0, 5, 4, 3, 0, 7, 0},
+				{0, 7, 8, 6, 0, 1, 4, 3, 5},
+				{4, 3, 5, 8, 0, 7, 6, 0, 1},
+				{7, 2, 0, 4, 5, 8, 0, 6, 9},
+				{6, 0, 0, 9, 1, 2, 0, 5, 7},
+				{0, 0, 0, 3, 7, 6, 0, 0, 4},
+				{0, 1, 6, 0, 3, 0, 0, 4, 0},
+				{3, 0, 0, 0, 8, 0, 0, 1, 6},
+				{0, 0, 7, 1, 6, 4, 5, 0, 3},
+			}),
+			expected: "Swordfish on 2 in rows 2,3,9 (c1,c5,c8) => r6c8,r7c1<>2",
+		},
+		{
+			step: StepEmptyRectangle,
+			puzzle: Classic.Create([][]int{
+				{5, 8, 0, 1, 7, 9, 0, 0, 3},
+				{0, 0, 0, 6, 0, 8, 9, 7, 5},
+				{6, 9, 7, 3, 5, 0, 0, 0, 0},
+				{9, 0, 0, 5, 3, 0, 7, 2, 8},
+				{7, 0, 3, 8, 1, 0, 5, 0, 0},
+				{8, 5, 0, 9, 0, 7, 1, 3, 0},
+				{4, 6, 9, 2, 8, 1, 3, 5, 7},
+				{0, 0, 8, 7, 6, 5, 0, 0, 0},
+				{0, 7, 5, 4, 9, 3, 0, 0, 0},
+			}),
+			expected: "Empty Rectangle on 2 in b1 (r1c3,r2c1,r2c2,r2c3,r2c5,r6c5) => r2c5,r6c3<>2",
+		},
+	}
+
+	for _, test := range tests {
+		solver := test.puzzle.Solver()
+		solver.LogEnabled = true
+		test.step.Logic(&solver, SolveLimit{MaxBatches: 1}, test.step)
+
+		// Every elimination of a pattern is one step with one deduction.
+		first := sliceWhere(solver.Logs, func(log SolverLog) bool { return log.Batch == 1 })
+		if len(first) != 2 {
+			t.Fatalf("%s: expected the first pattern to eliminate 2 candidates, got %d", test.step.Technique, len(first))
+		}
+		if first[0].Deduction != first[1].Deduction {
+			t.Errorf("%s: expected the eliminations to share one deduction", test.step.Technique)
+		}
+		if first[0].Explanation() != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.step.Technique, test.expected, first[0].Explanation())
+		}
+	}
+}
+
+func TestOptionalSteps(t *testing.T) {
+	puzzle := Classic.Empty()
+	solver := puzzle.Solver()
+
+	steps := solver.GetSteps(SolveLimit{})
+	if len(steps) != len(StandardSolveSteps) {
+		t.Errorf("Expected only the standard steps, got %d", len(steps))
+	}
+
+	steps = solver.GetSteps(SolveLimit{Techniques: map[string]int{"Mutant Swordfish": 1}})
+	if len(steps) != len(StandardSolveSteps)+1 || steps[len(steps)-1] != StepMutantSwordfish {
+		t.Errorf("Expected the mutant swordfish step to be added")
+	}
+	if len(solver.Steps) != len(StandardSolveSteps) {
+		t.Errorf("The solver steps should not change")
+	}
+}
+
+func TestAssumeUnique(t *testing.T) {
+	puzzle := Classic.Create([][]int{
+		{0, 0, 5, 0, 1, 0, 0, 0, 0},
+		{0, 0, 0, 7, 0, 0, 0, 1, 0},
+		{0, 6, 0, 0, 0, 0, 2, 5, 0},
+		{7, 0, 3, 9, 0, 0, 0, 0, 0},
+		{8, 0, 0, 1, 0, 7, 4, 0, 0},
+		{0, 0, 0, 2, 0, 0, 8, 0, 0},
+		{3, 0, 4, 0, 0, 0, 0, 0, 9},
+		{0, 0, 6, 3, 0, 4, 0, 0, 8},
+		{0, 0, 0, 0, 7, 6, 0, 0, 0},
+	})
+
+	solver := puzzle.Solver()
+	solver.Solve(SolveLimit{})
+	for _, step := range []*SolveStep{StepBUG1, StepUniqueRectangle1, StepUniqueRectangle2, StepUniqueRectangle3, StepUniqueRectangle4, StepUniqueRectangle5, StepUniqueRectangle6, StepHiddenRectangle, StepAvoidableRectangle} {
+		if solver.LogTechniques[step.Technique] > 0 {
+			t.Errorf("%s was used without assuming a unique solution", step.Technique)
+		}
+	}
+
+	unique := puzzle.Solver()
+	unique.AssumeUnique = true
+	solution, solved := unique.Solve(SolveLimit{})
+	if !solved {
+		solution.PrintConsoleCandidates()
+		t.Fatal("Failed to solve assuming a unique solution")
+	}
+	if unique.LogTechniques[StepAvoidableRectangle.Technique] == 0 {
+		t.Errorf("Expected an avoidable rectangle to be used")
+	}
+
+	checkValid(solution, t)
+}
+
+func TestMaxChainLength(t *testing.T) {
+	puzzle := Classic.Create([][]int{
+		{4, 9, 2, 7, 8, 1, 0, 6, 0},
+		{3, 8, 6, 2, 4, 5, 7, 9, 1},
+		{7, 5, 1, 0, 0, 3, 2, 4, 8},
+		{9, 0, 3, 0, 0, 8, 0, 5, 7},
+		{0, 0, 0, 0, 7, 0, 0, 0, 0},
+		{0, 6, 7, 4, 0, 0, 0, 0, 0},
+		{2, 3, 9, 0, 0, 0, 0, 0, 6},
+		{0, 0, 5, 0, 0, 0, 0, 0, 0},
+		{0, 7, 0, 0, 0, 0, 9, 3, 0},
+	})
+
+	short := puzzle.Solver()
+	short.LogEnabled = true
+	StepXChain.Logic(&short, SolveLimit{MaxBatches: 1, MaxChainLength: 3}, StepXChain)
+	if len(short.Logs) != 0 {
+		t.Errorf("Expected no chains of 3 links, got %d logs", len(short.Logs))
+	}
+
+	long := puzzle.Solver()
+	long.LogEnabled = true
+	StepXChain.Logic(&long, SolveLimit{MaxBatches: 1, MaxChainLength: 5}, StepXChain)
+	if len(long.Logs) != 1 || long.Logs[0].Cost != StepXChain.FirstCost+400 {
+		t.Errorf("Expected a chain of 5 links")
+	}
+}
+
+func TestMaxForcing(t *testing.T) {
+	puzzle := Classic.Create([][]int{
+		{0, 0, 0, 0, 1, 0, 0, 8, 2},
+		{1, 0, 4, 0, 0, 0, 0, 7, 3},
+		{0, 0, 2, 0, 0, 7, 9, 0, 1},
+		{4, 1, 0, 2, 9, 0, 7, 0, 8},
+		{0, 2, 8, 0, 0, 5, 1, 0, 9},
+		{9, 0, 0, 0, 0, 1, 0, 2, 4},
+		{2, 6, 9, 1, 7, 0, 0, 0, 5},
+		{8, 4, 3, 6, 5, 9, 2, 1, 7},
+		{5, 7, 1, 0, 0, 0, 0, 9, 6},
+	})
+
+	shallow := puzzle.Solver()
+	shallow.LogEnabled = true
+	StepForcingChainContradiction.Logic(&shallow, SolveLimit{MaxForcingDepth: 3}, StepForcingChainContradiction)
+	if len(shallow.Logs) != 0 {
+		t.Errorf("Expected no contradiction within 3 implications, got %d logs", len(shallow.Logs))
+	}
+
+	deep := puzzle.Solver()
+	deep.LogEnabled = true
+	StepForcingChainContradiction.Logic(&deep, SolveLimit{MaxForcingDepth: 4}, StepForcingChainContradiction)
+	if len(deep.Logs) != 1 {
+		t.Errorf("Expected a contradiction within 4 implications, got %d logs", len(deep.Logs))
+	}
+
+	small := puzzle.Solver()
+	small.LogEnabled = true
+	StepForcingChainContradiction.Logic(&small, SolveLimit{MaxForcingNodes: 20}, StepForcingChainContradiction)
+	if len(small.Logs) != 0 {
+		t.Errorf("Expected no contradiction within 20 candidates, got %d logs", len(small.Logs))
+	}
+}
+
+func TestForcingDeduction(t *testing.T) {
+	puzzle := Classic.Create([][]int{
+		{0, 0, 0, 0, 1, 0, 0, 8, 2},
+		{1, 0, 4, 0, 0, 0, 0, 7, 3},
+		{0, 0, 2, 0, 0, 7, 9, 0, 1},
+		{4, 1, 0, 2, 9, 0, 7, 0, 8},
+		{0, 2, 8, 0, 0, 5, 1, 0, 9},
+		{9, 0, 0, 0, 0, 1, 0, 2, 4},
+		{2, 6, 9, 1, 7, 0, 0, 0, 5},
+		{8, 4, 3, 6, 5, 9, 2, 1, 7},
+		{5, 7, 1, 0, 0, 0, 0, 9, 6},
+	})
+
+	solver := puzzle.Solver()
+	solver.LogEnabled = true
+	StepForcingChainContradiction.Logic(&solver, SolveLimit{}, StepForcingChainContradiction)
+	if len(solver.Logs) != 1 {
+		t.Fatalf("Expected a contradiction, got %d logs", len(solver.Logs))
+	}
+
+	deduction := solver.Logs[0].Deduction
+	if len(deduction.Implications) != 1 {
+		t.Fatalf("Expected one path to the contradiction, got %v", deduction.Implications)
+	}
+	path := deduction.Implications[0]
+	assumption := SolverImplication{SolverCandidate{deduction.Cells[0], deduction.Candidates[0]}, true}
+	if path[0] != assumption {
+		t.Errorf("Expected the path to start with the assumption %v, got %v", assumption, path[0])
+	}
+	if fmt.Sprint(deduction.Contradiction) != "[{7 3}]" || path[len(path)-1].Position != deduction.Contradiction[0] {
+		t.Errorf("Expected the path %v to end at the contradiction %v", path, deduction.Contradiction)
+	}
+
+	cell := puzzle.Solver()
+	cell.LogEnabled = true
+	StepCellForcingChain.Logic(&cell, SolveLimit{}, StepCellForcingChain)
+	if len(cell.Logs) == 0 {
+		t.Fatalf("Expected a cell forcing chain")
+	}
+	deduction = cell.Logs[0].Deduction
+	assumptions := len(deduction.Candidates)
+	if len(deduction.Implications) != assumptions*len(deduction.Eliminations) {
+		t.Errorf("Expected a path from each of the %d assumptions to each elimination, got %v", assumptions, deduction.Implications)
+	}
+}
+
+func TestForcingHouses(t *testing.T) {
+	kind := SudokuX.WithRules(RuleAntiKnight)
+	puzzle := kind.Empty()
+	solver := puzzle.Solver()
+	graph := newChainGraph(&solver, chainLinks{houseStrong: true, houseWeak: true, cellStrong: true, cellWeak: true})
+
+	// A diagonal and a knight's move which aren't in the same row, column, or box.
+	for _, pair := range [][2]Position{{{0, 0}, {8, 8}}, {{2, 2}, {3, 0}}} {
+		from := graph.index(solver.Puzzle.Get(pair[0].Col, pair[0].Row), 1)
+		to := graph.index(solver.Puzzle.Get(pair[1].Col, pair[1].Row), 1)
+		if sliceIndex(graph.weak[from], func(i int) bool { return i == to }) == -1 {
+			t.Errorf("Expected r%dc%d to be weakly linked to r%dc%d", pair[0].Row+1, pair[0].Col+1, pair[1].Row+1, pair[1].Col+1)
+		}
+	}
+
+	// 1 can only be in two cells of the diagonal, so they are strongly linked.
+	for _, cell := range solver.Houses[0] {
+		if cell.Id != solver.Puzzle.Get(0, 0).Id && cell.Id != solver.Puzzle.Get(8, 8).Id {
+			cell.RemoveCandidate(1)
+		}
+	}
+	graph = newChainGraph(&solver, chainLinks{houseStrong: true, houseWeak: true, cellStrong: true, cellWeak: true})
+	corner := graph.index(solver.Puzzle.Get(0, 0), 1)
+	if sliceIndex(graph.strong[corner], func(i int) bool { return i == graph.index(solver.Puzzle.Get(8, 8), 1) }) == -1 {
+		t.Errorf("Expected r1c1 to be strongly linked to r9c9 on the diagonal")
+	}
+}
+
+func TestForcingConstraints(t *testing.T) {
+	kind := Classic.Clone()
+	kind.Constraints = []Constraint{&ConstraintCage{Sum: 3, Cells: []Position{{Col: 0, Row: 0}, {Col: 1, Row: 0}}}}
+	puzzle := kind.Empty()
+	solver := puzzle.Solver()
+	assumed := solver.Puzzle.Get(0, 0)
+	other := solver.Puzzle.Get(1, 0)
+
+	// With 1 in the cage the other cell must be 2, which only the constraint can tell.
+	for _, net := range []bool{false, true} {
+		f := newForcingNet(&solver, SolveLimit{}, net)
+		f.assume(f.graph.index(assumed, 1), true)
+		decided := f.off[f.graph.index(other, 5)] == f.generation
+		if decided != net {
+			t.Errorf("Expected r1c2<>5 to be decided %v by a net %v", net, net)
+		}
+	}
+}
+
+func TestAlmostLockedSets(t *testing.T) {
+	puzzle := Classic.Create([][]int{
+		{0, 4, 1, 7, 2, 9, 0, 3, 0},
+		{7, 6, 9, 0, 0, 3, 4, 0, 2},
+		{0, 3, 2, 6, 4, 0, 7, 1, 9},
+		{4, 0, 3, 9, 0, 0, 1, 7, 0},
+		{6, 0, 7, 0, 0, 4, 9, 0, 3},
+		{1, 9, 5, 3, 7, 0, 0, 2, 4},
+		{2, 1, 4, 5, 6, 7, 3, 9, 8},
+		{3, 7, 6, 0, 9, 0, 5, 4, 1},
+		{9, 5, 8, 4, 3, 1, 2, 6, 7},
+	})
+	solver := puzzle.Solver()
+	sets := getAlmostLockedSets(&solver)
+
+	if len(sets) == 0 {
+		t.Fatalf("Expected almost locked sets")
+	}
+	found := map[string]bool{}
+	for _, als := range sets {
+		key := fmt.Sprint(als.ids)
+		if found[key] {
+			t.Errorf("Duplicate almost locked set %v", als.ids)
+		}
+		found[key] = true
+		if als.candidates.Count != len(als.cells)+1 {
+			t.Errorf("Almost locked set %v has %d candidates", als.ids, als.candidates.Count)
+		}
+	}
+}
+
+func TestSeenByAllHouses(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     *Kind
+		seen     Position
+		expected Position
+	}{
+		{"diagonal", SudokuX, Position{Col: 0, Row: 0}, Position{Col: 4, Row: 4}},
+		{"window", Windoku, Position{Col: 1, Row: 1}, Position{Col: 3, Row: 3}},
+		{"anti-knight", Classic.WithRules(RuleAntiKnight), Position{Col: 2, Row: 2}, Position{Col: 0, Row: 3}},
+	}
+
+	for _, test := range tests {
+		puzzle := test.kind.Empty()
+		solver := puzzle.Solver()
+		seen := solver.Puzzle.Get(test.seen.Col, test.seen.Row)
+		expected := solver.Puzzle.Get(test.expected.Col, test.expected.Row)
+
+		eliminations := getCandidateSeenByAll(&solver, 1, []*Cell{seen}, nil)
+		if sliceIndex(eliminations, func(node candidateNode) bool { return node.cell == expected }) == -1 {
+			t.Errorf("%s: expected r%dc%d to see r%dc%d", test.name, expected.Row+1, expected.Col+1, seen.Row+1, seen.Col+1)
+		}
+		for i, node := range eliminations {
+			if sliceIndex(eliminations[:i], func(other candidateNode) bool { return other.cell == node.cell }) != -1 {
+				t.Errorf("%s: r%dc%d was found more than once", test.name, node.cell.Row+1, node.cell.Col+1)
+			}
+		}
+	}
+}
+
+func TestTemplatesKinds(t *testing.T) {
+	for _, kind := range []*Kind{Kind2x2, Kind3x2, Classic, Kind4x3, Kind4x4} {
+		gen := NewSeededGenerator(kind, 1)
+		solution, _ := gen.Generate()
+
+		// Without any of a digit every other digit is placed, so there is one template for it.
+		puzzle := solution.Clone()
+		for i := range puzzle.Cells {
+			if puzzle.Cells[i].Value == 1 {
+				puzzle.RemoveCell(&puzzle.Cells[i])
+			}
+		}
+
+		solver := puzzle.Solver()
+		placements, _ := StepTemplates.Logic(&solver, SolveLimit{}, StepTemplates)
+		if placements != kind.Size() {
+			t.Errorf("Expected templates to place %d digits for %dx%d, placed %d", kind.Size(), kind.BoxSize.Width, kind.BoxSize.Height, placements)
+		}
+		if solver.Puzzle.UniqueId() != solution.UniqueId() {
+			solver.Puzzle.PrintConsoleCandidates()
+			t.Errorf("Expected templates to solve %dx%d", kind.BoxSize.Width, kind.BoxSize.Height)
+		}
+	}
+
+	// More than 64 digits, from a solution where each row is shifted from the one above.
+	kind := NewKind(9, 8)
+	w, h, size := kind.BoxSize.Width, kind.BoxSize.Height, kind.Size()
+	solution := New(kind)
+	puzzle := New(kind)
+	for i := range solution.Cells {
+		cell := &solution.Cells[i]
+		value := ((cell.Row%h)*w + cell.Row/h + cell.Col) % size
+		solution.SetCell(cell, value+1)
+		if value != 0 {
+			puzzle.SetCell(&puzzle.Cells[i], value+1)
+		}
+	}
+
+	solver := puzzle.Solver()
+	placements, _ := StepTemplates.Logic(&solver, SolveLimit{}, StepTemplates)
+	if placements != size {
+		t.Errorf("Expected templates to place %d digits for 9x8, placed %d", size, placements)
+	}
+	if solver.Puzzle.UniqueId() != solution.UniqueId() {
+		t.Errorf("Expected templates to solve 9x8")
+	}
+}
+
+func TestTemplatesHousesAndLinks(t *testing.T) {
+	x4 := NewKind(2, 2)
+	x4.Houses = DiagonalHouses(4)
+
+	tests := []struct {
+		name   string
+		kind   *Kind
+		values string
+	}{
+		{"diagonal 4x4", x4, "....3.....414..."},
+		{"diagonal", SudokuX, ".......3.9........3....6..22.3.1859..98..73.117.3.98.4...9........7.4.....7.6...."},
+		{"anti-knight", Classic.WithRules(RuleAntiKnight), "41..8.....3..1.6....7.....367...5.....3..78...8.....65..8...........8..1...93.2.."},
+		{"anti-king", Kind3x2.WithRules(RuleAntiKing), "...31.4.........3.5........2....4..1"},
+	}
+
+	for _, test := range tests {
+		puzzle := test.kind.Empty()
+		for i, value := range test.values {
+			if value != '.' {
+				puzzle.SetCell(&puzzle.Cells[i], int(value-'0'))
+			}
+		}
+		solution := puzzle.GetSolutions(SolutionsLimit{MaxSolutions: 1})[0].Puzzle
+
+		solver := puzzle.Solver()
+		placements, _ := StepTemplates.Logic(&solver, SolveLimit{}, StepTemplates)
+		for i := range solver.Puzzle.Cells {
+			cell, value := &solver.Puzzle.Cells[i], solution.Cells[i].Value
+			if (cell.HasValue() && cell.Value != value) || (cell.Empty() && !cell.HasCandidate(value)) {
+				t.Errorf("%s: templates went against the solution at r%dc%d", test.name, cell.Row+1, cell.Col+1)
+			}
+		}
+
+		// The same candidates without the extra houses and links have more templates.
+		plainKind := test.kind.Clone()
+		plainKind.Houses = nil
+		plainKind.Links = nil
+		plain := puzzle.Clone()
+		plain.Kind = plainKind
+		for i := range plain.Cells {
+			plain.Cells[i].Houses = nil
+			plain.Cells[i].Links = nil
+		}
+		plainSolver := plain.Solver()
+		plainPlacements, _ := StepTemplates.Logic(&plainSolver, SolveLimit{}, StepTemplates)
+		if placements <= plainPlacements {
+			t.Errorf("%s: expected the extra houses and links to place more than %d digits, placed %d", test.name, plainPlacements, placements)
+		}
+	}
+}
+
+func TestTemplatesMaxNodes(t *testing.T) {
+	puzzle := Classic.Empty()
+	solver := puzzle.Solver()
+	templates := newTemplates(&solver, SolveLimit{MaxTemplateNodes: 10}, 1)
+	if !templates.exceeded || templates.nodes > 10 {
+		t.Errorf("Expected templates to stop at 10 states, searched %d", templates.nodes)
+	}
+
+	// Without any of a digit there is one template, but finding it takes a state for each row.
+	gen := NewSeededGenerator(Classic, 1)
+	solution, _ := gen.Generate()
+	puzzle = solution.Clone()
+	for i := range puzzle.Cells {
+		if puzzle.Cells[i].Value == 1 {
+			puzzle.RemoveCell(&puzzle.Cells[i])
+		}
+	}
+	solver = puzzle.Solver()
+	placements, _ := StepTemplates.Logic(&solver, SolveLimit{MaxTemplateNodes: 4}, StepTemplates)
+	if placements != 0 {
+		t.Errorf("Expected templates past the limit to be skipped, placed %d", placements)
+	}
+}
+
 func checkValid(puzzle *Puzzle, t *testing.T) {
 	if !puzzle.IsValid() {
 		puzzle.PrintConsoleCandidates()
@@ -0,0 +1,272 @@
+package sudogo
+
+// The maximum number of template states a digit can have when SolveLimit.MaxTemplateNodes is not given.
+const DefaultMaxTemplateNodes = 20000
+
+// The most columns, boxes, and extra houses a template can track, kinds with more are skipped.
+const templateMaxSize = 128
+
+// The most rows apart the linked cells of a kind can be, kinds with links further apart are skipped.
+const templateMaxLinkRows = 4
+
+// The columns, boxes, or extra houses used by a template.
+type templateSet [templateMaxSize / 64]uint64
+
+func (set templateSet) has(i int) bool {
+	return set[i/64]&(1<<(i%64)) != 0
+}
+
+func (set *templateSet) add(i int) {
+	set[i/64] |= 1 << (i % 64)
+}
+
+// The columns, boxes, and extra houses used by the rows before a row of a template and the columns
+// (plus one) of the last rows for the kind's links. It's comparable so it can be remembered.
+type templateState struct {
+	row    int
+	cols   templateSet
+	boxs   templateSet
+	houses templateSet
+	recent [templateMaxLinkRows]int
+}
+
+// The cells a digit can be in for every valid placement of the digit (a template): one cell in each row,
+// column, box, and extra house which is either the cell with the digit or an unsolved cell with it as a
+// candidate, and no two cells which see each other through the kind's links.
+type templates struct {
+	puzzle *Puzzle
+	size   int
+	digit  int
+	// Every extra house, which a template must all use.
+	houses templateSet
+	// How many rows before a row can have cells linked to it.
+	linkRows int
+	// Whether the rows after a state can be completed.
+	feasible map[templateState]bool
+	visited  map[templateState]bool
+	// The states searched, the most before the templates are given up on, and whether they were.
+	nodes    int
+	maxNodes int
+	exceeded bool
+	// Whether each cell (by id) is in at least one template.
+	used []bool
+}
+
+// Returns whether templates can be found for the kind.
+func canUseTemplates(kind *Kind) bool {
+	if kind.Size() > templateMaxSize || len(kind.Houses) > templateMaxSize {
+		return false
+	}
+	for _, link := range kind.Links {
+		if AbsInt(link.Row) > templateMaxLinkRows {
+			return false
+		}
+	}
+	return true
+}
+
+func newTemplates(solver *Solver, limits SolveLimit, digit int) *templates {
+	kind := solver.Puzzle.Kind
+	t := &templates{
+		puzzle:   &solver.Puzzle,
+		size:     kind.Size(),
+		digit:    digit,
+		feasible: map[templateState]bool{},
+		visited:  map[templateState]bool{},
+		maxNodes: getMaxTemplateNodes(limits),
+		used:     make([]bool, len(solver.Puzzle.Cells)),
+	}
+	for i := range kind.Houses {
+		t.houses.add(i)
+	}
+	for _, link := range kind.Links {
+		t.linkRows = Max(t.linkRows, AbsInt(link.Row))
+	}
+	if t.any() && !t.exceeded {
+		t.visit(templateState{})
+	}
+	return t
+}
+
+// The maximum number of template states a digit can have for the given limits.
+func getMaxTemplateNodes(limits SolveLimit) int {
+	if limits.MaxTemplateNodes > 0 {
+		return limits.MaxTemplateNodes
+	}
+	return DefaultMaxTemplateNodes
+}
+
+// The cells in the row the digit can be in, the cell with the digit if it's already placed.
+func (t *templates) choices(row int) []*Cell {
+	choices := make([]*Cell, 0, t.size)
+	for col := 0; col < t.size; col++ {
+		cell := t.puzzle.Get(col, row)
+		if cell.Value == t.digit {
+			return []*Cell{cell}
+		}
+		if cell.Empty() && cell.HasCandidate(t.digit) {
+			choices = append(choices, cell)
+		}
+	}
+	return choices
+}
+
+func (t *templates) next(state templateState, cell *Cell) (templateState, bool) {
+	if state.cols.has(cell.Col) || state.boxs.has(cell.Box) {
+		return state, false
+	}
+	for _, house := range cell.Houses {
+		if state.houses.has(house) {
+			return state, false
+		}
+	}
+	for _, link := range cell.Links {
+		rows := cell.Row - link/t.size
+		if rows > 0 && rows <= t.linkRows && state.recent[rows-1] == link%t.size+1 {
+			return state, false
+		}
+	}
+	next := state
+	next.row++
+	next.cols.add(cell.Col)
+	next.boxs.add(cell.Box)
+	for _, house := range cell.Houses {
+		next.houses.add(house)
+	}
+	if t.linkRows > 0 {
+		copy(next.recent[1:t.linkRows], state.recent[:t.linkRows-1])
+		next.recent[0] = cell.Col + 1
+	}
+	return next, true
+}
+
+// Returns whether there is a template which starts with the state.
+func (t *templates) isFeasible(state templateState) bool {
+	if state.row == t.size {
+		return state.houses == t.houses
+	}
+	if feasible, ok := t.feasible[state]; ok {
+		return feasible
+	}
+	if t.nodes >= t.maxNodes {
+		t.exceeded = true
+		return false
+	}
+	t.nodes++
+	feasible := false
+	for _, cell := range t.choices(state.row) {
+		if next, ok := t.next(state, cell); ok && t.isFeasible(next) {
+			feasible = true
+			break
+		}
+	}
+	t.feasible[state] = feasible
+	return feasible
+}
+
+// Marks every cell in a template which starts with the state, which must be feasible.
+func (t *templates) visit(state templateState) {
+	if state.row == t.size {
+		return
+	}
+	if t.visited[state] {
+		return
+	}
+	t.visited[state] = true
+	for _, cell := range t.choices(state.row) {
+		if next, ok := t.next(state, cell); ok && t.isFeasible(next) {
+			t.used[cell.Id] = true
+			t.visit(next)
+		}
+	}
+}
+
+// Returns whether there is at least one template.
+func (t *templates) any() bool {
+	return t.isFeasible(templateState{})
+}
+
+// The cell in the row which is in every template, or nil if there are none or more than one.
+func (t *templates) only(row int) *Cell {
+	var only *Cell
+	for col := 0; col < t.size; col++ {
+		cell := t.puzzle.Get(col, row)
+		if t.used[cell.Id] {
+			if only != nil {
+				return nil
+			}
+			only = cell
+		}
+	}
+	return only
+}
+
+// ==================================================
+// Step: Templates
+//		http://hodoku.sourceforge.net/en/tech_misc.php#tmpl
+// ==================================================
+var StepTemplates = &SolveStep{
+	Technique:      "Templates",
+	FirstCost:      11000,
+	SubsequentCost: 9000,
+	Logic: func(solver *Solver, limits SolveLimit, step *SolveStep) (int, bool) {
+		placements, removed := 0, 0
+		if solver.CanContinueStep(limits, step) && canUseTemplates(solver.Puzzle.Kind) {
+			placements, removed = doTemplates(solver, limits, step)
+		}
+		return placements, removed > 0
+	},
+}
+
+// Every valid placement of a digit (a template) is found given the current candidates, a digit with too many
+// to find within the limits is skipped. A digit in a cell
+// which is in every template is placed, and a candidate in a cell which is in no template is removed.
+func doTemplates(solver *Solver, limits SolveLimit, step *SolveStep) (int, int) {
+	placements, removed := 0, 0
+	size := solver.Puzzle.Kind.Size()
+	for digit := 1; digit <= size; digit++ {
+		t := newTemplates(solver, limits, digit)
+		if t.exceeded || !t.any() {
+			continue
+		}
+
+		pattern := make([]candidateNode, 0, size)
+		for i := range solver.Puzzle.Cells {
+			if cell := &solver.Puzzle.Cells[i]; t.used[cell.Id] {
+				pattern = append(pattern, candidateNode{cell, digit})
+			}
+		}
+
+		placed := 0
+		for row := 0; row < size; row++ {
+			cell := t.only(row)
+			if cell == nil || !cell.Empty() {
+				continue
+			}
+			if placed == 0 {
+				solver.LogStep(step)
+				logPatternNodes(solver, pattern)
+			}
+			solver.LogBefore(cell)
+			solver.SetCell(cell, digit)
+			solver.LogPlacement(cell)
+			placed++
+		}
+		placements += placed
+
+		if placed == 0 {
+			eliminations := make([]candidateNode, 0)
+			for _, cell := range solver.Unsolved {
+				if cell.HasCandidate(digit) && !t.used[cell.Id] {
+					eliminations = append(eliminations, candidateNode{cell, digit})
+				}
+			}
+			removed += removeCandidateNodes(solver, step, eliminations, pattern)
+		}
+
+		if !solver.CanContinueStep(limits, step) {
+			break
+		}
+	}
+	return placements, removed
+}
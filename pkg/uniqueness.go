@@ -0,0 +1,484 @@
+package sudogo
+
+// Four cells in two rows, two columns, and two boxes. The cells are ordered r1c1, r1c2, r2c1, r2c2 so
+// cells i and i^1 share a row, cells i and i^2 share a column, and cells i and 3-i are diagonal.
+type rectangle [4]*Cell
+
+// Returns every rectangle in the puzzle where the cells have no constraints and match the given function.
+func getRectangles(solver *Solver, matches func(r rectangle) bool) []rectangle {
+	size := solver.Puzzle.Kind.Size()
+	rectangles := make([]rectangle, 0)
+	for row1 := 0; row1 < size; row1++ {
+		for row2 := row1 + 1; row2 < size; row2++ {
+			for col1 := 0; col1 < size; col1++ {
+				for col2 := col1 + 1; col2 < size; col2++ {
+					r := rectangle{
+						solver.Puzzle.Get(col1, row1),
+						solver.Puzzle.Get(col2, row1),
+						solver.Puzzle.Get(col1, row2),
+						solver.Puzzle.Get(col2, row2),
+					}
+					if r.boxes() == 2 && !r.constrained() && matches(r) {
+						rectangles = append(rectangles, r)
+					}
+				}
+			}
+		}
+	}
+	return rectangles
+}
+
+// The number of boxes the rectangle is in.
+func (r rectangle) boxes() int {
+	boxes := 0
+	for i, cell := range r {
+		if sliceIndex(r[:i], func(other *Cell) bool { return other.Box == cell.Box }) == -1 {
+			boxes++
+		}
+	}
+	return boxes
+}
+
+// Returns whether any cell in the rectangle has a constraint or link, which may prevent swapping values, or is the
+// only cell of the rectangle in an extra house, where the swapped value could repeat.
+func (r rectangle) constrained() bool {
+	for _, cell := range r {
+		if len(cell.Constraints) > 0 || len(cell.Links) > 0 {
+			return true
+		}
+		for _, house := range cell.Houses {
+			if sliceIndex(r[:], func(other *Cell) bool { return other != cell && other.HasHouse(house) }) == -1 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// The index of the cell that shares a row with the cell at i.
+func rectangleRowOf(i int) int {
+	return i ^ 1
+}
+
+// The index of the cell that shares a column with the cell at i.
+func rectangleColOf(i int) int {
+	return i ^ 2
+}
+
+// The index of the cell diagonal to the cell at i.
+func rectangleDiagonalOf(i int) int {
+	return 3 - i
+}
+
+// A rectangle of unsolved cells which all have the candidates a and b. If every cell only had a and b
+// the values could be swapped for a second solution (a deadly pattern), so a unique puzzle can't end
+// up with that pattern.
+type uniqueRectangle struct {
+	cells rectangle
+	pair  Candidates
+	a     int
+	b     int
+	// The cells (by index) which only have a and b.
+	floor []int
+	// The cells (by index) which have candidates other than a and b.
+	roof []int
+}
+
+// The candidates in the cell at i other than a and b.
+func (ur uniqueRectangle) extras(i int) Candidates {
+	extras := ur.cells[i].candidates
+	extras.Remove(ur.pair)
+	return extras
+}
+
+// The pair and the given extra candidates in the rectangle, the pattern of a deduction made with it.
+func (ur uniqueRectangle) pattern(extras Candidates) []candidateNode {
+	extras.Or(ur.pair)
+	return getCandidateNodes(ur.cells[:], extras)
+}
+
+// Returns whether the roof is two cells that share a row or column.
+func (ur uniqueRectangle) adjacentRoof() bool {
+	return len(ur.roof) == 2 && ur.roof[1] != rectangleDiagonalOf(ur.roof[0])
+}
+
+// Returns whether the roof is two cells that are diagonal.
+func (ur uniqueRectangle) diagonalRoof() bool {
+	return len(ur.roof) == 2 && ur.roof[1] == rectangleDiagonalOf(ur.roof[0])
+}
+
+// The unsolved cells in the houses shared by the roof cells, excluding the roof cells.
+func (ur uniqueRectangle) roofHouses(solver *Solver) [][]*Cell {
+	first := ur.cells[ur.roof[0]]
+	second := ur.cells[ur.roof[1]]
+	houses := make([][]*Cell, 0, 2)
+	for _, group := range []Group{GroupCol, GroupRow, GroupBox} {
+		if first.GetGroup(group) != second.GetGroup(group) {
+			continue
+		}
+		house := make([]*Cell, 0)
+		for _, cell := range solver.Group(group, first) {
+			if cell != first && cell != second {
+				house = append(house, cell)
+			}
+		}
+		houses = append(houses, house)
+	}
+	return houses
+}
+
+// Returns every unique rectangle where at least one cell only has the two candidates.
+func getUniqueRectangles(solver *Solver) []uniqueRectangle {
+	urs := make([]uniqueRectangle, 0)
+	getRectangles(solver, func(r rectangle) bool {
+		pairs := make([]Candidates, 0, 4)
+		for _, cell := range r {
+			if cell.HasValue() {
+				return false
+			}
+			if cell.candidates.Count == 2 && sliceIndex(pairs, func(p Candidates) bool { return p.Equals(cell.candidates) }) == -1 {
+				pairs = append(pairs, cell.candidates)
+			}
+		}
+		for _, pair := range pairs {
+			ur := uniqueRectangle{
+				cells: r,
+				pair:  pair,
+				a:     pair.First(),
+				b:     pair.Last(),
+				floor: make([]int, 0, 4),
+				roof:  make([]int, 0, 4),
+			}
+			for i, cell := range r {
+				if !cell.HasCandidate(ur.a) || !cell.HasCandidate(ur.b) {
+					ur.floor = nil
+					break
+				}
+				if cell.candidates.Count == 2 {
+					ur.floor = append(ur.floor, i)
+				} else {
+					ur.roof = append(ur.roof, i)
+				}
+			}
+			if ur.floor != nil {
+				urs = append(urs, ur)
+			}
+		}
+		return false
+	})
+	return urs
+}
+
+// Returns the number of unsolved cells in the house with the candidate.
+func countCandidate(house []*Cell, candidate int) int {
+	count := 0
+	for _, cell := range house {
+		if cell.HasCandidate(candidate) {
+			count++
+		}
+	}
+	return count
+}
+
+// ==================================================
+// Step: Uniqueness
+//		http://hodoku.sourceforge.net/en/tech_ur.php
+// ==================================================
+func CreateStepUniqueness(technique string, firstCost int, subsequentCost int, logic func(solver *Solver, limits SolveLimit, step *SolveStep) (int, int)) *SolveStep {
+	return &SolveStep{
+		Technique:      technique,
+		FirstCost:      firstCost,
+		SubsequentCost: subsequentCost,
+		Logic: func(solver *Solver, limits SolveLimit, step *SolveStep) (int, bool) {
+			placements, removed := 0, 0
+			if solver.AssumeUnique && solver.CanContinueStep(limits, step) {
+				placements, removed = logic(solver, limits, step)
+			}
+			return placements, removed > 0
+		},
+	}
+}
+
+// Creates a unique rectangle step which looks at every unique rectangle.
+func createStepUniqueRectangle(technique string, firstCost int, subsequentCost int, remove func(solver *Solver, step *SolveStep, ur uniqueRectangle) int) *SolveStep {
+	return CreateStepUniqueness(technique, firstCost, subsequentCost, func(solver *Solver, limits SolveLimit, step *SolveStep) (int, int) {
+		removed := 0
+		for _, ur := range getUniqueRectangles(solver) {
+			removed += remove(solver, step, ur)
+
+			if !solver.CanContinueStep(limits, step) {
+				break
+			}
+		}
+		return 0, removed
+	})
+}
+
+// Three cells only have a and b, so the fourth can't be a or b.
+var StepUniqueRectangle1 = createStepUniqueRectangle("Unique Rectangle Type 1", 2400, 1400, func(solver *Solver, step *SolveStep, ur uniqueRectangle) int {
+	if len(ur.floor) != 3 {
+		return 0
+	}
+	cell := ur.cells[ur.roof[0]]
+	return removeCandidateNodes(solver, step, []candidateNode{{cell, ur.a}, {cell, ur.b}}, ur.pattern(Candidates{}))
+})
+
+// The cells which aren't only a and b all have one extra candidate c, so c must be in one of them and can be
+// removed from any cell that sees them all. Type 2 has two extra cells which share a house and type 5 has
+// two diagonal or three extra cells.
+func createStepUniqueRectangleExtra(technique string, firstCost int, subsequentCost int, matches func(ur uniqueRectangle) bool) *SolveStep {
+	return createStepUniqueRectangle(technique, firstCost, subsequentCost, func(solver *Solver, step *SolveStep, ur uniqueRectangle) int {
+		if len(ur.roof) < 2 || !matches(ur) {
+			return 0
+		}
+		extra := ur.extras(ur.roof[0])
+		holders := make([]*Cell, 0, len(ur.roof))
+		for _, i := range ur.roof {
+			if extra.Count != 1 || !extra.Equals(ur.extras(i)) {
+				return 0
+			}
+			holders = append(holders, ur.cells[i])
+		}
+		return removeCandidateSeenByAll(solver, step, extra.First(), holders, ur.pattern(extra))
+	})
+}
+
+var StepUniqueRectangle2 = createStepUniqueRectangleExtra("Unique Rectangle Type 2", 2600, 1600, func(ur uniqueRectangle) bool {
+	return ur.adjacentRoof()
+})
+
+var StepUniqueRectangle5 = createStepUniqueRectangleExtra("Unique Rectangle Type 5", 2800, 1800, func(ur uniqueRectangle) bool {
+	return len(ur.roof) == 3 || ur.diagonalRoof()
+})
+
+// Two cells which share a house only have a and b, one of the other two cells must have one of their
+// extra candidates. Those extra candidates act as one cell in a naked subset with other cells in a house
+// shared by the two cells, and the subset's candidates can be removed from the rest of the house.
+var StepUniqueRectangle3 = createStepUniqueRectangle("Unique Rectangle Type 3", 2800, 1800, func(solver *Solver, step *SolveStep, ur uniqueRectangle) int {
+	if !ur.adjacentRoof() {
+		return 0
+	}
+	extras := ur.extras(ur.roof[0])
+	extras.Or(ur.extras(ur.roof[1]))
+
+	removed := 0
+	for _, house := range ur.roofHouses(solver) {
+		subset := make([]*Cell, 0, 3)
+
+		var chooseCell func(start int, candidates Candidates) bool
+		chooseCell = func(start int, candidates Candidates) bool {
+			if len(subset) > 0 && candidates.Count == len(subset)+1 {
+				eliminations := make([]candidateNode, 0)
+				for _, cell := range house {
+					if sliceIndex(subset, func(s *Cell) bool { return s == cell }) != -1 {
+						continue
+					}
+					for _, candidate := range candidates.ToSlice() {
+						if cell.HasCandidate(candidate) {
+							eliminations = append(eliminations, candidateNode{cell, candidate})
+						}
+					}
+				}
+				pattern := append(ur.pattern(extras), getCandidateNodes(subset, candidates)...)
+				removed += removeCandidateNodes(solver, step, eliminations, pattern)
+				return removed == 0
+			}
+			if len(subset) == 3 {
+				return true
+			}
+			for i := start; i < len(house); i++ {
+				next := candidates
+				next.Or(house[i].candidates)
+				if next.Count > 4 {
+					continue
+				}
+				subset = append(subset, house[i])
+				searching := chooseCell(i+1, next)
+				subset = sliceRemoveLast(subset)
+				if !searching {
+					return false
+				}
+			}
+			return true
+		}
+
+		chooseCell(0, extras)
+
+		if removed > 0 {
+			break
+		}
+	}
+	return removed
+})
+
+// Two cells which share a house only have a and b. If the other two cells are the only cells in a house
+// with a then they both must be a, since either being b would be a deadly pattern, and b can be removed
+// from them.
+var StepUniqueRectangle4 = createStepUniqueRectangle("Unique Rectangle Type 4", 2600, 1600, func(solver *Solver, step *SolveStep, ur uniqueRectangle) int {
+	if len(ur.floor) != 2 || !ur.adjacentRoof() {
+		return 0
+	}
+	for _, house := range ur.roofHouses(solver) {
+		for _, pair := range [][2]int{{ur.a, ur.b}, {ur.b, ur.a}} {
+			if countCandidate(house, pair[0]) == 0 {
+				first := ur.cells[ur.roof[0]]
+				second := ur.cells[ur.roof[1]]
+				return removeCandidateNodes(solver, step, []candidateNode{{first, pair[1]}, {second, pair[1]}}, ur.pattern(Candidates{}))
+			}
+		}
+	}
+	return 0
+})
+
+// Two diagonal cells only have a and b. If a is only in the rectangle in both of its rows (or columns) then
+// a must be in the two diagonal cells, since a being in the other two cells would be a deadly pattern, so a
+// can be removed from the other two cells.
+var StepUniqueRectangle6 = createStepUniqueRectangle("Unique Rectangle Type 6", 2800, 1800, func(solver *Solver, step *SolveStep, ur uniqueRectangle) int {
+	if len(ur.floor) != 2 || !ur.diagonalRoof() {
+		return 0
+	}
+	for _, candidate := range []int{ur.a, ur.b} {
+		for _, group := range []Group{GroupRow, GroupCol} {
+			if countCandidate(solver.Group(group, ur.cells[0]), candidate) == 2 && countCandidate(solver.Group(group, ur.cells[3]), candidate) == 2 {
+				first := ur.cells[ur.roof[0]]
+				second := ur.cells[ur.roof[1]]
+				return removeCandidateNodes(solver, step, []candidateNode{{first, candidate}, {second, candidate}}, ur.pattern(Candidates{}))
+			}
+		}
+	}
+	return 0
+})
+
+// ==================================================
+// Step: Hidden Rectangle
+//		http://hodoku.sourceforge.net/en/tech_ur.php#hr
+// ==================================================
+// A cell only has a and b. If the diagonal cell is the only cell in the rectangle's row and column with a
+// apart from the rectangle, then the diagonal cell must be a, because b would force a deadly pattern.
+var StepHiddenRectangle = createStepUniqueRectangle("Hidden Rectangle", 2800, 1800, func(solver *Solver, step *SolveStep, ur uniqueRectangle) int {
+	if len(ur.floor) > 2 {
+		return 0
+	}
+	for _, floor := range ur.floor {
+		diagonal := rectangleDiagonalOf(floor)
+		cell := ur.cells[diagonal]
+		if cell.candidates.Count == 2 {
+			continue
+		}
+		for _, pair := range [][2]int{{ur.a, ur.b}, {ur.b, ur.a}} {
+			if countCandidate(solver.Row(cell.Row), pair[0]) == 2 && countCandidate(solver.Col(cell.Col), pair[0]) == 2 {
+				return removeCandidateNodes(solver, step, []candidateNode{{cell, pair[1]}}, ur.pattern(Candidates{}))
+			}
+		}
+	}
+	return 0
+})
+
+// ==================================================
+// Step: Avoidable Rectangle
+//		http://hodoku.sourceforge.net/en/tech_ur.php#ar
+// ==================================================
+// Like unique rectangles but with cells that were solved (not given). Type 1: three solved cells where the
+// two which share a house with the fourth cell have the same value b and the diagonal cell has the value a,
+// so the fourth cell can't be a. Type 2: two solved cells a and b which share a house, the cell sharing a
+// house with a only has b and c and the cell sharing a house with b only has a and c, so c must be in one of
+// them and can be removed from any cell which sees them both.
+var StepAvoidableRectangle = CreateStepUniqueness("Avoidable Rectangle", 2600, 1600, func(solver *Solver, limits SolveLimit, step *SolveStep) (int, int) {
+	removed := 0
+	rectangles := getRectangles(solver, func(r rectangle) bool {
+		solved := 0
+		for _, cell := range r {
+			if solver.IsGiven(cell) {
+				return false
+			}
+			if cell.HasValue() {
+				solved++
+			}
+		}
+		return solved >= 2
+	})
+
+	for _, r := range rectangles {
+		// The values of the solved cells.
+		pattern := make([]candidateNode, 0, len(r))
+		for _, cell := range r {
+			if cell.HasValue() {
+				pattern = append(pattern, candidateNode{cell, cell.Value})
+			}
+		}
+
+		for i, cell := range r {
+			if cell.HasValue() {
+				continue
+			}
+			row := r[rectangleRowOf(i)]
+			col := r[rectangleColOf(i)]
+			diagonal := r[rectangleDiagonalOf(i)]
+			if !diagonal.HasValue() {
+				continue
+			}
+
+			if row.HasValue() && col.HasValue() && row.Value == col.Value && cell.HasCandidate(diagonal.Value) {
+				removed += removeCandidateNodes(solver, step, []candidateNode{{cell, diagonal.Value}}, pattern)
+			}
+			for _, mates := range [][2]*Cell{{row, col}, {col, row}} {
+				unsolved, solved := mates[0], mates[1]
+				if unsolved.HasValue() || !solved.HasValue() || cell.candidates.Count != 2 || unsolved.candidates.Count != 2 {
+					continue
+				}
+				a, b := diagonal.Value, solved.Value
+				if !cell.HasCandidate(a) || !unsolved.HasCandidate(b) {
+					continue
+				}
+				extra := cell.candidates
+				extra.Set(a, false)
+				c := extra.First()
+				if c != b && unsolved.HasCandidate(c) {
+					nodes := append(getCandidateNodes([]*Cell{cell, unsolved}, cell.candidates), candidateNode{unsolved, b})
+					removed += removeCandidateSeenByAll(solver, step, c, []*Cell{cell, unsolved}, append(sliceClone(pattern), nodes...))
+				}
+			}
+
+			if !solver.CanContinueStep(limits, step) {
+				return 0, removed
+			}
+		}
+	}
+
+	return 0, removed
+})
+
+// ==================================================
+// Step: BUG+1
+//		http://hodoku.sourceforge.net/en/tech_ur.php#bug
+// ==================================================
+// Every unsolved cell has two candidates except one with three, which is a bivalue universal grave (every
+// candidate in every house twice) plus one. Without the candidate which is in that cell's houses three times
+// the puzzle would have two solutions, so the cell must be that candidate.
+var StepBUG1 = CreateStepUniqueness("BUG+1", 2200, 1200, func(solver *Solver, limits SolveLimit, step *SolveStep) (int, int) {
+	if len(solver.Puzzle.Kind.Constraints) > 0 || len(solver.Houses) > 0 || len(solver.Puzzle.Kind.Links) > 0 {
+		return 0, 0
+	}
+	var triple *Cell
+	for _, cell := range solver.Unsolved {
+		if cell.candidates.Count == 3 && triple == nil {
+			triple = cell
+		} else if cell.candidates.Count != 2 {
+			return 0, 0
+		}
+	}
+	if triple == nil {
+		return 0, 0
+	}
+	for _, candidate := range triple.Candidates() {
+		if countCandidate(solver.Row(triple.Row), candidate) == 3 && countCandidate(solver.Col(triple.Col), candidate) == 3 && countCandidate(solver.Box(triple.Box), candidate) == 3 {
+			solver.LogStep(step)
+			solver.LogPattern([]*Cell{triple}, triple.Candidates()...)
+			solver.LogBefore(triple)
+			solver.SetCell(triple, candidate)
+			solver.LogPlacement(triple)
+			return 1, 0
+		}
+	}
+	return 0, 0
+})
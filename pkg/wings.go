@@ -0,0 +1,240 @@
+package sudogo
+
+// ==================================================
+// Step: XY-Wing, XYZ-Wing & WXYZ-Wing
+//		http://hodoku.sourceforge.net/en/tech_wings.php
+// ==================================================
+func CreateStepWing(cellCount int, technique string, firstCost int, subsequentCost int, matches func(cells []*Cell) bool) *SolveStep {
+	return &SolveStep{
+		Technique:      technique,
+		FirstCost:      firstCost,
+		SubsequentCost: subsequentCost,
+		Logic: func(solver *Solver, limits SolveLimit, step *SolveStep) (int, bool) {
+			removed := false
+			if solver.CanContinueStep(limits, step) {
+				removed = doWing(solver, limits, step, cellCount, matches) > 0
+			}
+			return 0, removed
+		},
+	}
+}
+
+// Three bivalue cells where a pivot sees two pincers: {x,y} {x,z} {y,z}.
+var StepXYWing = CreateStepWing(3, "XY-Wing", 3200, 2000, func(cells []*Cell) bool {
+	for _, cell := range cells {
+		if cell.candidates.Count != 2 {
+			return false
+		}
+	}
+	return true
+})
+
+// A pivot {x,y,z} which sees two bivalue pincers: {x,z} {y,z}.
+var StepXYZWing = CreateStepWing(3, "XYZ-Wing", 3600, 2400, func(cells []*Cell) bool {
+	pivots := 0
+	for _, cell := range cells {
+		if cell.candidates.Count == 3 {
+			pivots++
+		} else if cell.candidates.Count != 2 {
+			return false
+		}
+	}
+	return pivots == 1
+})
+
+// Four cells with four candidates between them.
+var StepWXYZWing = CreateStepWing(4, "WXYZ-Wing", 5600, 4000, func(cells []*Cell) bool {
+	return true
+})
+
+// Finds a set of cells that share a candidate with another cell in the set which have as many
+// candidates between them as there are cells. When every candidate but one (z) can only be in one
+// of the cells (because all cells with that candidate see each other) then z must be in one of the
+// cells. Any cell that sees all the cells with z can have z removed.
+func doWing(solver *Solver, limits SolveLimit, step *SolveStep, cellCount int, matches func(cells []*Cell) bool) int {
+	removed := 0
+	available := make([]*Cell, 0, len(solver.Unsolved))
+	for _, cell := range solver.Unsolved {
+		if cell.candidates.Count >= 2 && cell.candidates.Count <= cellCount {
+			available = append(available, cell)
+		}
+	}
+
+	cells := make([]*Cell, 0, cellCount)
+
+	var chooseCell func(start int, candidates Candidates) bool
+	chooseCell = func(start int, candidates Candidates) bool {
+		if len(cells) == cellCount {
+			if candidates.Count != cellCount || !isConnectedWing(cells) || !matches(cells) {
+				return true
+			}
+			z := getWingUnrestricted(cells, candidates)
+			if z == 0 {
+				return true
+			}
+			holders := make([]*Cell, 0, cellCount)
+			for _, cell := range cells {
+				if cell.HasCandidate(z) {
+					holders = append(holders, cell)
+				}
+			}
+			removed += removeCandidateSeenByAll(solver, step, z, holders, getCandidateNodes(cells, candidates))
+
+			return solver.CanContinueStep(limits, step)
+		}
+		for i := start; i <= len(available)-(cellCount-len(cells)); i++ {
+			cell := available[i]
+			next := candidates
+			next.Or(cell.candidates)
+			if next.Count > cellCount {
+				continue
+			}
+			cells = append(cells, cell)
+			completed := chooseCell(i+1, next)
+			cells = sliceRemoveLast(cells)
+			if !completed {
+				return false
+			}
+		}
+		return true
+	}
+
+	chooseCell(0, Candidates{})
+
+	return removed
+}
+
+// Returns whether every cell in the wing is connected to the rest through cells that see each other
+// and share a candidate.
+func isConnectedWing(cells []*Cell) bool {
+	connected := Bitset{}
+	connected.Set(0, true)
+	for changed := true; changed; {
+		changed = false
+		for i, cell := range cells {
+			if connected.Has(i) {
+				continue
+			}
+			for j, other := range cells {
+				if connected.Has(j) && cell.InGroup(other) && cell.candidates.Overlaps(other.candidates) {
+					connected.Set(i, true)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	return connected.Count == len(cells)
+}
+
+// Returns the only candidate in the wing which is in two cells that don't see each other, or 0 if
+// there is no such candidate or there are more than one.
+func getWingUnrestricted(cells []*Cell, candidates Candidates) int {
+	unrestricted := 0
+	for _, candidate := range candidates.ToSlice() {
+		restricted := true
+		for i, a := range cells {
+			for _, b := range cells[i+1:] {
+				if a.HasCandidate(candidate) && b.HasCandidate(candidate) && !a.InGroup(b) {
+					restricted = false
+				}
+			}
+		}
+		if !restricted {
+			if unrestricted != 0 {
+				return 0
+			}
+			unrestricted = candidate
+		}
+	}
+	return unrestricted
+}
+
+// ==================================================
+// Step: W-Wing
+//		http://hodoku.sourceforge.net/en/tech_wings.php#w
+// ==================================================
+var StepWWing = &SolveStep{
+	Technique:      "W-Wing",
+	FirstCost:      3000,
+	SubsequentCost: 1800,
+	Logic: func(solver *Solver, limits SolveLimit, step *SolveStep) (int, bool) {
+		removed := false
+		if solver.CanContinueStep(limits, step) {
+			removed = doWWing(solver, limits, step) > 0
+		}
+		return 0, removed
+	},
+}
+
+// Find two cells with the same two candidates {x,y} that don't see each other. If there is a strong link
+// on x (a house where x can only be in two cells) where one end sees the first cell and the other end sees
+// the second cell, then one of the two cells must be y. Any cell that sees both cells can have y removed.
+func doWWing(solver *Solver, limits SolveLimit, step *SolveStep) int {
+	removed := 0
+
+	bivalues := make([]*Cell, 0)
+	for _, cell := range solver.Unsolved {
+		if cell.candidates.Count == 2 {
+			bivalues = append(bivalues, cell)
+		}
+	}
+
+	links := getStrongLinks(solver)
+
+	for i, a := range bivalues {
+		for _, b := range bivalues[i+1:] {
+			if !a.candidates.Equals(b.candidates) || a.InGroup(b) {
+				continue
+			}
+			x := a.candidates.First()
+			y := a.candidates.Last()
+			for k := 0; k < 2; k++ {
+				for _, link := range links[x] {
+					if link.has(a) || link.has(b) {
+						continue
+					}
+					if (link.first.InGroup(a) && link.second.InGroup(b)) || (link.first.InGroup(b) && link.second.InGroup(a)) {
+						pattern := append(getCandidateNodes([]*Cell{a, b}, a.candidates), candidateNode{link.first, x}, candidateNode{link.second, x})
+						removed += removeCandidateSeenByAll(solver, step, y, []*Cell{a, b}, pattern)
+
+						if !solver.CanContinueStep(limits, step) {
+							return removed
+						}
+						break
+					}
+				}
+				x, y = y, x
+			}
+		}
+	}
+
+	return removed
+}
+
+// Two cells which are the only cells in a house with a candidate, if one is not the candidate the other is.
+type strongLink struct {
+	first  *Cell
+	second *Cell
+}
+
+func (link strongLink) has(cell *Cell) bool {
+	return link.first == cell || link.second == cell
+}
+
+// Returns the strong links for each candidate (by candidate) in every column, row, and box.
+func getStrongLinks(solver *Solver) [][]strongLink {
+	size := solver.Puzzle.Kind.Size()
+	links := make([][]strongLink, size+1)
+	for _, group := range []Group{GroupCol, GroupRow, GroupBox} {
+		for _, dist := range getGroupCandidateDistributions(solver, group) {
+			for _, candidateCells := range dist.candidates {
+				if candidateCells.size == 2 {
+					link := strongLink{candidateCells.cells[0], candidateCells.cells[1]}
+					links[candidateCells.candidate] = append(links[candidateCells.candidate], link)
+				}
+			}
+		}
+	}
+	return links
+}